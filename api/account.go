@@ -1,45 +1,51 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/shopspring/decimal"
 )
 
+var errAccountNotOwned = newAPIError(http.StatusForbidden, "account_not_owned", "account doesn't belong to the authenticated user")
+
 type createAccountRequest struct {
-	Owner    string      `json:"owner" binding:"required"`
-	Currency db.Currency `json:"currency" binding:"required"`
+	Currency db.Currency `json:"currency" binding:"required,currency"`
 }
 
 func (server *Server) createAccountHandler(ctx *gin.Context) {
 	var req createAccountRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if _, ok := server.checkCurrency(ctx, req.Currency); !ok {
 		return
 	}
 
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	arg := db.CreateAccountParams{
-		Owner:    req.Owner,
+		Owner:    authPayload.Username,
 		Balance:  decimal.NewFromInt(0),
 		Currency: req.Currency,
 	}
 
-	account, err := server.store.CreateAccount(ctx, arg)
+	account, err := server.store.CreateAccountTx(ctx, arg)
 	if err != nil {
-		if pgErr, ok := err.(*pgconn.PgError); ok {
-			switch pgErr.Code {
-			case "23503", "23505":
-				ctx.JSON(http.StatusForbidden, errorResponse(pgErr))
-				return
-			}
+		// The user doesn't exist or already has an account in the currency
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && (pgErr.Code == pgForeignKeyViolation || pgErr.Code == pgUniqueViolation) {
+			errorResponseWithStatus(ctx, http.StatusForbidden, err)
+			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, err)
 		return
 	}
 
@@ -50,26 +56,36 @@ type getAccountRequest struct {
 	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
+// getAccountResponse is the account with the amount it can spend,
+// which is what a transfer is checked against
+type getAccountResponse struct {
+	db.Account
+	AvailableBalance decimal.Decimal `json:"available_balance"`
+}
+
 func (server *Server) getAccountHandler(ctx *gin.Context) {
 	var req getAccountRequest
 
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, invalidRequest(err))
 		return
 	}
 
-	account, err := server.store.GetAccount(ctx, req.ID)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
+	account, ok := server.getOwnedAccount(ctx, req.ID)
+	if !ok {
+		return
+	}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	heldAmount, err := server.store.GetAccountHeldAmount(ctx, account.ID)
+	if err != nil {
+		errorResponse(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	ctx.JSON(http.StatusOK, getAccountResponse{
+		Account:          account,
+		AvailableBalance: db.AvailableBalance(account, heldAmount),
+	})
 }
 
 type listAccountsRequest struct {
@@ -81,76 +97,72 @@ func (server *Server) listAccountsHandler(ctx *gin.Context) {
 	var req listAccountsRequest
 
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, invalidRequest(err))
 		return
 	}
 
-	arg := db.ListAccountsParams{
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.ListAccountsByOwnerParams{
+		Owner:  authPayload.Username,
 		Limit:  req.PageSize,
 		Offset: (req.PageId - 1) * req.PageSize,
 	}
 
-	accounts, err := server.store.ListAccounts(ctx, arg)
+	accounts, err := server.store.ListAccountsByOwner(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, accounts)
 }
 
-type updateAccountRequest struct {
-	ID      int64           `json:"id" binding:"required,min=1"`
-	Balance decimal.Decimal `json:"balance" binding:"required"`
+type closeAccountRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
 }
 
-func (server *Server) updateAccountsHandler(ctx *gin.Context) {
-	var req updateAccountRequest
+// closeAccountHandler closes an account instead of deleting it,
+// so that its entries and transfers stay in the ledger
+func (server *Server) closeAccountHandler(ctx *gin.Context) {
+	var req closeAccountRequest
 
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
 		return
 	}
 
-	arg := db.UpdateAccountParams{
-		ID:      req.ID,
-		Balance: req.Balance,
+	if _, ok := server.getOwnedAccount(ctx, req.ID); !ok {
+		return
 	}
 
-	account, err := server.store.UpdateAccount(ctx, arg)
+	account, err := server.store.CloseAccountTx(ctx, req.ID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+		if errors.Is(err, db.ErrAccountFrozen) {
+			errorResponseWithStatus(ctx, http.StatusConflict, err)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, account)
 }
 
-type deleteAccountRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
-}
-
-func (server *Server) deleteAccountsHandler(ctx *gin.Context) {
-	var req deleteAccountRequest
-
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	err := server.store.DeleteAccount(ctx, req.ID)
+// getOwnedAccount fetches the account and makes sure it belongs to the
+// authenticated user. It writes the error response and returns false otherwise.
+func (server *Server) getOwnedAccount(ctx *gin.Context, accountId int64) (db.Account, bool) {
+	account, err := server.store.GetAccount(ctx, accountId)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
+		errorResponse(ctx, err)
+		return account, false
+	}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if account.Owner != authPayload.Username {
+		errorResponse(ctx, errAccountNotOwned)
+		return account, false
 	}
+
+	return account, true
 }
@@ -9,9 +9,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
 	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
 	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
 	"github.com/jackc/pgx/v5"
 	"github.com/shopspring/decimal"
@@ -21,30 +23,106 @@ import (
 
 func TestGetAccountApi(t *testing.T) {
 	account := createRandomAccount()
+	account.OverdraftLimit = decimal.NewFromInt(50)
+
+	heldAmount := decimal.RequireFromString("12.34")
 
 	testCases := []struct {
 		name             string
 		accountId        int64
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubFunc    func(store *mockdb.MockStore)
 		validateResponse func(recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name:      "OK",
 			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
 					Times(1).
 					Return(account, nil)
+
+				store.EXPECT().
+					GetAccountHeldAmount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(heldAmount, nil)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, recorder.Code)
-				assertAccount(t, account, recorder.Body)
+
+				var response getAccountResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, account, response.Account)
+
+				// The balance and the overdraft limit, minus the active holds
+				expected := account.Balance.Add(account.OverdraftLimit).Sub(heldAmount)
+				assert.True(t, expected.Equal(response.AvailableBalance))
+			},
+		},
+		{
+			name:      "HeldAmountError",
+			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					GetAccountHeldAmount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(decimal.Decimal{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+		{
+			name:      "UnauthorizedUser",
+			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "otheruser", utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, errAccountNotOwned, recorder.Body)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
 		{
 			name:      "NotFoundError",
 			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
@@ -59,6 +137,9 @@ func TestGetAccountApi(t *testing.T) {
 		{
 			name:      "InternalServerError",
 			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
@@ -73,6 +154,9 @@ func TestGetAccountApi(t *testing.T) {
 		{
 			name:      "BadRequest",
 			accountId: 0,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetAccount(gomock.Any(), gomock.Any()).
@@ -97,13 +181,14 @@ func TestGetAccountApi(t *testing.T) {
 			tc.buildStubFunc(store)
 
 			// start test server and send http request
-			server := NewServer(store)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
 			url := fmt.Sprintf("/api/accounts/%d", tc.accountId)
 			request, err := http.NewRequest(http.MethodGet, url, nil)
 			assert.NoError(t, err)
 
+			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
 			tc.validateResponse(recorder)
 		})
@@ -116,12 +201,16 @@ func TestCreateAccountApi(t *testing.T) {
 	testCases := []struct {
 		name             string
 		account          db.Account
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubFunc    func(store *mockdb.MockStore)
 		validateResponse func(recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name:    "OK",
 			account: account,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				arg := db.CreateAccountParams{
 					Owner:    account.Owner,
@@ -130,7 +219,7 @@ func TestCreateAccountApi(t *testing.T) {
 				}
 
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Eq(arg)).
+					CreateAccountTx(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
 					Return(account, nil)
 			},
@@ -139,9 +228,26 @@ func TestCreateAccountApi(t *testing.T) {
 				assertAccount(t, account, recorder.Body)
 			},
 		},
+		{
+			name:    "NoAuthorization",
+			account: account,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 		{
 			name:    "InternalServerError",
 			account: account,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				arg := db.CreateAccountParams{
 					Owner:    account.Owner,
@@ -150,7 +256,7 @@ func TestCreateAccountApi(t *testing.T) {
 				}
 
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Eq(arg)).
+					CreateAccountTx(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
 					Return(db.Account{}, pgx.ErrTxClosed)
 			},
@@ -162,13 +268,48 @@ func TestCreateAccountApi(t *testing.T) {
 		{
 			name:    "BadRequest",
 			account: db.Account{}, // empty account
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "UnsupportedCurrency",
+			account: db.Account{Currency: "XYZ"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateAccount(gomock.Any(), gomock.Any()).
+					CreateAccountTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errUnsupportedCurrency, recorder.Body)
+			},
+		},
+		{
+			name:    "DisabledCurrency",
+			account: db.Account{Currency: "GBP"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errUnsupportedCurrency, recorder.Body)
 			},
 		},
 	}
@@ -184,12 +325,11 @@ func TestCreateAccountApi(t *testing.T) {
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubFunc(store)
 
-			server := NewServer(store)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
 			url := "/api/accounts"
 			req := createAccountRequest{
-				Owner:    tc.account.Owner,
 				Currency: tc.account.Currency,
 			}
 			body, err := json.Marshal(req)
@@ -198,160 +338,175 @@ func TestCreateAccountApi(t *testing.T) {
 			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
 			assert.NoError(t, err)
 
+			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
 			tc.validateResponse(recorder)
 		})
 	}
 }
 
-func TestUpdateAccountApi(t *testing.T) {
+func TestCloseAccountApi(t *testing.T) {
 	account := createRandomAccount()
+	account.Balance = decimal.NewFromInt(0)
+
+	closedAccount := account
+	closedAccount.Status = db.AccountStatusClosed
 
 	testCases := []struct {
 		name             string
-		account          db.Account
+		accountId        int64
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubFunc    func(store *mockdb.MockStore)
 		validateResponse func(recorder *httptest.ResponseRecorder)
 	}{
 		{
-			name:    "OK",
-			account: account,
+			name:      "OK",
+			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
-				arg := db.UpdateAccountParams{
-					ID:      account.ID,
-					Balance: account.Balance,
-				}
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
 
 				store.
 					EXPECT().
-					UpdateAccount(gomock.Any(), gomock.Eq(arg)).
+					CloseAccountTx(gomock.Any(), gomock.Eq(account.ID)).
 					Times(1).
-					Return(account, nil)
+					Return(closedAccount, nil)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, recorder.Code)
-				assertAccount(t, account, recorder.Body)
+				assertAccount(t, closedAccount, recorder.Body)
 			},
 		},
 		{
-			name:    "InternalServerError",
-			account: account,
+			name:      "UnauthorizedUser",
+			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "otheruser", utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
-				arg := db.UpdateAccountParams{
-					ID:      account.ID,
-					Balance: account.Balance,
-				}
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
 
 				store.
 					EXPECT().
-					UpdateAccount(gomock.Any(), gomock.Eq(arg)).
-					Times(1).
-					Return(db.Account{}, pgx.ErrTxClosed)
+					CloseAccountTx(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
-				assertError(t, pgx.ErrTxClosed, recorder.Body)
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, errAccountNotOwned, recorder.Body)
 			},
 		},
 		{
-			name:    "NotFound",
-			account: account,
+			name:      "NonZeroBalance",
+			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
-				arg := db.UpdateAccountParams{
-					ID:      account.ID,
-					Balance: account.Balance,
-				}
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
 
 				store.
 					EXPECT().
-					UpdateAccount(gomock.Any(), gomock.Eq(arg)).
+					CloseAccountTx(gomock.Any(), gomock.Eq(account.ID)).
 					Times(1).
-					Return(db.Account{}, pgx.ErrNoRows)
+					Return(db.Account{}, &db.NonZeroBalanceError{
+						AccountID: account.ID,
+						Balance:   decimal.RequireFromString("12.5"),
+					})
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusNotFound, recorder.Code)
-				assertError(t, pgx.ErrNoRows, recorder.Body)
+				assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				var body APIError
+				err := json.Unmarshal(recorder.Body.Bytes(), &body)
+				assert.NoError(t, err)
+				assert.Equal(t, "12.5", body.Details["balance"])
 			},
 		},
 		{
-			name:    "BadRequest",
-			account: db.Account{},
+			name:      "ActiveHolds",
+			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
 				store.
 					EXPECT().
-					UpdateAccount(gomock.Any(), gomock.Any()).
-					Times(0)
+					CloseAccountTx(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.Account{}, &db.ActiveHoldsError{
+						AccountID:  account.ID,
+						HeldAmount: decimal.RequireFromString("7.5"),
+					})
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				var body APIError
+				err := json.Unmarshal(recorder.Body.Bytes(), &body)
+				assert.NoError(t, err)
+				assert.Equal(t, "active_holds", body.Code)
+				assert.Equal(t, "7.5", body.Details["held_amount"])
 			},
 		},
-	}
-
-	for i := range testCases {
-		tc := testCases[i]
-
-		t.Run(tc.name, func(t *testing.T) {
-
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-			tc.buildStubFunc(store)
-
-			server := NewServer(store)
-			recorder := httptest.NewRecorder()
-
-			req := updateAccountRequest{
-				ID:      tc.account.ID,
-				Balance: tc.account.Balance,
-			}
-
-			body, err := json.Marshal(req)
-			assert.NoError(t, err)
-
-			url := "/api/accounts"
-			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
-			assert.NoError(t, err)
-
-			server.router.ServeHTTP(recorder, request)
-			tc.validateResponse(recorder)
-		})
-	}
-}
-
-func TestDeleteAccountApi(t *testing.T) {
-	account := createRandomAccount()
-
-	testCases := []struct {
-		name             string
-		accountId        int64
-		buildStubFunc    func(store *mockdb.MockStore)
-		validateResponse func(recorder *httptest.ResponseRecorder)
-	}{
 		{
-			name:      "OK",
+			name:      "AlreadyClosed",
 			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(closedAccount, nil)
+
 				store.
 					EXPECT().
-					DeleteAccount(gomock.Any(), gomock.Eq(account.ID)).
+					CloseAccountTx(gomock.Any(), gomock.Eq(account.ID)).
 					Times(1).
-					Return(nil)
+					Return(db.Account{}, &db.AccountNotActiveError{
+						AccountID: account.ID,
+						Status:    db.AccountStatusClosed,
+					})
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, http.StatusConflict, recorder.Code)
 			},
 		},
 		{
 			name:      "InternalServerError",
 			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
 				store.
 					EXPECT().
-					DeleteAccount(gomock.Any(), gomock.Eq(account.ID)).
+					CloseAccountTx(gomock.Any(), gomock.Eq(account.ID)).
 					Times(1).
-					Return(pgx.ErrTxClosed)
+					Return(db.Account{}, pgx.ErrTxClosed)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -361,12 +516,19 @@ func TestDeleteAccountApi(t *testing.T) {
 		{
 			name:      "NotFound",
 			accountId: account.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.Account{}, pgx.ErrNoRows)
+
 				store.
 					EXPECT().
-					DeleteAccount(gomock.Any(), gomock.Eq(account.ID)).
-					Times(1).
-					Return(pgx.ErrNoRows)
+					CloseAccountTx(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusNotFound, recorder.Code)
@@ -376,10 +538,13 @@ func TestDeleteAccountApi(t *testing.T) {
 		{
 			name:      "BadRequest",
 			accountId: 0,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.
 					EXPECT().
-					DeleteAccount(gomock.Any(), gomock.Any()).
+					CloseAccountTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
@@ -398,13 +563,14 @@ func TestDeleteAccountApi(t *testing.T) {
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubFunc(store)
 
-			server := NewServer(store)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			url := fmt.Sprintf("/api/accounts/%d", tc.accountId)
-			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			url := fmt.Sprintf("/api/accounts/%d/close", tc.accountId)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
 			assert.NoError(t, err)
 
+			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
 			tc.validateResponse(recorder)
 		})
@@ -412,11 +578,13 @@ func TestDeleteAccountApi(t *testing.T) {
 }
 
 func TestListAccountsApi(t *testing.T) {
+	owner := utils.RandomString(6)
 	n := 5
 
 	accounts := make([]db.Account, n)
 	for i := 0; i < 5; i++ {
 		accounts[i] = createRandomAccount()
+		accounts[i].Owner = owner
 	}
 
 	type Query struct {
@@ -427,6 +595,7 @@ func TestListAccountsApi(t *testing.T) {
 	testCases := []struct {
 		name             string
 		query            Query
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubFunc    func(store *mockdb.MockStore)
 		validateResponse func(recorder *httptest.ResponseRecorder)
 	}{
@@ -436,14 +605,18 @@ func TestListAccountsApi(t *testing.T) {
 				PageId:   1,
 				PageSize: 5,
 			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
-				arg := db.ListAccountsParams{
+				arg := db.ListAccountsByOwnerParams{
+					Owner:  owner,
 					Limit:  5,
 					Offset: 0,
 				}
 				store.
 					EXPECT().
-					ListAccounts(gomock.Any(), gomock.Eq(arg)).
+					ListAccountsByOwner(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
 					Return(accounts, nil)
 			},
@@ -452,20 +625,68 @@ func TestListAccountsApi(t *testing.T) {
 				assertAccounts(t, accounts, recorder.Body)
 			},
 		},
+		{
+			name: "OtherUserSeesOnlyOwnAccounts",
+			query: Query{
+				PageId:   1,
+				PageSize: 5,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "otheruser", utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.ListAccountsByOwnerParams{
+					Owner:  "otheruser",
+					Limit:  5,
+					Offset: 0,
+				}
+				store.
+					EXPECT().
+					ListAccountsByOwner(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Account{}, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assertAccounts(t, []db.Account{}, recorder.Body)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			query: Query{
+				PageId:   1,
+				PageSize: 5,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.
+					EXPECT().
+					ListAccountsByOwner(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 		{
 			name: "InternalServerError",
 			query: Query{
 				PageId:   1,
 				PageSize: 5,
 			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
-				arg := db.ListAccountsParams{
+				arg := db.ListAccountsByOwnerParams{
+					Owner:  owner,
 					Limit:  5,
 					Offset: 0,
 				}
 				store.
 					EXPECT().
-					ListAccounts(gomock.Any(), gomock.Eq(arg)).
+					ListAccountsByOwner(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
 					Return([]db.Account{}, pgx.ErrTxClosed)
 			},
@@ -480,10 +701,13 @@ func TestListAccountsApi(t *testing.T) {
 				PageId:   0,
 				PageSize: 5,
 			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.
 					EXPECT().
-					ListAccounts(gomock.Any(), gomock.Any()).
+					ListAccountsByOwner(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
@@ -502,7 +726,7 @@ func TestListAccountsApi(t *testing.T) {
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubFunc(store)
 
-			server := NewServer(store)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
 			//url := fmt.Sprintf("/api/accounts?page_id={%d}&page_size=%d", 1, 1)
@@ -515,6 +739,7 @@ func TestListAccountsApi(t *testing.T) {
 
 			assert.NoError(t, err)
 
+			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
 			tc.validateResponse(recorder)
 		})
@@ -523,10 +748,12 @@ func TestListAccountsApi(t *testing.T) {
 
 func createRandomAccount() db.Account {
 	return db.Account{
-		ID:       utils.RandomNumber(1, 1000),
-		Owner:    utils.RandomString(5),
-		Balance:  utils.RandomDecimal(10, 100),
-		Currency: randomCurrency(),
+		ID:             utils.RandomNumber(1, 1000),
+		Owner:          utils.RandomString(5),
+		Balance:        utils.RandomDecimal(10, 100),
+		Currency:       randomCurrency(),
+		OverdraftLimit: utils.RandomDecimal(10, 100),
+		Status:         db.AccountStatusActive,
 	}
 }
 
@@ -563,8 +790,11 @@ func assertError(t *testing.T, expectedErr error, body *bytes.Buffer) {
 	data, err := io.ReadAll(body)
 	assert.NoError(t, err)
 
-	var actualErr map[string]interface{}
+	var actualErr APIError
 	err = json.Unmarshal(data, &actualErr)
+	assert.NoError(t, err)
 
-	assert.Equal(t, errorResponse(expectedErr)["error"], actualErr["error"])
+	expected := apiErrorFrom(expectedErr)
+	assert.Equal(t, expected.Code, actualErr.Code)
+	assert.Equal(t, expected.Message, actualErr.Message)
 }
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+var errInvalidAdjustmentAmount = newAPIError(
+	http.StatusBadRequest,
+	"invalid_adjustment_amount",
+	"adjustment amount must be non zero and within the allowed range")
+
+type createAdjustmentUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// createAdjustmentRequest is a signed amount: positive to credit the account, negative to debit it
+type createAdjustmentRequest struct {
+	Amount     decimal.Decimal `json:"amount" binding:"required"`
+	ReasonCode string          `json:"reason_code" binding:"required,oneof=correction chargeback fee_refund goodwill write_off"`
+	Note       string          `json:"note" binding:"max=500"`
+}
+
+func (server *Server) createAdjustmentHandler(ctx *gin.Context) {
+	var uri createAdjustmentUri
+	var req createAdjustmentRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if req.Amount.IsZero() || req.Amount.Abs().GreaterThan(maxAmount) {
+		errorResponse(ctx, errInvalidAdjustmentAmount)
+		return
+	}
+
+	// The operator is the authenticated admin, never taken from the request body
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := db.AdjustBalanceTxParams{
+		AccountID:  uri.ID,
+		Amount:     req.Amount,
+		ReasonCode: req.ReasonCode,
+		Note:       req.Note,
+		Operator:   authPayload.Username,
+	}
+
+	result, err := server.store.AdjustBalanceTx(ctx, arg)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
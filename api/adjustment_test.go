@@ -0,0 +1,349 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateAdjustmentApi(t *testing.T) {
+	admin, _ := createRandomUser()
+	account := createRandomAccount()
+	amount := decimal.RequireFromString("-12.5")
+
+	result := db.AdjustBalanceTxResult{
+		Account: account,
+		Entry: db.Entry{
+			ID:        utils.RandomNumber(1, 1000),
+			AccountID: account.ID,
+			Amount:    amount,
+		},
+		Adjustment: db.BalanceAdjustment{
+			ID:         utils.RandomNumber(1, 1000),
+			AccountID:  account.ID,
+			Amount:     amount,
+			ReasonCode: "correction",
+			Note:       "duplicate card payment",
+			Operator:   admin.Name,
+		},
+	}
+	result.Account.Balance = account.Balance.Add(amount)
+
+	testCases := []struct {
+		name             string
+		accountId        int64
+		body             gin.H
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			accountId: account.ID,
+			body: gin.H{
+				"amount":      amount,
+				"reason_code": "correction",
+				"note":        "duplicate card payment",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.AdjustBalanceTxParams{
+					AccountID:  account.ID,
+					Amount:     amount,
+					ReasonCode: "correction",
+					Note:       "duplicate card payment",
+					Operator:   admin.Name,
+				}
+
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(result, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assertAdjustBalanceResult(t, result, recorder.Body)
+			},
+		},
+		{
+			name:      "NotAdmin",
+			accountId: account.ID,
+			body: gin.H{
+				"amount":      amount,
+				"reason_code": "correction",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			accountId: account.ID,
+			body: gin.H{
+				"amount":      amount,
+				"reason_code": "correction",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "MissingReasonCode",
+			accountId: account.ID,
+			body: gin.H{
+				"amount": amount,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "UnknownReasonCode",
+			accountId: account.ID,
+			body: gin.H{
+				"amount":      amount,
+				"reason_code": "because",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "ZeroAmount",
+			accountId: account.ID,
+			body: gin.H{
+				"amount":      "0",
+				"reason_code": "correction",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errInvalidAdjustmentAmount, recorder.Body)
+			},
+		},
+		{
+			name:      "TooLargeAmount",
+			accountId: account.ID,
+			body: gin.H{
+				"amount":      maxAmount.Add(decimal.NewFromInt(1)).Neg(),
+				"reason_code": "write_off",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errInvalidAdjustmentAmount, recorder.Body)
+			},
+		},
+		{
+			name:      "InvalidAmountPrecision",
+			accountId: account.ID,
+			body: gin.H{
+				"amount":      "1.001",
+				"reason_code": "goodwill",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdjustBalanceTxResult{}, &db.AmountPrecisionError{
+						Amount:   decimal.RequireFromString("1.001"),
+						Currency: account.Currency,
+						Exponent: 2,
+					})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "AccountNotFound",
+			accountId: account.ID,
+			body: gin.H{
+				"amount":      amount,
+				"reason_code": "correction",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdjustBalanceTxResult{}, pgx.ErrNoRows)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+				assertError(t, pgx.ErrNoRows, recorder.Body)
+			},
+		},
+		{
+			name:      "SystemAccount",
+			accountId: account.ID,
+			body: gin.H{
+				"amount":      amount,
+				"reason_code": "correction",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdjustBalanceTxResult{}, db.ErrSystemAccount)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, db.ErrSystemAccount, recorder.Body)
+			},
+		},
+		{
+			name:      "InternalServerError",
+			accountId: account.ID,
+			body: gin.H{
+				"amount":      amount,
+				"reason_code": "correction",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdjustBalanceTxResult{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+		{
+			name:      "BadRequest",
+			accountId: 0,
+			body: gin.H{
+				"amount":      amount,
+				"reason_code": "correction",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/accounts/%d/adjustments", tc.accountId)
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func assertAdjustBalanceResult(t *testing.T, expectedResult db.AdjustBalanceTxResult, body *bytes.Buffer) {
+	data, err := io.ReadAll(body)
+	assert.NoError(t, err)
+
+	var actualResult db.AdjustBalanceTxResult
+	err = json.Unmarshal(data, &actualResult)
+	assert.NoError(t, err)
+
+	assert.Equal(t, expectedResult.Account.ID, actualResult.Account.ID)
+	assert.True(t, expectedResult.Account.Balance.Equal(actualResult.Account.Balance))
+	assert.Equal(t, expectedResult.Entry.ID, actualResult.Entry.ID)
+	assert.True(t, expectedResult.Entry.Amount.Equal(actualResult.Entry.Amount))
+	assert.Equal(t, expectedResult.Adjustment.ID, actualResult.Adjustment.ID)
+	assert.Equal(t, expectedResult.Adjustment.ReasonCode, actualResult.Adjustment.ReasonCode)
+	assert.Equal(t, expectedResult.Adjustment.Operator, actualResult.Adjustment.Operator)
+}
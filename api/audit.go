@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/logging"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// auditMiddleware creates a gin middleware which attaches the authenticated user,
+// the request id and the client IP to the request context, so that the store records them
+// in the audit log with the changes made by the request. It must run after requestIDMiddleware,
+// and after authMiddleware on the routes which require a valid access token.
+func auditMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		info := db.AuditInfo{
+			RequestID: logging.RequestIDFrom(ctx.Request.Context()),
+			ClientIP:  ctx.ClientIP(),
+		}
+
+		if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+			info.Actor = payload.(*token.Payload).Username
+		}
+
+		ctx.Request = ctx.Request.WithContext(db.WithAuditInfo(ctx.Request.Context(), info))
+		ctx.Next()
+	}
+}
+
+type auditEventResponse struct {
+	ID           int64           `json:"id"`
+	Actor        string          `json:"actor"`
+	Action       string          `json:"action"`
+	ResourceType string          `json:"resource_type"`
+	ResourceID   string          `json:"resource_id"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	RequestID    string          `json:"request_id"`
+	ClientIP     string          `json:"client_ip"`
+	PrevHash     string          `json:"prev_hash"`
+	Hash         string          `json:"hash"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// newAuditEventResponse returns the states of the resource as JSON documents
+func newAuditEventResponse(event db.AuditEvent) auditEventResponse {
+	return auditEventResponse{
+		ID:           event.ID,
+		Actor:        event.Actor,
+		Action:       event.Action,
+		ResourceType: event.ResourceType,
+		ResourceID:   event.ResourceID,
+		Before:       nullableRawMessage(event.Before),
+		After:        nullableRawMessage(event.After),
+		RequestID:    event.RequestID,
+		ClientIP:     event.ClientIp,
+		PrevHash:     event.PrevHash,
+		Hash:         event.Hash,
+		CreatedAt:    event.CreatedAt.Time,
+	}
+}
+
+func nullableRawMessage(data []byte) json.RawMessage {
+	if data == nil {
+		return json.RawMessage("null")
+	}
+
+	return data
+}
+
+type listAuditEventsRequest struct {
+	Actor        string `form:"actor" binding:"max=50"`
+	ResourceType string `form:"resource_type" binding:"omitempty,oneof=user account transfer"`
+	ResourceID   string `form:"resource_id" binding:"max=50"`
+	PageId       int32  `form:"page_id" binding:"required,min=1"`
+	PageSize     int32  `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listAuditEventsHandler returns the audit events matching the filters, latest first.
+// Events are listed once the audit chainer appended them to the log.
+func (server *Server) listAuditEventsHandler(ctx *gin.Context) {
+	var req listAuditEventsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	events, err := server.store.ListAuditEvents(ctx, db.ListAuditEventsParams{
+		Actor:        pgtype.Text{String: req.Actor, Valid: req.Actor != ""},
+		ResourceType: pgtype.Text{String: req.ResourceType, Valid: req.ResourceType != ""},
+		ResourceID:   pgtype.Text{String: req.ResourceID, Valid: req.ResourceID != ""},
+		LimitCount:   req.PageSize,
+		OffsetCount:  (req.PageId - 1) * req.PageSize,
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	response := make([]auditEventResponse, 0, len(events))
+	for _, event := range events {
+		response = append(response, newAuditEventResponse(event))
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// verifyAuditChainHandler recomputes the hash chain of the audit log
+// and reports the first event which doesn't match it
+func (server *Server) verifyAuditChainHandler(ctx *gin.Context) {
+	result, err := server.store.VerifyAuditChain(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,245 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuditMiddleware(t *testing.T) {
+	user, _ := createRandomUser()
+	account := createRandomAccount()
+	account.Owner = user.Name
+
+	testCases := []struct {
+		name              string
+		requestID         string
+		setupAuth         func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		expectedActor     string
+		validateRequestID func(t *testing.T, requestID string)
+	}{
+		{
+			name:      "GivenRequestID",
+			requestID: "req-123",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Name, utils.DepositorRole, time.Minute)
+			},
+			expectedActor: user.Name,
+			validateRequestID: func(t *testing.T, requestID string) {
+				assert.Equal(t, "req-123", requestID)
+			},
+		},
+		{
+			name: "GeneratedRequestID",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Name, utils.DepositorRole, time.Minute)
+			},
+			expectedActor: user.Name,
+			validateRequestID: func(t *testing.T, requestID string) {
+				assert.NotEmpty(t, requestID)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// The store receives the audit info of the request with the context of the change
+			var storeInfo db.AuditInfo
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				CreateAccountTx(gomock.Any(), gomock.Any()).
+				Times(1).
+				DoAndReturn(func(ctx context.Context, arg db.CreateAccountParams) (db.Account, error) {
+					storeInfo = db.AuditInfoFromContext(ctx)
+					return account, nil
+				})
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"currency": account.Currency})
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/accounts", bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			request.RemoteAddr = "10.0.0.1:4321"
+			if tc.requestID != "" {
+				request.Header.Set(requestIDHeader, tc.requestID)
+			}
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, tc.expectedActor, storeInfo.Actor)
+			assert.Equal(t, recorder.Header().Get(requestIDHeader), storeInfo.RequestID)
+			assert.Equal(t, "10.0.0.1", storeInfo.ClientIP)
+			tc.validateRequestID(t, recorder.Header().Get(requestIDHeader))
+		})
+	}
+}
+
+func TestListAuditEventsApi(t *testing.T) {
+	admin, _ := createRandomUser()
+	user, _ := createRandomUser()
+
+	after, err := json.Marshal(db.Transfer{ID: 7, Amount: decimal.NewFromInt(10)})
+	assert.NoError(t, err)
+
+	event := db.AuditEvent{
+		ID:           utils.RandomNumber(1, 1000),
+		Actor:        user.Name,
+		Action:       db.AuditActionCreate,
+		ResourceType: db.AuditResourceTransfer,
+		ResourceID:   "7",
+		After:        after,
+		RequestID:    "req-123",
+		ClientIp:     "127.0.0.1",
+		Hash:         "abc",
+		CreatedAt:    pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+
+	adminAuth := func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+		addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+	}
+
+	testCases := []struct {
+		name             string
+		url              string
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			url:       "/api/admin/audit?page_id=2&page_size=5&actor=" + user.Name + "&resource_type=transfer",
+			setupAuth: adminAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.ListAuditEventsParams{
+					Actor:        pgtype.Text{String: user.Name, Valid: true},
+					ResourceType: pgtype.Text{String: db.AuditResourceTransfer, Valid: true},
+					LimitCount:   5,
+					OffsetCount:  5,
+				}
+
+				store.EXPECT().
+					ListAuditEvents(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.AuditEvent{event}, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var events []auditEventResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &events)
+				assert.NoError(t, err)
+				if assert.Len(t, events, 1) {
+					assert.Equal(t, event.ID, events[0].ID)
+					assert.Equal(t, "null", string(events[0].Before))
+					assert.JSONEq(t, string(after), string(events[0].After))
+				}
+			},
+		},
+		{
+			name: "NotAdmin",
+			url:  "/api/admin/audit?page_id=1&page_size=5",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Name, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidResourceType",
+			url:       "/api/admin/audit?page_id=1&page_size=5&resource_type=session",
+			setupAuth: adminAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAuditEvents(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "InternalServerError",
+			url:       "/api/admin/audit?page_id=1&page_size=5",
+			setupAuth: adminAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAuditEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.AuditEvent{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:      "VerifyOK",
+			url:       "/api/admin/audit/verify",
+			setupAuth: adminAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyAuditChain(gomock.Any()).
+					Times(1).
+					Return(db.AuditChainResult{Valid: false, Checked: 3, BrokenEventID: 4}, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var result db.AuditChainResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &result)
+				assert.NoError(t, err)
+				assert.False(t, result.Valid)
+				assert.Equal(t, int64(4), result.BrokenEventID)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			assert.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type cashUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type cashRequest struct {
+	Amount   decimal.Decimal `json:"amount" binding:"required,amount"`
+	Currency db.Currency     `json:"currency" binding:"required,currency"`
+}
+
+// depositHandler credits an account with money coming from outside of the bank.
+// As deposits create money, they are restricted to admins, who can deposit on any account.
+func (server *Server) depositHandler(ctx *gin.Context) {
+	server.cashHandler(ctx, server.getAccount, server.store.DepositTx)
+}
+
+func (server *Server) withdrawalHandler(ctx *gin.Context) {
+	server.cashHandler(ctx, server.getOwnedAccount, server.store.WithdrawTx)
+}
+
+// cashHandler moves the requested amount between an account, fetched with getAccount,
+// and the system account of its currency, with the given store transaction
+func (server *Server) cashHandler(
+	ctx *gin.Context,
+	getAccount func(*gin.Context, int64) (db.Account, bool),
+	cashTx func(context.Context, db.CashTxParams) (db.TransferTxResult, error)) {
+
+	var uri cashUri
+	var req cashRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if !server.checkAmount(ctx, req.Amount, req.Currency) {
+		return
+	}
+
+	account, ok := getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	if account.Currency != req.Currency {
+		errorResponse(ctx, errCurrencyMismatch(account, req.Currency))
+		return
+	}
+
+	result, err := cashTx(ctx, db.CashTxParams{
+		AccountID: uri.ID,
+		Amount:    req.Amount,
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
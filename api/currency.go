@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+var errUnsupportedCurrency = newAPIError(
+	http.StatusBadRequest,
+	"unsupported_currency",
+	"currency is not supported")
+
+// currencyCatalogue caches the currencies table, which rarely changes,
+// so that request validation doesn't hit the database every time
+type currencyCatalogue struct {
+	store db.Store
+	ttl   time.Duration
+
+	mu         sync.Mutex
+	currencies []db.CurrencyInfo
+	loadedAt   time.Time
+}
+
+func newCurrencyCatalogue(store db.Store, ttl time.Duration) *currencyCatalogue {
+	return &currencyCatalogue{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// list returns all the currencies, reloading them once the cache is stale.
+// The stale currencies are kept when they cannot be reloaded.
+func (catalogue *currencyCatalogue) list(ctx context.Context) ([]db.CurrencyInfo, error) {
+	catalogue.mu.Lock()
+	defer catalogue.mu.Unlock()
+
+	if catalogue.currencies != nil && time.Since(catalogue.loadedAt) < catalogue.ttl {
+		return catalogue.currencies, nil
+	}
+
+	currencies, err := catalogue.store.ListCurrencies(ctx)
+	if err != nil {
+		if catalogue.currencies != nil {
+			return catalogue.currencies, nil
+		}
+
+		return nil, err
+	}
+
+	catalogue.currencies = currencies
+	catalogue.loadedAt = time.Now()
+
+	return currencies, nil
+}
+
+// get returns the currency matching the code, false if there is none
+func (catalogue *currencyCatalogue) get(ctx context.Context, code db.Currency) (db.CurrencyInfo, bool, error) {
+	currencies, err := catalogue.list(ctx)
+	if err != nil {
+		return db.CurrencyInfo{}, false, err
+	}
+
+	for _, currency := range currencies {
+		if currency.Code == code {
+			return currency, true, nil
+		}
+	}
+
+	return db.CurrencyInfo{}, false, nil
+}
+
+// checkCurrency makes sure the currency is enabled in the catalogue.
+// It writes the error response and returns false otherwise.
+func (server *Server) checkCurrency(ctx *gin.Context, code db.Currency) (db.CurrencyInfo, bool) {
+	currency, found, err := server.currencies.get(ctx, code)
+	if err != nil {
+		errorResponse(ctx, err)
+		return currency, false
+	}
+
+	if !found || !currency.Enabled {
+		errorResponse(ctx, errUnsupportedCurrency)
+		return currency, false
+	}
+
+	return currency, true
+}
+
+// checkAmount makes sure the currency is enabled in the catalogue and the amount
+// fits its minor unit. It writes the error response and returns false otherwise.
+func (server *Server) checkAmount(ctx *gin.Context, amount decimal.Decimal, code db.Currency) bool {
+	currency, ok := server.checkCurrency(ctx, code)
+	if !ok {
+		return false
+	}
+
+	if !amount.Equal(amount.Truncate(int32(currency.Exponent))) {
+		errorResponse(ctx, &db.AmountPrecisionError{
+			Amount:   amount,
+			Currency: code,
+			Exponent: currency.Exponent,
+		})
+		return false
+	}
+
+	return true
+}
+
+type currencyResponse struct {
+	Code     db.Currency `json:"code"`
+	Exponent int16       `json:"exponent"`
+	Symbol   string      `json:"symbol"`
+}
+
+func (server *Server) listCurrenciesHandler(ctx *gin.Context) {
+	currencies, err := server.currencies.list(ctx)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	response := []currencyResponse{}
+	for _, currency := range currencies {
+		if !currency.Enabled {
+			continue
+		}
+
+		response = append(response, currencyResponse{
+			Code:     currency.Code,
+			Exponent: currency.Exponent,
+			Symbol:   currency.Symbol,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListCurrenciesApi(t *testing.T) {
+	testCases := []struct {
+		name             string
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubFunc: func(store *mockdb.MockStore) {
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				data, err := io.ReadAll(recorder.Body)
+				assert.NoError(t, err)
+
+				var currencies []currencyResponse
+				err = json.Unmarshal(data, &currencies)
+				assert.NoError(t, err)
+
+				// Disabled currencies are not listed
+				assert.Equal(t, []currencyResponse{
+					{Code: db.CurrencyEUR, Exponent: 2, Symbol: "€"},
+					{Code: db.CurrencyINR, Exponent: 2, Symbol: "₹"},
+					{Code: db.CurrencyUSD, Exponent: 2, Symbol: "$"},
+				}, currencies)
+			},
+		},
+		{
+			name: "InternalServerError",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCurrencies(gomock.Any()).
+					Times(1).
+					Return([]db.CurrencyInfo{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/currencies", nil)
+			assert.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func TestCurrencyCatalogue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	catalogue := newCurrencyCatalogue(store, time.Hour)
+
+	// The currencies are loaded once and then served from the cache
+	store.EXPECT().
+		ListCurrencies(gomock.Any()).
+		Times(1).
+		Return(testCurrencies, nil)
+
+	for i := 0; i < 3; i++ {
+		currency, found, err := catalogue.get(context.Background(), db.CurrencyUSD)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, int16(2), currency.Exponent)
+	}
+
+	_, found, err := catalogue.get(context.Background(), "XYZ")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// A stale catalogue is reloaded, and kept when the reload fails
+	catalogue.loadedAt = time.Now().Add(-2 * time.Hour)
+	store.EXPECT().
+		ListCurrencies(gomock.Any()).
+		Times(1).
+		Return(nil, errors.New("connection refused"))
+
+	_, found, err = catalogue.get(context.Background(), db.CurrencyUSD)
+	assert.NoError(t, err)
+	assert.True(t, found)
+}
+
+func TestCurrencyCatalogueLoadError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	catalogue := newCurrencyCatalogue(store, time.Hour)
+
+	store.EXPECT().
+		ListCurrencies(gomock.Any()).
+		Times(1).
+		Return(nil, pgx.ErrTxClosed)
+
+	_, _, err := catalogue.get(context.Background(), db.CurrencyUSD)
+	assert.ErrorIs(t, err, pgx.ErrTxClosed)
+}
@@ -0,0 +1,256 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/fx"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/logging"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// problemContentType is the media type of the error responses (RFC 7807)
+const problemContentType = "application/problem+json"
+
+// Stable codes of the errors which are not specific to a handler.
+// Clients are expected to rely on them rather than on the messages.
+const (
+	codeInvalidRequest      = "invalid_request"
+	codeUnauthorized        = "unauthorized"
+	codeForbidden           = "forbidden"
+	codeNotFound            = "not_found"
+	codeAlreadyExists       = "already_exists"
+	codeInvalidReference    = "invalid_reference"
+	codeConstraintViolation = "constraint_violation"
+	codeConcurrentUpdate    = "concurrent_update"
+	codeInternal            = "internal_error"
+)
+
+// Postgres error codes mapped by apiErrorFrom
+const (
+	pgInvalidTextRepresentation = "22P02"
+	pgNumericValueOutOfRange    = "22003"
+	pgNotNullViolation          = "23502"
+	pgForeignKeyViolation       = "23503"
+	pgUniqueViolation           = "23505"
+	pgCheckViolation            = "23514"
+	pgSerializationFailure      = "40001"
+	pgDeadlockDetected          = "40P01"
+)
+
+// APIError is the body of every error response, a problem details object (RFC 7807)
+// extended with a stable code, details specific to the error and the id of the request.
+// Type is always about:blank, so Title is the text of Status.
+type APIError struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Code      string         `json:"code"`
+	Message   string         `json:"detail"`
+	Details   map[string]any `json:"details,omitempty"`
+	Instance  string         `json:"instance,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+}
+
+func newAPIError(status int, code string, message string) *APIError {
+	return &APIError{
+		Type:    "about:blank",
+		Title:   http.StatusText(status),
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// withDetails returns a copy of the error with details
+func (e *APIError) withDetails(details map[string]any) *APIError {
+	apiErr := *e
+	apiErr.Details = details
+	return &apiErr
+}
+
+// domainError maps an error of the store or of a provider to its status and code
+type domainError struct {
+	err    error
+	status int
+	code   string
+}
+
+var domainErrors = []domainError{
+	{pgx.ErrNoRows, http.StatusNotFound, codeNotFound},
+	{token.ErrInvalidToken, http.StatusUnauthorized, "invalid_token"},
+	{token.ErrExpiredToken, http.StatusUnauthorized, "expired_token"},
+	{db.ErrSystemAccount, http.StatusForbidden, "system_account"},
+	{db.ErrAccountFrozen, http.StatusForbidden, "account_frozen"},
+	{db.ErrAccountClosed, http.StatusConflict, "account_closed"},
+	{db.ErrAccountNotFrozen, http.StatusConflict, "account_not_frozen"},
+	{db.ErrNonZeroBalance, http.StatusUnprocessableEntity, "non_zero_balance"},
+	{db.ErrActiveHolds, http.StatusUnprocessableEntity, "active_holds"},
+	{db.ErrInvalidAmountPrecision, http.StatusBadRequest, "invalid_amount_precision"},
+	{db.ErrZeroAdjustment, http.StatusBadRequest, "zero_adjustment"},
+	{db.ErrExchangeRateRequired, http.StatusUnprocessableEntity, "exchange_rate_required"},
+	{fx.ErrRateNotFound, http.StatusUnprocessableEntity, "exchange_rate_not_found"},
+	{fx.ErrStaleRate, http.StatusServiceUnavailable, "exchange_rate_stale"},
+	{fx.ErrRatesUnavailable, http.StatusServiceUnavailable, "exchange_rates_unavailable"},
+	{db.ErrToAmountTooSmall, http.StatusUnprocessableEntity, "amount_too_small"},
+	{db.ErrInsufficientFunds, http.StatusUnprocessableEntity, "insufficient_funds"},
+	{db.ErrLimitExceeded, http.StatusTooManyRequests, "limit_exceeded"},
+	{db.ErrInvalidHoldAmount, http.StatusBadRequest, "invalid_hold_amount"},
+	{db.ErrInvalidCaptureAmount, http.StatusBadRequest, "invalid_capture_amount"},
+	{db.ErrHoldExpired, http.StatusConflict, "hold_expired"},
+	{db.ErrHoldNotActive, http.StatusConflict, "hold_not_active"},
+	{db.ErrIdempotencyKeyReused, http.StatusConflict, "idempotency_key_reused"},
+	{db.ErrInvalidReversalAmount, http.StatusBadRequest, "invalid_reversal_amount"},
+	{db.ErrReversalOfReversal, http.StatusUnprocessableEntity, "reversal_of_reversal"},
+	{db.ErrReversalExceedsRemaining, http.StatusConflict, "reversal_exceeds_remaining"},
+	{db.ErrInvalidScheduleTransition, http.StatusConflict, "invalid_schedule_transition"},
+	{webhook.ErrInsecureURL, http.StatusBadRequest, "insecure_webhook_url"},
+	{webhook.ErrForbiddenAddress, http.StatusBadRequest, "forbidden_webhook_address"},
+}
+
+// apiErrorFrom maps err to the error returned to the client. Errors of the handlers are
+// returned as they are, errors of the store by their sentinel error or their Postgres code.
+// The messages of Postgres and of unexpected errors are never returned, as they
+// describe the schema and the internals of the server.
+func apiErrorFrom(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.withDetails(apiErr.Details)
+	}
+
+	for _, domainErr := range domainErrors {
+		if errors.Is(err, domainErr.err) {
+			message := err.Error()
+			if domainErr.err == pgx.ErrNoRows {
+				message = "resource not found"
+			}
+
+			return newAPIError(domainErrorStatus(err, domainErr.status), domainErr.code, message).
+				withDetails(domainErrorDetails(err))
+		}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgInvalidTextRepresentation, pgNumericValueOutOfRange, pgNotNullViolation:
+			return newAPIError(http.StatusBadRequest, codeInvalidRequest, "invalid value in the request")
+		case pgForeignKeyViolation:
+			return newAPIError(http.StatusUnprocessableEntity, codeInvalidReference, "the request refers to a resource which doesn't exist")
+		case pgUniqueViolation:
+			return newAPIError(http.StatusConflict, codeAlreadyExists, "resource already exists")
+		case pgCheckViolation:
+			return newAPIError(http.StatusUnprocessableEntity, codeConstraintViolation, "the request breaks a rule of the resource")
+		case pgSerializationFailure, pgDeadlockDetected:
+			return newAPIError(http.StatusConflict, codeConcurrentUpdate, "the request conflicted with a concurrent request, retry it")
+		}
+	}
+
+	return newAPIError(http.StatusInternalServerError, codeInternal, "internal server error")
+}
+
+// domainErrorStatus returns the status of the domain errors whose status depends on their details.
+// A transfer above the max single transfer cannot go through, the other limits are velocity
+// limits which let the transfer go through later.
+func domainErrorStatus(err error, status int) int {
+	var limitErr *db.LimitExceededError
+	if errors.As(err, &limitErr) && limitErr.Limit == db.LimitMaxSingleTransfer {
+		return http.StatusUnprocessableEntity
+	}
+
+	return status
+}
+
+// domainErrorDetails returns the details carried by the typed errors of the store
+func domainErrorDetails(err error) map[string]any {
+	var insufficientFundsErr *db.InsufficientFundsError
+	if errors.As(err, &insufficientFundsErr) {
+		return map[string]any{"available_balance": insufficientFundsErr.Available}
+	}
+
+	var notActiveErr *db.AccountNotActiveError
+	if errors.As(err, &notActiveErr) && notActiveErr.Reason != "" {
+		return map[string]any{"reason": notActiveErr.Reason}
+	}
+
+	var nonZeroBalanceErr *db.NonZeroBalanceError
+	if errors.As(err, &nonZeroBalanceErr) {
+		return map[string]any{"balance": nonZeroBalanceErr.Balance}
+	}
+
+	var activeHoldsErr *db.ActiveHoldsError
+	if errors.As(err, &activeHoldsErr) {
+		return map[string]any{"held_amount": activeHoldsErr.HeldAmount}
+	}
+
+	var exceedsErr *db.ReversalExceedsRemainingError
+	if errors.As(err, &exceedsErr) {
+		return map[string]any{"remaining": exceedsErr.Remaining}
+	}
+
+	var limitErr *db.LimitExceededError
+	if errors.As(err, &limitErr) {
+		return map[string]any{
+			"limit":     limitErr.Limit,
+			"scope":     limitErr.Scope,
+			"currency":  limitErr.Currency,
+			"max":       limitErr.Max,
+			"used":      limitErr.Used,
+			"requested": limitErr.Requested,
+		}
+	}
+
+	return nil
+}
+
+// invalidRequest returns the error of a request which cannot be bound or validated.
+// The fields failing validation are returned with the rule they break.
+func invalidRequest(err error) *APIError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make(map[string]any, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fields[fieldErr.Field()] = fieldErr.Tag()
+		}
+
+		return newAPIError(http.StatusBadRequest, codeInvalidRequest, "invalid request").
+			withDetails(map[string]any{"fields": fields})
+	}
+
+	return newAPIError(http.StatusBadRequest, codeInvalidRequest, err.Error())
+}
+
+// errorResponse writes err as a problem details response and aborts the request.
+// err is kept in the context, so that unexpected errors are logged.
+func errorResponse(ctx *gin.Context, err error) {
+	writeError(ctx, apiErrorFrom(err), err)
+}
+
+// errorResponseWithStatus writes err like errorResponse, with a status depending on the
+// operation, e.g. a frozen account is a conflict when freezing it but forbids a transfer
+func errorResponseWithStatus(ctx *gin.Context, status int, err error) {
+	apiErr := apiErrorFrom(err)
+	apiErr.Status = status
+	apiErr.Title = http.StatusText(status)
+
+	writeError(ctx, apiErr, err)
+}
+
+func writeError(ctx *gin.Context, apiErr *APIError, err error) {
+	apiErr.Instance = ctx.Request.URL.Path
+	apiErr.RequestID = logging.RequestIDFrom(ctx.Request.Context())
+
+	_ = ctx.Error(err)
+	ctx.Header("Content-Type", problemContentType)
+	ctx.AbortWithStatusJSON(apiErr.Status, apiErr)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestApiErrorFrom(t *testing.T) {
+	pgErr := func(code string) error {
+		return &pgconn.PgError{
+			Code:           code,
+			Message:        "duplicate key value violates unique constraint \"users_email_key\"",
+			ConstraintName: "users_email_key",
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"ApiError", errAccountNotOwned, http.StatusForbidden, "account_not_owned"},
+		{"NoRows", pgx.ErrNoRows, http.StatusNotFound, codeNotFound},
+		{"WrappedNoRows", fmt.Errorf("cannot get account: %w", pgx.ErrNoRows), http.StatusNotFound, codeNotFound},
+		{"AccountFrozen", &db.AccountNotActiveError{Status: db.AccountStatusFrozen}, http.StatusForbidden, "account_frozen"},
+		{"AccountClosed", &db.AccountNotActiveError{Status: db.AccountStatusClosed}, http.StatusConflict, "account_closed"},
+		{"InsufficientFunds", &db.InsufficientFundsError{}, http.StatusUnprocessableEntity, "insufficient_funds"},
+		{"VelocityLimit", &db.LimitExceededError{Limit: db.LimitDailyOutgoing}, http.StatusTooManyRequests, "limit_exceeded"},
+		{"SingleTransferLimit", &db.LimitExceededError{Limit: db.LimitMaxSingleTransfer}, http.StatusUnprocessableEntity, "limit_exceeded"},
+		{"UniqueViolation", pgErr(pgUniqueViolation), http.StatusConflict, codeAlreadyExists},
+		{"ForeignKeyViolation", pgErr(pgForeignKeyViolation), http.StatusUnprocessableEntity, codeInvalidReference},
+		{"CheckViolation", pgErr(pgCheckViolation), http.StatusUnprocessableEntity, codeConstraintViolation},
+		{"SerializationFailure", pgErr(pgSerializationFailure), http.StatusConflict, codeConcurrentUpdate},
+		{"DeadlockDetected", pgErr(pgDeadlockDetected), http.StatusConflict, codeConcurrentUpdate},
+		{"UnknownPgError", pgErr("XX000"), http.StatusInternalServerError, codeInternal},
+		{"Unexpected", errors.New("connection refused"), http.StatusInternalServerError, codeInternal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiErr := apiErrorFrom(tc.err)
+			assert.Equal(t, tc.expectedStatus, apiErr.Status)
+			assert.Equal(t, http.StatusText(tc.expectedStatus), apiErr.Title)
+			assert.Equal(t, tc.expectedCode, apiErr.Code)
+
+			// The messages of Postgres don't leak
+			assert.NotContains(t, apiErr.Message, "users_email_key")
+		})
+	}
+}
+
+func TestApiErrorFromDoesNotModifyApiErrors(t *testing.T) {
+	apiErr := apiErrorFrom(errAccountNotOwned)
+	apiErr.RequestID = "request-id"
+
+	assert.Empty(t, errAccountNotOwned.RequestID)
+}
+
+func TestApiErrorDetails(t *testing.T) {
+	apiErr := apiErrorFrom(&db.InsufficientFundsError{
+		AccountID: 1,
+		Available: decimal.NewFromInt(5),
+		Requested: decimal.NewFromInt(10),
+	})
+	assert.Equal(t, decimal.NewFromInt(5), apiErr.Details["available_balance"])
+
+	apiErr = apiErrorFrom(&db.AccountNotActiveError{Status: db.AccountStatusFrozen, Reason: "court order"})
+	assert.Equal(t, "court order", apiErr.Details["reason"])
+
+	apiErr = apiErrorFrom(&db.AccountNotActiveError{Status: db.AccountStatusFrozen})
+	assert.Nil(t, apiErr.Details)
+}
+
+func TestErrorResponseProblemDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/api/users", nil)
+	assert.NoError(t, err)
+	request.Header.Set(requestIDHeader, "test-request-id")
+
+	server.router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, problemContentType, recorder.Header().Get("Content-Type"))
+
+	var body APIError
+	err = json.Unmarshal(recorder.Body.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Equal(t, "about:blank", body.Type)
+	assert.Equal(t, http.StatusBadRequest, body.Status)
+	assert.Equal(t, codeInvalidRequest, body.Code)
+	assert.Equal(t, "/api/users", body.Instance)
+	assert.Equal(t, "test-request-id", body.RequestID)
+}
+
+func TestInvalidRequestFields(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	data, err := json.Marshal(gin.H{
+		"name":     "user1",
+		"email":    "invalid-email",
+		"password": "secret",
+	})
+	assert.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/api/users", bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	var body APIError
+	err = json.Unmarshal(recorder.Body.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"Email": "email", "Password": "min"}, body.Details["fields"])
+}
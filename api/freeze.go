@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/gin-gonic/gin"
+)
+
+type freezeAccountUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type freezeAccountRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+func (server *Server) freezeAccountHandler(ctx *gin.Context) {
+	var uri freezeAccountUri
+	var req freezeAccountRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.store.FreezeAccountTx(ctx, db.FreezeAccountTxParams{
+		AccountID: uri.ID,
+		Reason:    req.Reason,
+		Operator:  authPayload.Username,
+	})
+	if err != nil {
+		freezeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (server *Server) unfreezeAccountHandler(ctx *gin.Context) {
+	var uri freezeAccountUri
+	var req freezeAccountRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.store.UnfreezeAccountTx(ctx, db.UnfreezeAccountTxParams{
+		AccountID: uri.ID,
+		Reason:    req.Reason,
+		Operator:  authPayload.Username,
+	})
+	if err != nil {
+		freezeErrorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// freezeErrorResponse writes the error of a freeze or an unfreeze, for which
+// the status of the account is a conflict rather than a forbidden operation
+func freezeErrorResponse(ctx *gin.Context, err error) {
+	if errors.Is(err, db.ErrAccountFrozen) {
+		errorResponseWithStatus(ctx, http.StatusConflict, err)
+		return
+	}
+
+	errorResponse(ctx, err)
+}
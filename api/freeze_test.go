@@ -0,0 +1,246 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFreezeAccountApi(t *testing.T) {
+	admin, _ := createRandomUser()
+	account := createRandomAccount()
+	reason := "suspicious activity"
+
+	frozenAccount := account
+	frozenAccount.Status = db.AccountStatusFrozen
+
+	testCases := []struct {
+		name             string
+		action           string
+		accountId        int64
+		body             gin.H
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "FreezeOK",
+			action:    "freeze",
+			accountId: account.ID,
+			body:      gin.H{"reason": reason},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.FreezeAccountTxParams{
+					AccountID: account.ID,
+					Reason:    reason,
+					Operator:  admin.Name,
+				}
+
+				store.EXPECT().
+					FreezeAccountTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.AccountFreezeTxResult{
+						Account: frozenAccount,
+						Freeze:  db.AccountFreeze{AccountID: account.ID, Reason: reason, FrozenBy: admin.Name},
+					}, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var result db.AccountFreezeTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &result)
+				assert.NoError(t, err)
+				assert.Equal(t, db.AccountStatusFrozen, result.Account.Status)
+				assert.Equal(t, reason, result.Freeze.Reason)
+				assert.Equal(t, admin.Name, result.Freeze.FrozenBy)
+			},
+		},
+		{
+			name:      "FreezeNotAdmin",
+			action:    "freeze",
+			accountId: account.ID,
+			body:      gin.H{"reason": reason},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "FreezeMissingReason",
+			action:    "freeze",
+			accountId: account.ID,
+			body:      gin.H{},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().FreezeAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "FreezeAlreadyFrozen",
+			action:    "freeze",
+			accountId: account.ID,
+			body:      gin.H{"reason": reason},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					FreezeAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountFreezeTxResult{}, &db.AccountNotActiveError{
+						AccountID: account.ID,
+						Status:    db.AccountStatusFrozen,
+					})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:      "FreezeAccountNotFound",
+			action:    "freeze",
+			accountId: account.ID,
+			body:      gin.H{"reason": reason},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					FreezeAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountFreezeTxResult{}, pgx.ErrNoRows)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "UnfreezeOK",
+			action:    "unfreeze",
+			accountId: account.ID,
+			body:      gin.H{"reason": "cleared by compliance"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.UnfreezeAccountTxParams{
+					AccountID: account.ID,
+					Reason:    "cleared by compliance",
+					Operator:  admin.Name,
+				}
+
+				store.EXPECT().
+					UnfreezeAccountTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.AccountFreezeTxResult{Account: account}, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "UnfreezeNotFrozen",
+			action:    "unfreeze",
+			accountId: account.ID,
+			body:      gin.H{"reason": "cleared by compliance"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UnfreezeAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountFreezeTxResult{}, db.ErrAccountNotFrozen)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+				assertError(t, db.ErrAccountNotFrozen, recorder.Body)
+			},
+		},
+		{
+			name:      "UnfreezeInternalServerError",
+			action:    "unfreeze",
+			accountId: account.ID,
+			body:      gin.H{"reason": "cleared by compliance"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UnfreezeAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountFreezeTxResult{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+		{
+			name:      "BadRequest",
+			action:    "unfreeze",
+			accountId: 0,
+			body:      gin.H{"reason": "cleared by compliance"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().UnfreezeAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/accounts/%d/%s", tc.accountId, tc.action)
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+var errInvalidLimitAmount = newAPIError(
+	http.StatusBadRequest,
+	"invalid_limit_amount",
+	"limit amounts must be positive and within the allowed range")
+
+// limitValuesRequest contains the values of the limits, a missing value removes the limit.
+// Amounts are in the currency of the limits.
+type limitValuesRequest struct {
+	MaxSingleTransfer   decimal.NullDecimal `json:"max_single_transfer"`
+	DailyOutgoing       decimal.NullDecimal `json:"daily_outgoing"`
+	MonthlyOutgoing     decimal.NullDecimal `json:"monthly_outgoing"`
+	MaxTransfersPerHour *int32              `json:"max_transfers_per_hour" binding:"omitempty,min=1"`
+}
+
+// validate makes sure the amounts of the limits are valid amounts
+func (req limitValuesRequest) validate() error {
+	for _, amount := range []decimal.NullDecimal{req.MaxSingleTransfer, req.DailyOutgoing, req.MonthlyOutgoing} {
+		if amount.Valid && (!amount.Decimal.IsPositive() || amount.Decimal.GreaterThan(maxAmount)) {
+			return errInvalidLimitAmount
+		}
+	}
+
+	return nil
+}
+
+func (req limitValuesRequest) maxTransfersPerHour() pgtype.Int4 {
+	if req.MaxTransfersPerHour == nil {
+		return pgtype.Int4{}
+	}
+
+	return pgtype.Int4{Int32: *req.MaxTransfersPerHour, Valid: true}
+}
+
+type accountLimitUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// setAccountLimitHandler creates or replaces the limits of an account, in its currency
+func (server *Server) setAccountLimitHandler(ctx *gin.Context) {
+	var uri accountLimitUri
+	var req limitValuesRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	account, ok := server.getAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	limit, err := server.store.UpsertAccountLimit(ctx, db.UpsertAccountLimitParams{
+		AccountID:           account.ID,
+		Currency:            account.Currency,
+		MaxSingleTransfer:   req.MaxSingleTransfer,
+		DailyOutgoing:       req.DailyOutgoing,
+		MonthlyOutgoing:     req.MonthlyOutgoing,
+		MaxTransfersPerHour: req.maxTransfersPerHour(),
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limit)
+}
+
+type userLimitUri struct {
+	Name     string      `uri:"name" binding:"required,alphanum"`
+	Currency db.Currency `uri:"currency" binding:"required,currency"`
+}
+
+// setUserLimitHandler creates or replaces the limits of a user in a currency,
+// which apply to all the accounts of the user in the currency together
+func (server *Server) setUserLimitHandler(ctx *gin.Context) {
+	var uri userLimitUri
+	var req limitValuesRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if _, ok := server.checkCurrency(ctx, uri.Currency); !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if err := req.validate(); err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	limit, err := server.store.UpsertUserLimit(ctx, db.UpsertUserLimitParams{
+		Owner:               uri.Name,
+		Currency:            uri.Currency,
+		MaxSingleTransfer:   req.MaxSingleTransfer,
+		DailyOutgoing:       req.DailyOutgoing,
+		MonthlyOutgoing:     req.MonthlyOutgoing,
+		MaxTransfersPerHour: req.maxTransfersPerHour(),
+	})
+	if err != nil {
+		// The user doesn't exist
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			errorResponseWithStatus(ctx, http.StatusNotFound, err)
+			return
+		}
+
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limit)
+}
+
+type listLimitsRequest struct {
+	AccountID int64  `form:"account_id" binding:"omitempty,min=1"`
+	Owner     string `form:"owner" binding:"omitempty,alphanum"`
+	PageId    int32  `form:"page_id" binding:"required,min=1"`
+	PageSize  int32  `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listLimitsHandler(ctx *gin.Context) {
+	var req listLimitsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	limits, err := server.store.ListLimits(ctx, db.ListLimitsParams{
+		AccountID:   pgtype.Int8{Int64: req.AccountID, Valid: req.AccountID != 0},
+		Owner:       pgtype.Text{String: req.Owner, Valid: req.Owner != ""},
+		LimitCount:  req.PageSize,
+		OffsetCount: (req.PageId - 1) * req.PageSize,
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limits)
+}
+
+type limitUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// deleteLimitHandler removes the limits and returns them
+func (server *Server) deleteLimitHandler(ctx *gin.Context) {
+	var uri limitUri
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	limit, err := server.store.GetLimit(ctx, uri.ID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	err = server.store.DeleteLimit(ctx, limit.ID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limit)
+}
+
+// listAccountLimitsHandler returns the limits applying to the transfers
+// from an account of the authenticated user, its own and the ones of the user
+func (server *Server) listAccountLimitsHandler(ctx *gin.Context) {
+	var uri accountLimitUri
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	account, ok := server.getOwnedAccount(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	limits, err := server.store.ListTransferLimits(ctx, db.ListTransferLimitsParams{
+		AccountID: account.ID,
+		Owner:     account.Owner,
+		Currency:  account.Currency,
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, limits)
+}
@@ -0,0 +1,358 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSetLimitApi(t *testing.T) {
+	admin, _ := createRandomUser()
+	user, _ := createRandomUser()
+	account := createRandomAccount()
+	account.Owner = user.Name
+
+	limit := db.Limit{
+		ID:                  utils.RandomNumber(1, 1000),
+		AccountID:           pgtype.Int8{Int64: account.ID, Valid: true},
+		Currency:            account.Currency,
+		DailyOutgoing:       decimal.NewNullDecimal(decimal.NewFromInt(1000)),
+		MaxTransfersPerHour: pgtype.Int4{Int32: 5, Valid: true},
+	}
+
+	adminAuth := func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+		addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+	}
+
+	testCases := []struct {
+		name             string
+		url              string
+		body             gin.H
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "AccountOK",
+			url:       fmt.Sprintf("/api/accounts/%d/limits", account.ID),
+			body:      gin.H{"daily_outgoing": "1000", "max_transfers_per_hour": 5},
+			setupAuth: adminAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.UpsertAccountLimitParams{
+					AccountID:           account.ID,
+					Currency:            account.Currency,
+					DailyOutgoing:       decimal.NewNullDecimal(decimal.NewFromInt(1000)),
+					MaxTransfersPerHour: pgtype.Int4{Int32: 5, Valid: true},
+				}
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					UpsertAccountLimit(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(limit, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var result db.Limit
+				err := json.Unmarshal(recorder.Body.Bytes(), &result)
+				assert.NoError(t, err)
+				assert.Equal(t, limit.ID, result.ID)
+				assert.False(t, result.MaxSingleTransfer.Valid)
+			},
+		},
+		{
+			name:      "AccountNotFound",
+			url:       fmt.Sprintf("/api/accounts/%d/limits", account.ID),
+			body:      gin.H{"daily_outgoing": "1000"},
+			setupAuth: adminAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, pgx.ErrNoRows)
+				store.EXPECT().UpsertAccountLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "NegativeAmount",
+			url:       fmt.Sprintf("/api/accounts/%d/limits", account.ID),
+			body:      gin.H{"max_single_transfer": "-10"},
+			setupAuth: adminAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertAccountLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errInvalidLimitAmount, recorder.Body)
+			},
+		},
+		{
+			name:      "ZeroTransfersPerHour",
+			url:       fmt.Sprintf("/api/accounts/%d/limits", account.ID),
+			body:      gin.H{"max_transfers_per_hour": 0},
+			setupAuth: adminAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertAccountLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			url:  fmt.Sprintf("/api/accounts/%d/limits", account.ID),
+			body: gin.H{"daily_outgoing": "1000"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Name, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertAccountLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "UserOK",
+			url:       fmt.Sprintf("/api/users/%s/limits/%s", user.Name, account.Currency),
+			body:      gin.H{"monthly_outgoing": "5000"},
+			setupAuth: adminAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.UpsertUserLimitParams{
+					Owner:           user.Name,
+					Currency:        account.Currency,
+					MonthlyOutgoing: decimal.NewNullDecimal(decimal.NewFromInt(5000)),
+				}
+
+				store.EXPECT().
+					UpsertUserLimit(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.Limit{
+						ID:              limit.ID + 1,
+						Owner:           pgtype.Text{String: user.Name, Valid: true},
+						Currency:        account.Currency,
+						MonthlyOutgoing: arg.MonthlyOutgoing,
+					}, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "UserNotFound",
+			url:       fmt.Sprintf("/api/users/%s/limits/%s", user.Name, account.Currency),
+			body:      gin.H{"monthly_outgoing": "5000"},
+			setupAuth: adminAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpsertUserLimit(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Limit{}, pgconn.ErrorResponseToPgError(&pgproto3.ErrorResponse{Code: "23503"}))
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "UnsupportedCurrency",
+			url:       fmt.Sprintf("/api/users/%s/limits/XYZ", user.Name),
+			body:      gin.H{"monthly_outgoing": "5000"},
+			setupAuth: adminAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertUserLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errUnsupportedCurrency, recorder.Body)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, tc.url, bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func TestListAccountLimitsApi(t *testing.T) {
+	user, _ := createRandomUser()
+	account := createRandomAccount()
+	account.Owner = user.Name
+
+	limits := []db.Limit{
+		{
+			ID:                utils.RandomNumber(1, 1000),
+			AccountID:         pgtype.Int8{Int64: account.ID, Valid: true},
+			Currency:          account.Currency,
+			MaxSingleTransfer: decimal.NewNullDecimal(decimal.NewFromInt(100)),
+		},
+	}
+
+	testCases := []struct {
+		name             string
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Name, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.ListTransferLimitsParams{
+					AccountID: account.ID,
+					Owner:     user.Name,
+					Currency:  account.Currency,
+				}
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ListTransferLimits(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(limits, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var result []db.Limit
+				err := json.Unmarshal(recorder.Body.Bytes(), &result)
+				assert.NoError(t, err)
+				assert.Len(t, result, 1)
+			},
+		},
+		{
+			name: "NotOwner",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "someone", utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListTransferLimits(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/accounts/%d/limits", account.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			assert.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func TestDeleteLimitApi(t *testing.T) {
+	admin, _ := createRandomUser()
+	limit := db.Limit{
+		ID:       utils.RandomNumber(1, 1000),
+		Owner:    pgtype.Text{String: admin.Name, Valid: true},
+		Currency: db.CurrencyUSD,
+	}
+
+	testCases := []struct {
+		name             string
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLimit(gomock.Any(), gomock.Eq(limit.ID)).Times(1).Return(limit, nil)
+				store.EXPECT().DeleteLimit(gomock.Any(), gomock.Eq(limit.ID)).Times(1).Return(nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetLimit(gomock.Any(), gomock.Eq(limit.ID)).Times(1).Return(db.Limit{}, pgx.ErrNoRows)
+				store.EXPECT().DeleteLimit(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/admin/limits/%d", limit.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			assert.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/logging"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	maxRequestIDLength = 128
+)
+
+// requestIDMiddleware creates a gin middleware which identifies each request by the
+// X-Request-ID header of the client, or by a generated id when it is missing or invalid.
+// The id is returned in the response and attached to the request context,
+// so that it is added to the logs of the request and of its queries.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		ctx.Header(requestIDHeader, requestID)
+		ctx.Request = ctx.Request.WithContext(logging.WithRequestID(ctx.Request.Context(), requestID))
+		ctx.Next()
+	}
+}
+
+// isValidRequestID makes sure a request id given by a client can be logged as it is
+func isValidRequestID(requestID string) bool {
+	if len(requestID) == 0 || len(requestID) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range requestID {
+		isAlphanum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlphanum && c != '-' && c != '_' && c != '.' && c != ':' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// accessLogMiddleware creates a gin middleware which logs every request once served,
+// with its status, latency and authenticated user. Server errors are logged at error level
+// with the error behind them, client errors at warn level. It must run after requestIDMiddleware.
+func accessLogMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", logging.RedactURL(ctx.Request.URL)),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.Int("size", ctx.Writer.Size()),
+			slog.String("client_ip", ctx.ClientIP()),
+		}
+
+		if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+			attrs = append(attrs, slog.String("user", payload.(*token.Payload).Username))
+		}
+
+		if err := ctx.Errors.Last(); err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		logger.LogAttrs(ctx.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// recoveryMiddleware creates a gin middleware which logs the panics of the handlers
+// with their stack and responds with an internal error
+func recoveryMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(ctx *gin.Context, recovered any) {
+		logger.ErrorContext(
+			ctx.Request.Context(),
+			"panic recovered",
+			slog.Any("panic", recovered),
+			slog.String("stack", string(debug.Stack())))
+
+		errorResponse(ctx, fmt.Errorf("panic: %v", recovered))
+	})
+}
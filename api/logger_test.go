@@ -0,0 +1,176 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/logging"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLoggedRouter returns a router with the logging middlewares of the server,
+// logging to buffer, and routes setting the authenticated user, failing and panicking
+func newLoggedRouter(t *testing.T, buffer *bytes.Buffer) *gin.Engine {
+	logger, err := logging.NewLogger(buffer, logging.FormatJSON, "info")
+	assert.NoError(t, err)
+
+	router := gin.New()
+	router.Use(requestIDMiddleware(), accessLogMiddleware(logger), recoveryMiddleware(logger))
+
+	router.GET("/ok", func(ctx *gin.Context) {
+		ctx.Set(authorizationPayloadKey, &token.Payload{Username: "user1", Role: utils.DepositorRole})
+		ctx.JSON(http.StatusOK, gin.H{})
+	})
+	router.GET("/fail", func(ctx *gin.Context) {
+		errorResponse(ctx, newAPIError(http.StatusInternalServerError, codeInternal, "connection refused"))
+	})
+	router.GET("/panic", func(ctx *gin.Context) {
+		panic("unexpected")
+	})
+
+	return router
+}
+
+// readLogRecords returns the JSON records written to buffer
+func readLogRecords(t *testing.T, buffer *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		var record map[string]any
+		err := json.Unmarshal([]byte(line), &record)
+		assert.NoError(t, err)
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	testCases := []struct {
+		name              string
+		requestID         string
+		validateRequestID func(t *testing.T, requestID string)
+	}{
+		{
+			name:      "GivenRequestID",
+			requestID: "req-123",
+			validateRequestID: func(t *testing.T, requestID string) {
+				assert.Equal(t, "req-123", requestID)
+			},
+		},
+		{
+			name: "GeneratedRequestID",
+			validateRequestID: func(t *testing.T, requestID string) {
+				assert.Len(t, requestID, 36)
+			},
+		},
+		{
+			name:      "InvalidRequestID",
+			requestID: "req\n123",
+			validateRequestID: func(t *testing.T, requestID string) {
+				assert.Len(t, requestID, 36)
+			},
+		},
+		{
+			name:      "TooLongRequestID",
+			requestID: strings.Repeat("a", maxRequestIDLength+1),
+			validateRequestID: func(t *testing.T, requestID string) {
+				assert.Len(t, requestID, 36)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			router := newLoggedRouter(t, &buffer)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/ok", nil)
+			assert.NoError(t, err)
+			request.Header.Set(requestIDHeader, tc.requestID)
+
+			router.ServeHTTP(recorder, request)
+
+			requestID := recorder.Header().Get(requestIDHeader)
+			tc.validateRequestID(t, requestID)
+
+			records := readLogRecords(t, &buffer)
+			assert.Len(t, records, 1)
+			assert.Equal(t, requestID, records[0][logging.RequestIDKey])
+		})
+	}
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	var buffer bytes.Buffer
+	router := newLoggedRouter(t, &buffer)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/ok?page_id=1&access_token=abc", nil)
+	assert.NoError(t, err)
+
+	router.ServeHTTP(recorder, request)
+
+	records := readLogRecords(t, &buffer)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "INFO", records[0]["level"])
+	assert.Equal(t, "request", records[0]["msg"])
+	assert.Equal(t, http.MethodGet, records[0]["method"])
+	assert.Equal(t, "/ok", records[0]["route"])
+	assert.Equal(t, float64(http.StatusOK), records[0]["status"])
+	assert.Equal(t, "user1", records[0]["user"])
+	assert.Contains(t, records[0], "latency")
+	assert.NotContains(t, records[0]["path"], "abc")
+}
+
+func TestAccessLogMiddlewareServerError(t *testing.T) {
+	var buffer bytes.Buffer
+	router := newLoggedRouter(t, &buffer)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/fail", nil)
+	assert.NoError(t, err)
+
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+	records := readLogRecords(t, &buffer)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "ERROR", records[0]["level"])
+	assert.Equal(t, "connection refused", records[0]["error"])
+	assert.NotContains(t, records[0], "user")
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	var buffer bytes.Buffer
+	router := newLoggedRouter(t, &buffer)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/panic", nil)
+	assert.NoError(t, err)
+
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, problemContentType, recorder.Header().Get("Content-Type"))
+
+	var body APIError
+	err = json.Unmarshal(recorder.Body.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Equal(t, codeInternal, body.Code)
+	assert.Equal(t, recorder.Header().Get(requestIDHeader), body.RequestID)
+
+	records := readLogRecords(t, &buffer)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "panic recovered", records[0]["msg"])
+	assert.Equal(t, "unexpected", records[0]["panic"])
+	assert.Equal(t, float64(http.StatusInternalServerError), records[1]["status"])
+}
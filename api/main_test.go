@@ -1,12 +1,65 @@
 package api
 
 import (
+	"io"
+	"log/slog"
 	"os"
 	"testing"
+	"time"
 
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/fx"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 )
 
+// testRates are the exchange rates served to the test server.
+// There is no rate between USD and INR.
+var testRates = []fx.Rate{
+	{From: string(db.CurrencyEUR), To: string(db.CurrencyUSD), Rate: decimal.RequireFromString("1.1")},
+	{From: string(db.CurrencyEUR), To: string(db.CurrencyINR), Rate: decimal.RequireFromString("90")},
+}
+
+// testCurrencies is the currency catalogue of the test server.
+// GBP is disabled.
+var testCurrencies = []db.CurrencyInfo{
+	{Code: db.CurrencyEUR, Exponent: 2, Symbol: "€", Enabled: true},
+	{Code: "GBP", Exponent: 2, Symbol: "£", Enabled: false},
+	{Code: db.CurrencyINR, Exponent: 2, Symbol: "₹", Enabled: true},
+	{Code: db.CurrencyUSD, Exponent: 2, Symbol: "$", Enabled: true},
+}
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := utils.Config{
+		TokenSymmetricKey:    utils.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		RefreshTokenDuration: time.Hour,
+		IdempotencyKeyTTL:    time.Hour,
+		CurrencyCacheTTL:     time.Minute,
+	}
+
+	// The currency catalogue is read by the request validators
+	if mockStore, ok := store.(*mockdb.MockStore); ok {
+		mockStore.EXPECT().
+			ListCurrencies(gomock.Any()).
+			AnyTimes().
+			Return(testCurrencies, nil)
+	}
+
+	rateProvider, err := fx.NewStaticRateProvider(testRates)
+	assert.NoError(t, err)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server, err := NewServer(config, store, rateProvider, logger)
+	assert.NoError(t, err)
+
+	return server
+}
+
 func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+	authorizationPayloadKey = "authorization_payload"
+)
+
+// authMiddleware creates a gin middleware for authorization.
+// It rejects requests without a valid bearer token and stores
+// the token payload in the context for the handlers.
+func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+		if len(authorizationHeader) == 0 {
+			errorResponse(ctx, newAPIError(http.StatusUnauthorized, codeUnauthorized, "authorization header is not provided"))
+			return
+		}
+
+		fields := strings.Fields(authorizationHeader)
+		if len(fields) != 2 {
+			errorResponse(ctx, newAPIError(http.StatusUnauthorized, codeUnauthorized, "invalid authorization header format"))
+			return
+		}
+
+		authorizationType := strings.ToLower(fields[0])
+		if authorizationType != authorizationTypeBearer {
+			message := fmt.Sprintf("unsupported authorization type %s", authorizationType)
+			errorResponse(ctx, newAPIError(http.StatusUnauthorized, codeUnauthorized, message))
+			return
+		}
+
+		accessToken := fields[1]
+		payload, err := tokenMaker.VerifyToken(accessToken, token.TokenTypeAccess)
+		if err != nil {
+			errorResponse(ctx, err)
+			return
+		}
+
+		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Next()
+	}
+}
+
+// roleMiddleware creates a gin middleware which only lets through
+// users with the given role. It must run after authMiddleware.
+func roleMiddleware(role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if authPayload.Role != role {
+			message := fmt.Sprintf("user doesn't have the %s role", role)
+			errorResponse(ctx, newAPIError(http.StatusForbidden, codeForbidden, message))
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	errScheduleStartInPast     = newAPIError(http.StatusBadRequest, "schedule_start_in_past", "start_at must be in the future")
+	errScheduleEndBeforeStart  = newAPIError(http.StatusBadRequest, "schedule_end_before_start", "end_at must be after start_at")
+	errScheduleIntervalForOnce = newAPIError(
+		http.StatusBadRequest,
+		"schedule_interval_for_once",
+		"interval_count is not allowed with the once frequency")
+	errScheduledTransferNotOwned = newAPIError(
+		http.StatusForbidden,
+		"scheduled_transfer_not_owned",
+		"scheduled transfer doesn't belong to the authenticated user")
+)
+
+type createScheduledTransferRequest struct {
+	FromAccountId int64                `json:"from_account_id" binding:"required,min=1"`
+	ToAccountId   int64                `json:"to_account_id" binding:"required,min=1"`
+	Amount        decimal.Decimal      `json:"amount" binding:"required,amount"`
+	Currency      db.Currency          `json:"currency" binding:"required,currency"`
+	Frequency     db.ScheduleFrequency `json:"frequency" binding:"required,oneof=once daily weekly monthly"`
+	IntervalCount int32                `json:"interval_count" binding:"omitempty,min=1,max=366"`
+	StartAt       time.Time            `json:"start_at" binding:"required"`
+	EndAt         *time.Time           `json:"end_at"`
+}
+
+// createScheduledTransferHandler creates a standing order between two accounts of the same currency.
+// Unlike a transfer, the exchange rate is unknown until the run, so no conversion is supported.
+func (server *Server) createScheduledTransferHandler(ctx *gin.Context) {
+	var req createScheduledTransferRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if !server.checkAmount(ctx, req.Amount, req.Currency) {
+		return
+	}
+
+	if req.Frequency == db.ScheduleFrequencyOnce && req.IntervalCount != 0 {
+		errorResponse(ctx, errScheduleIntervalForOnce)
+		return
+	}
+
+	if !req.StartAt.After(time.Now()) {
+		errorResponse(ctx, errScheduleStartInPast)
+		return
+	}
+
+	if req.EndAt != nil && !req.EndAt.After(req.StartAt) {
+		errorResponse(ctx, errScheduleEndBeforeStart)
+		return
+	}
+
+	fromAccount, ok := server.validateAccount(ctx, req.FromAccountId, req.Currency)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if fromAccount.Owner != authPayload.Username {
+		errorResponse(ctx, errAccountNotOwned)
+		return
+	}
+
+	// The scheduler would only record a failed run for every occurrence
+	if fromAccount.Status != db.AccountStatusActive {
+		errorResponse(ctx, &db.AccountNotActiveError{AccountID: fromAccount.ID, Status: fromAccount.Status})
+		return
+	}
+
+	toAccount, ok := server.validateAccount(ctx, req.ToAccountId, req.Currency)
+	if !ok {
+		return
+	}
+
+	if toAccount.IsSystem {
+		errorResponse(ctx, db.ErrSystemAccount)
+		return
+	}
+
+	if toAccount.Status == db.AccountStatusClosed {
+		errorResponse(ctx, &db.AccountNotActiveError{AccountID: toAccount.ID, Status: toAccount.Status})
+		return
+	}
+
+	intervalCount := req.IntervalCount
+	if intervalCount == 0 {
+		intervalCount = 1
+	}
+
+	arg := db.CreateScheduledTransferParams{
+		Owner:         authPayload.Username,
+		FromAccountID: req.FromAccountId,
+		ToAccountID:   req.ToAccountId,
+		Amount:        req.Amount,
+		Frequency:     req.Frequency,
+		IntervalCount: intervalCount,
+		StartAt:       pgtype.Timestamptz{Time: req.StartAt, Valid: true},
+	}
+	if req.EndAt != nil {
+		arg.EndAt = pgtype.Timestamptz{Time: *req.EndAt, Valid: true}
+	}
+
+	// The schedule is only created when start_at is also in the future for the
+	// database, whose clock is the one the scheduler runs the schedules with
+	schedule, err := server.store.CreateScheduledTransfer(ctx, arg)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			errorResponse(ctx, errScheduleStartInPast)
+			return
+		}
+
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedule)
+}
+
+type listScheduledTransfersRequest struct {
+	PageId   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listScheduledTransfersHandler(ctx *gin.Context) {
+	var req listScheduledTransfersRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	schedules, err := server.store.ListScheduledTransfersByOwner(ctx, db.ListScheduledTransfersByOwnerParams{
+		Owner:  authPayload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageId - 1) * req.PageSize,
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedules)
+}
+
+type scheduledTransferUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) pauseScheduledTransferHandler(ctx *gin.Context) {
+	server.setScheduledTransferStatus(ctx, db.ScheduledTransferStatusPaused)
+}
+
+func (server *Server) resumeScheduledTransferHandler(ctx *gin.Context) {
+	server.setScheduledTransferStatus(ctx, db.ScheduledTransferStatusActive)
+}
+
+func (server *Server) cancelScheduledTransferHandler(ctx *gin.Context) {
+	server.setScheduledTransferStatus(ctx, db.ScheduledTransferStatusCancelled)
+}
+
+// setScheduledTransferStatus moves a scheduled transfer of the authenticated user to status
+func (server *Server) setScheduledTransferStatus(ctx *gin.Context, status db.ScheduledTransferStatus) {
+	var uri scheduledTransferUri
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	schedule, err := server.store.GetScheduledTransfer(ctx, uri.ID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if schedule.Owner != authPayload.Username {
+		errorResponse(ctx, errScheduledTransferNotOwned)
+		return
+	}
+
+	schedule, err = server.store.SetScheduledTransferStatusTx(ctx, db.SetScheduledTransferStatusTxParams{
+		ID:     uri.ID,
+		Status: status,
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, schedule)
+}
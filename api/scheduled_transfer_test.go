@@ -0,0 +1,609 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateScheduledTransferApi(t *testing.T) {
+	fromAccount := createRandomAccount()
+	fromAccount.Currency = db.CurrencyEUR
+
+	toAccount := createRandomAccount()
+	toAccount.Currency = db.CurrencyEUR
+
+	toAccountUSD := createRandomAccount()
+	toAccountUSD.Currency = db.CurrencyUSD
+
+	systemAccount := createRandomAccount()
+	systemAccount.Currency = db.CurrencyEUR
+	systemAccount.IsSystem = true
+
+	frozenFromAccount := fromAccount
+	frozenFromAccount.Status = db.AccountStatusFrozen
+
+	closedToAccount := toAccount
+	closedToAccount.Status = db.AccountStatusClosed
+
+	startAt := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+	endAt := startAt.AddDate(1, 0, 0)
+
+	schedule := db.ScheduledTransfer{
+		ID:            utils.RandomNumber(1, 1000),
+		Owner:         fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        decimal.NewFromInt(500),
+		Frequency:     db.ScheduleFrequencyMonthly,
+		IntervalCount: 1,
+		StartAt:       pgtype.Timestamptz{Time: startAt, Valid: true},
+		EndAt:         pgtype.Timestamptz{Time: endAt, Valid: true},
+		NextRunAt:     pgtype.Timestamptz{Time: startAt, Valid: true},
+		Status:        db.ScheduledTransferStatusActive,
+	}
+
+	validBody := func(toAccountID int64) gin.H {
+		return gin.H{
+			"from_account_id": fromAccount.ID,
+			"to_account_id":   toAccountID,
+			"amount":          decimal.NewFromInt(500),
+			"currency":        db.CurrencyEUR,
+			"frequency":       db.ScheduleFrequencyMonthly,
+			"start_at":        startAt,
+			"end_at":          endAt,
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		body             gin.H
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: validBody(toAccount.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+				arg := db.CreateScheduledTransferParams{
+					Owner:         fromAccount.Owner,
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Amount:        decimal.NewFromInt(500),
+					Frequency:     db.ScheduleFrequencyMonthly,
+					IntervalCount: 1,
+					StartAt:       pgtype.Timestamptz{Time: startAt, Valid: true},
+					EndAt:         pgtype.Timestamptz{Time: endAt, Valid: true},
+				}
+				store.EXPECT().
+					CreateScheduledTransfer(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(schedule, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotSchedule db.ScheduledTransfer
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotSchedule)
+				assert.NoError(t, err)
+				assert.Equal(t, schedule.ID, gotSchedule.ID)
+				assert.Equal(t, db.ScheduledTransferStatusActive, gotSchedule.Status)
+			},
+		},
+		{
+			name: "StartInPast",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(500),
+				"currency":        db.CurrencyEUR,
+				"frequency":       db.ScheduleFrequencyOnce,
+				"start_at":        time.Now().Add(-time.Hour),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errScheduleStartInPast, recorder.Body)
+			},
+		},
+		{
+			name: "StartInPastForDatabase",
+			body: validBody(toAccount.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					CreateScheduledTransfer(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ScheduledTransfer{}, pgx.ErrNoRows)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errScheduleStartInPast, recorder.Body)
+			},
+		},
+		{
+			name: "IntervalCountForOnce",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(500),
+				"currency":        db.CurrencyEUR,
+				"frequency":       db.ScheduleFrequencyOnce,
+				"interval_count":  2,
+				"start_at":        startAt,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errScheduleIntervalForOnce, recorder.Body)
+			},
+		},
+		{
+			name: "FromAccountFrozen",
+			body: validBody(toAccount.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(frozenFromAccount, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, &db.AccountNotActiveError{AccountID: fromAccount.ID, Status: db.AccountStatusFrozen}, recorder.Body)
+			},
+		},
+		{
+			name: "ToAccountClosed",
+			body: validBody(toAccount.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(closedToAccount, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+				assertError(t, &db.AccountNotActiveError{AccountID: toAccount.ID, Status: db.AccountStatusClosed}, recorder.Body)
+			},
+		},
+		{
+			name: "EndBeforeStart",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(500),
+				"currency":        db.CurrencyEUR,
+				"frequency":       db.ScheduleFrequencyDaily,
+				"start_at":        startAt,
+				"end_at":          startAt.Add(-time.Minute),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errScheduleEndBeforeStart, recorder.Body)
+			},
+		},
+		{
+			name: "InvalidFrequency",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(500),
+				"currency":        db.CurrencyEUR,
+				"frequency":       "yearly",
+				"start_at":        startAt,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "FromAccountNotOwned",
+			body: validBody(toAccount.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, toAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, errAccountNotOwned, recorder.Body)
+			},
+		},
+		{
+			name: "ToAccountCurrencyMismatch",
+			body: validBody(toAccountUSD.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccountUSD.ID)).Times(1).Return(toAccountUSD, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ToSystemAccount",
+			body: validBody(systemAccount.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(systemAccount.ID)).Times(1).Return(systemAccount, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, db.ErrSystemAccount, recorder.Body)
+			},
+		},
+		{
+			name: "InternalServerError",
+			body: validBody(toAccount.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					CreateScheduledTransfer(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ScheduledTransfer{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: validBody(toAccount.ID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/scheduled-transfers", bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func TestListScheduledTransfersApi(t *testing.T) {
+	user, _ := createRandomUser()
+
+	schedules := []db.ScheduledTransfer{
+		{ID: 1, Owner: user.Name, Frequency: db.ScheduleFrequencyDaily, Status: db.ScheduledTransferStatusActive},
+		{ID: 2, Owner: user.Name, Frequency: db.ScheduleFrequencyOnce, Status: db.ScheduledTransferStatusCompleted},
+	}
+
+	testCases := []struct {
+		name             string
+		query            string
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "page_id=2&page_size=5",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.ListScheduledTransfersByOwnerParams{
+					Owner:  user.Name,
+					Limit:  5,
+					Offset: 5,
+				}
+
+				store.EXPECT().
+					ListScheduledTransfersByOwner(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(schedules, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotSchedules []db.ScheduledTransfer
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotSchedules)
+				assert.NoError(t, err)
+				assert.Len(t, gotSchedules, 2)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			query: "page_id=1&page_size=50",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().ListScheduledTransfersByOwner(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalServerError",
+			query: "page_id=1&page_size=5",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListScheduledTransfersByOwner(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/scheduled-transfers?"+tc.query, nil)
+			assert.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Name, utils.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func TestSetScheduledTransferStatusApi(t *testing.T) {
+	user, _ := createRandomUser()
+	otherUser, _ := createRandomUser()
+
+	schedule := db.ScheduledTransfer{
+		ID:        utils.RandomNumber(1, 1000),
+		Owner:     user.Name,
+		Frequency: db.ScheduleFrequencyWeekly,
+		Status:    db.ScheduledTransferStatusActive,
+	}
+
+	testCases := []struct {
+		name             string
+		action           string
+		scheduleID       int64
+		username         string
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "PauseOK",
+			action:     "pause",
+			scheduleID: schedule.ID,
+			username:   user.Name,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				pausedSchedule := schedule
+				pausedSchedule.Status = db.ScheduledTransferStatusPaused
+
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().
+					SetScheduledTransferStatusTx(gomock.Any(), gomock.Eq(db.SetScheduledTransferStatusTxParams{
+						ID:     schedule.ID,
+						Status: db.ScheduledTransferStatusPaused,
+					})).
+					Times(1).
+					Return(pausedSchedule, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotSchedule db.ScheduledTransfer
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotSchedule)
+				assert.NoError(t, err)
+				assert.Equal(t, db.ScheduledTransferStatusPaused, gotSchedule.Status)
+			},
+		},
+		{
+			name:       "ResumeOK",
+			action:     "resume",
+			scheduleID: schedule.ID,
+			username:   user.Name,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().
+					SetScheduledTransferStatusTx(gomock.Any(), gomock.Eq(db.SetScheduledTransferStatusTxParams{
+						ID:     schedule.ID,
+						Status: db.ScheduledTransferStatusActive,
+					})).
+					Times(1).
+					Return(schedule, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "CancelInvalidTransition",
+			action:     "cancel",
+			scheduleID: schedule.ID,
+			username:   user.Name,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().
+					SetScheduledTransferStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ScheduledTransfer{}, &db.ScheduleTransitionError{
+						ScheduledTransferID: schedule.ID,
+						From:                db.ScheduledTransferStatusCompleted,
+						To:                  db.ScheduledTransferStatusCancelled,
+					})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:       "NotFound",
+			action:     "cancel",
+			scheduleID: schedule.ID,
+			username:   user.Name,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).
+					Times(1).
+					Return(db.ScheduledTransfer{}, pgx.ErrNoRows)
+				store.EXPECT().SetScheduledTransferStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "NotOwned",
+			action:     "cancel",
+			scheduleID: schedule.ID,
+			username:   otherUser.Name,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().SetScheduledTransferStatusTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, errScheduledTransferNotOwned, recorder.Body)
+			},
+		},
+		{
+			name:       "InternalServerError",
+			action:     "pause",
+			scheduleID: schedule.ID,
+			username:   user.Name,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(schedule.ID)).Times(1).Return(schedule, nil)
+				store.EXPECT().
+					SetScheduledTransferStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ScheduledTransfer{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:       "BadRequest",
+			action:     "pause",
+			scheduleID: 0,
+			username:   user.Name,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/scheduled-transfers/%d/%s", tc.scheduleID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			assert.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, utils.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
@@ -1,47 +1,119 @@
 package api
 
 import (
+	"fmt"
+	"log/slog"
+
 	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/fx"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
 	"github.com/gin-gonic/gin"
 )
 
 // Server serves HTTP requests
 type Server struct {
-	store  db.Store
-	router *gin.Engine
+	config       utils.Config
+	store        db.Store
+	tokenMaker   token.Maker
+	rateProvider fx.FXRateProvider
+	currencies   *currencyCatalogue
+	router       *gin.Engine
+	logger       *slog.Logger
 }
 
-// NewServer creates an instance of Server and setup routing
-func NewServer(store db.Store) *Server {
+// NewServer creates an instance of Server and setup routing.
+// Requests are logged to logger.
+func NewServer(config utils.Config, store db.Store, rateProvider fx.FXRateProvider, logger *slog.Logger) (*Server, error) {
+	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+
 	server := &Server{
-		store: store,
+		config:       config,
+		store:        store,
+		tokenMaker:   tokenMaker,
+		rateProvider: rateProvider,
+		currencies:   newCurrencyCatalogue(store, config.CurrencyCacheTTL),
+		logger:       logger,
+	}
+
+	err = registerValidators()
+	if err != nil {
+		return nil, fmt.Errorf("cannot register validators: %w", err)
 	}
 
-	router := gin.Default()
+	server.setupRouter()
+
+	return server, nil
+}
+
+func (server *Server) setupRouter() {
+	router := gin.New()
+	router.Use(requestIDMiddleware(), accessLogMiddleware(server.logger), recoveryMiddleware(server.logger))
+
+	// The store reads the audit info attached to the request context by auditMiddleware
+	router.ContextWithFallback = true
 
 	// add routes to the router
-	router.POST("/api/accounts", server.createAccountHandler)
-	router.GET("/api/accounts/:id", server.getAccountHandler)
-	router.GET("/api/accounts", server.listAccountsHandler)
-	router.PUT("/api/accounts", server.updateAccountsHandler)
-	router.DELETE("/api/accounts/:id", server.deleteAccountsHandler)
+	publicRoutes := router.Group("/").Use(auditMiddleware())
 
-	router.POST("/api/transfers", server.createTransferHandler)
+	publicRoutes.POST("/api/users", server.createUserHandler)
+	publicRoutes.POST("/api/users/login", server.loginUserHandler)
+	publicRoutes.POST("/api/tokens/renew", server.renewAccessTokenHandler)
+	publicRoutes.GET("/api/currencies", server.listCurrenciesHandler)
 
-	router.POST("/api/users", server.createUserHandler)
+	// routes below require a valid access token
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), auditMiddleware())
 
-	server.router = router
+	authRoutes.POST("/api/accounts", server.createAccountHandler)
+	authRoutes.GET("/api/accounts/:id", server.getAccountHandler)
+	authRoutes.GET("/api/accounts", server.listAccountsHandler)
+	authRoutes.GET("/api/accounts/:id/entries", server.accountStatementHandler)
+	authRoutes.GET("/api/accounts/:id/transfers", server.listAccountTransfersHandler)
+	authRoutes.POST("/api/accounts/:id/withdrawals", server.withdrawalHandler)
+	authRoutes.POST("/api/accounts/:id/close", server.closeAccountHandler)
+	authRoutes.GET("/api/accounts/:id/limits", server.listAccountLimitsHandler)
 
-	return server
+	authRoutes.POST("/api/transfers", server.createTransferHandler)
+	authRoutes.GET("/api/transfers/:id", server.getTransferHandler)
+	authRoutes.POST("/api/transfers/:id/reverse", server.reverseTransferHandler)
+
+	authRoutes.POST("/api/scheduled-transfers", server.createScheduledTransferHandler)
+	authRoutes.GET("/api/scheduled-transfers", server.listScheduledTransfersHandler)
+	authRoutes.POST("/api/scheduled-transfers/:id/pause", server.pauseScheduledTransferHandler)
+	authRoutes.POST("/api/scheduled-transfers/:id/resume", server.resumeScheduledTransferHandler)
+	authRoutes.POST("/api/scheduled-transfers/:id/cancel", server.cancelScheduledTransferHandler)
+
+	authRoutes.POST("/api/webhooks", server.createWebhookEndpointHandler)
+	authRoutes.GET("/api/webhooks", server.listWebhookEndpointsHandler)
+	authRoutes.DELETE("/api/webhooks/:id", server.disableWebhookEndpointHandler)
+	authRoutes.GET("/api/webhooks/:id/deliveries", server.listWebhookDeliveriesHandler)
+
+	authRoutes.DELETE("/api/sessions/:id", server.deleteSessionHandler)
+
+	// routes below are restricted to admins
+	adminRoutes := router.Group("/").Use(
+		authMiddleware(server.tokenMaker),
+		roleMiddleware(utils.AdminRole),
+		auditMiddleware())
+
+	adminRoutes.POST("/api/accounts/:id/deposits", server.depositHandler)
+	adminRoutes.POST("/api/accounts/:id/adjustments", server.createAdjustmentHandler)
+	adminRoutes.POST("/api/accounts/:id/freeze", server.freezeAccountHandler)
+	adminRoutes.POST("/api/accounts/:id/unfreeze", server.unfreezeAccountHandler)
+	adminRoutes.PUT("/api/accounts/:id/limits", server.setAccountLimitHandler)
+	adminRoutes.PUT("/api/users/:name/limits/:currency", server.setUserLimitHandler)
+	adminRoutes.GET("/api/admin/limits", server.listLimitsHandler)
+	adminRoutes.DELETE("/api/admin/limits/:id", server.deleteLimitHandler)
+	adminRoutes.GET("/api/admin/audit", server.listAuditEventsHandler)
+	adminRoutes.GET("/api/admin/audit/verify", server.verifyAuditChainHandler)
+
+	server.router = router
 }
 
 // Start runs HTTP server on a specific address
 func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
-
-func errorResponse(err error) gin.H {
-	return gin.H{
-		"error": err.Error(),
-	}
-}
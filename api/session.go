@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var errSessionNotOwned = newAPIError(http.StatusForbidden, "session_not_owned", "session doesn't belong to the authenticated user")
+
+type deleteSessionRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// deleteSessionHandler logs the user out by blocking the session,
+// so that its refresh token can no longer be used to renew access tokens
+func (server *Server) deleteSessionHandler(ctx *gin.Context) {
+	var req deleteSessionRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	sessionID, err := uuid.Parse(req.ID)
+	if err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	session, err := server.store.GetSession(ctx, sessionID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if session.Username != authPayload.Username {
+		errorResponse(ctx, errSessionNotOwned)
+		return
+	}
+
+	_, err = server.store.BlockSession(ctx, session.ID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+const defaultStatementPageSize = 20
+
+var (
+	errInvalidStatementPeriod = newAPIError(http.StatusBadRequest, "invalid_statement_period", "from must be before to")
+	errInvalidCursor          = newAPIError(http.StatusBadRequest, "invalid_cursor", "invalid cursor")
+)
+
+type accountStatementRequest struct {
+	From     time.Time `form:"from"`
+	To       time.Time `form:"to"`
+	Cursor   string    `form:"cursor"`
+	PageSize int32     `form:"page_size" binding:"omitempty,min=5,max=100"`
+}
+
+type statementEntryResponse struct {
+	ID             int64           `json:"id"`
+	Amount         decimal.Decimal `json:"amount"`
+	RunningBalance decimal.Decimal `json:"running_balance"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type accountStatementResponse struct {
+	AccountID      int64                    `json:"account_id"`
+	Currency       db.Currency              `json:"currency"`
+	From           *time.Time               `json:"from,omitempty"`
+	To             time.Time                `json:"to"`
+	OpeningBalance decimal.Decimal          `json:"opening_balance"`
+	ClosingBalance decimal.Decimal          `json:"closing_balance"`
+	Entries        []statementEntryResponse `json:"entries"`
+	NextCursor     string                   `json:"next_cursor,omitempty"`
+}
+
+func (server *Server) accountStatementHandler(ctx *gin.Context) {
+	var uriReq getAccountRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	var req accountStatementRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+
+	if req.PageSize == 0 {
+		req.PageSize = defaultStatementPageSize
+	}
+
+	if !req.From.Before(req.To) {
+		errorResponse(ctx, errInvalidStatementPeriod)
+		return
+	}
+
+	var after *db.StatementCursor
+	if req.Cursor != "" {
+		cursor, err := decodeStatementCursor(req.Cursor)
+		if err != nil || cursor.CreatedAt.Before(req.From) || !cursor.CreatedAt.Before(req.To) {
+			errorResponse(ctx, errInvalidCursor)
+			return
+		}
+
+		after = &cursor
+	}
+
+	if _, ok := server.getOwnedAccount(ctx, uriReq.ID); !ok {
+		return
+	}
+
+	result, err := server.store.AccountStatementTx(ctx, db.AccountStatementTxParams{
+		AccountID: uriReq.ID,
+		From:      req.From,
+		To:        req.To,
+		After:     after,
+		PageSize:  req.PageSize,
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	response := accountStatementResponse{
+		AccountID:      result.Account.ID,
+		Currency:       result.Account.Currency,
+		To:             req.To,
+		OpeningBalance: result.OpeningBalance,
+		ClosingBalance: result.ClosingBalance,
+		Entries:        make([]statementEntryResponse, len(result.Entries)),
+	}
+
+	if !req.From.IsZero() {
+		response.From = &req.From
+	}
+
+	for i, entry := range result.Entries {
+		response.Entries[i] = statementEntryResponse{
+			ID:             entry.ID,
+			Amount:         entry.Amount,
+			RunningBalance: entry.RunningBalance,
+			CreatedAt:      entry.CreatedAt.Time,
+		}
+	}
+
+	if result.Next != nil {
+		response.NextCursor, err = encodeStatementCursor(*result.Next)
+		if err != nil {
+			errorResponse(ctx, err)
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// encodeStatementCursor encodes the cursor into an opaque string which can be used in urls
+func encodeStatementCursor(cursor db.StatementCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeStatementCursor decodes a cursor encoded by encodeStatementCursor
+func decodeStatementCursor(value string) (db.StatementCursor, error) {
+	var cursor db.StatementCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, err
+	}
+
+	err = json.Unmarshal(data, &cursor)
+	if err != nil {
+		return cursor, err
+	}
+
+	if cursor.ID < 1 {
+		return cursor, errInvalidCursor
+	}
+
+	return cursor, nil
+}
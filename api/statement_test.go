@@ -0,0 +1,351 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAccountStatementApi(t *testing.T) {
+	account := createRandomAccount()
+
+	from := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+	to := time.Now().UTC().Truncate(time.Second)
+
+	entries := []db.StatementEntry{
+		{
+			Entry: db.Entry{
+				ID:        utils.RandomNumber(1, 1000),
+				AccountID: account.ID,
+				Amount:    decimal.NewFromInt(10),
+				CreatedAt: pgtype.Timestamptz{Time: from.Add(time.Hour), Valid: true},
+			},
+			RunningBalance: decimal.NewFromInt(110),
+		},
+		{
+			Entry: db.Entry{
+				ID:        utils.RandomNumber(1001, 2000),
+				AccountID: account.ID,
+				Amount:    decimal.NewFromInt(-30),
+				CreatedAt: pgtype.Timestamptz{Time: from.Add(2 * time.Hour), Valid: true},
+			},
+			RunningBalance: decimal.NewFromInt(80),
+		},
+	}
+
+	next := db.StatementCursor{
+		CreatedAt: entries[1].CreatedAt.Time,
+		ID:        entries[1].ID,
+	}
+	nextCursor, err := encodeStatementCursor(next)
+	assert.NoError(t, err)
+
+	statement := db.AccountStatementTxResult{
+		Account:        account,
+		OpeningBalance: decimal.NewFromInt(100),
+		ClosingBalance: decimal.NewFromInt(50),
+		Entries:        entries,
+		Next:           &next,
+	}
+
+	authorized := func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+		addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+	}
+
+	testCases := []struct {
+		name             string
+		accountId        int64
+		query            url.Values
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			accountId: account.ID,
+			query: url.Values{
+				"from":      {from.Format(time.RFC3339)},
+				"to":        {to.Format(time.RFC3339)},
+				"page_size": {"5"},
+			},
+			setupAuth: authorized,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				arg := db.AccountStatementTxParams{
+					AccountID: account.ID,
+					From:      from,
+					To:        to,
+					PageSize:  5,
+				}
+				store.EXPECT().
+					AccountStatementTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(statement, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				response := readStatement(t, recorder)
+				assert.Equal(t, account.ID, response.AccountID)
+				assert.Equal(t, account.Currency, response.Currency)
+				assert.True(t, statement.OpeningBalance.Equal(response.OpeningBalance))
+				assert.True(t, statement.ClosingBalance.Equal(response.ClosingBalance))
+				assert.Equal(t, nextCursor, response.NextCursor)
+
+				assert.Len(t, response.Entries, len(entries))
+				for i, entry := range response.Entries {
+					assert.Equal(t, entries[i].ID, entry.ID)
+					assert.True(t, entries[i].Amount.Equal(entry.Amount))
+					assert.True(t, entries[i].RunningBalance.Equal(entry.RunningBalance))
+					assert.WithinDuration(t, entries[i].CreatedAt.Time, entry.CreatedAt, time.Second)
+				}
+			},
+		},
+		{
+			name:      "WithCursor",
+			accountId: account.ID,
+			query: url.Values{
+				"from":   {from.Format(time.RFC3339)},
+				"to":     {to.Format(time.RFC3339)},
+				"cursor": {nextCursor},
+			},
+			setupAuth: authorized,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					AccountStatementTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.AccountStatementTxParams) (db.AccountStatementTxResult, error) {
+						assert.NotNil(t, arg.After)
+						assert.Equal(t, next.ID, arg.After.ID)
+						assert.True(t, next.CreatedAt.Equal(arg.After.CreatedAt))
+						assert.Equal(t, int32(defaultStatementPageSize), arg.PageSize)
+
+						return db.AccountStatementTxResult{
+							Account:        account,
+							OpeningBalance: statement.OpeningBalance,
+							ClosingBalance: statement.ClosingBalance,
+						}, nil
+					})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				response := readStatement(t, recorder)
+				assert.Empty(t, response.Entries)
+				assert.Empty(t, response.NextCursor)
+			},
+		},
+		{
+			name:      "InvalidCursor",
+			accountId: account.ID,
+			query: url.Values{
+				"cursor": {"invalid"},
+			},
+			setupAuth: authorized,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AccountStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errInvalidCursor, recorder.Body)
+			},
+		},
+		{
+			name:      "CursorOutOfPeriod",
+			accountId: account.ID,
+			query: url.Values{
+				"from":   {from.Add(3 * time.Hour).Format(time.RFC3339)},
+				"to":     {to.Format(time.RFC3339)},
+				"cursor": {nextCursor},
+			},
+			setupAuth: authorized,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AccountStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errInvalidCursor, recorder.Body)
+			},
+		},
+		{
+			name:      "InvalidPeriod",
+			accountId: account.ID,
+			query: url.Values{
+				"from": {to.Format(time.RFC3339)},
+				"to":   {from.Format(time.RFC3339)},
+			},
+			setupAuth: authorized,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AccountStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errInvalidStatementPeriod, recorder.Body)
+			},
+		},
+		{
+			name:      "InvalidDate",
+			accountId: account.ID,
+			query: url.Values{
+				"from": {"yesterday"},
+			},
+			setupAuth: authorized,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AccountStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "InvalidPageSize",
+			accountId: account.ID,
+			query: url.Values{
+				"page_size": {"1000"},
+			},
+			setupAuth: authorized,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AccountStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "UnauthorizedUser",
+			accountId: account.ID,
+			query:     url.Values{},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "otheruser", utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().AccountStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, errAccountNotOwned, recorder.Body)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			accountId: account.ID,
+			query:     url.Values{},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().AccountStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			accountId: account.ID,
+			query:     url.Values{},
+			setupAuth: authorized,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(db.Account{}, pgx.ErrNoRows)
+
+				store.EXPECT().AccountStatementTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+				assertError(t, pgx.ErrNoRows, recorder.Body)
+			},
+		},
+		{
+			name:      "InternalServerError",
+			accountId: account.ID,
+			query:     url.Values{},
+			setupAuth: authorized,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+					Times(1).
+					Return(account, nil)
+
+				store.EXPECT().
+					AccountStatementTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountStatementTxResult{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/accounts/%d/entries?%s", tc.accountId, tc.query.Encode())
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			assert.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func readStatement(t *testing.T, recorder *httptest.ResponseRecorder) accountStatementResponse {
+	data, err := io.ReadAll(recorder.Body)
+	assert.NoError(t, err)
+
+	var response accountStatementResponse
+	err = json.Unmarshal(data, &response)
+	assert.NoError(t, err)
+
+	return response
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	errBlockedSession    = newAPIError(http.StatusUnauthorized, "blocked_session", "blocked session")
+	errIncorrectSession  = newAPIError(http.StatusUnauthorized, "incorrect_session", "incorrect session user")
+	errMismatchedSession = newAPIError(http.StatusUnauthorized, "mismatched_session", "mismatched session token")
+	errExpiredSession    = newAPIError(http.StatusUnauthorized, "expired_session", "expired session")
+)
+
+type renewAccessTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type renewAccessTokenResponse struct {
+	AccessToken          string    `json:"access_token"`
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+}
+
+func (server *Server) renewAccessTokenHandler(ctx *gin.Context) {
+	var req renewAccessTokenRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken, token.TokenTypeRefresh)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	session, err := server.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	if session.IsBlocked {
+		errorResponse(ctx, errBlockedSession)
+		return
+	}
+
+	if session.Username != refreshPayload.Username {
+		errorResponse(ctx, errIncorrectSession)
+		return
+	}
+
+	if session.RefreshToken != req.RefreshToken {
+		errorResponse(ctx, errMismatchedSession)
+		return
+	}
+
+	if time.Now().After(session.ExpiresAt.Time) {
+		errorResponse(ctx, errExpiredSession)
+		return
+	}
+
+	// The role is read again rather than taken from the refresh token,
+	// so that a demoted admin does not keep admin access until it expires
+	user, err := server.store.GetUser(ctx, refreshPayload.Username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			errorResponse(ctx, errIncorrectSession)
+			return
+		}
+
+		errorResponse(ctx, err)
+		return
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
+		user.Name,
+		user.Role,
+		token.TokenTypeAccess,
+		server.config.AccessTokenDuration)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	result := renewAccessTokenResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,351 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRenewAccessTokenApi(t *testing.T) {
+	user, _ := createRandomUser()
+
+	newSession := func(refreshToken string, payload *token.Payload) db.Session {
+		return db.Session{
+			ID:           payload.ID,
+			Username:     payload.Username,
+			RefreshToken: refreshToken,
+			ExpiresAt:    pgtype.Timestamptz{Time: payload.ExpiredAt, Valid: true},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		body             func(refreshToken string) gin.H
+		buildStubFunc    func(store *mockdb.MockStore, refreshToken string, payload *token.Payload)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubFunc: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(newSession(refreshToken, payload), nil)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(payload.Username)).
+					Times(1).
+					Return(user, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var response renewAccessTokenResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.NotEmpty(t, response.AccessToken)
+				assert.NotZero(t, response.AccessTokenExpiresAt)
+			},
+		},
+		{
+			name: "InvalidToken",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": "invalid"}
+			},
+			buildStubFunc: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assertError(t, token.ErrInvalidToken, recorder.Body)
+			},
+		},
+		{
+			name: "SessionNotFound",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubFunc: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(db.Session{}, pgx.ErrNoRows)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+				assertError(t, pgx.ErrNoRows, recorder.Body)
+			},
+		},
+		{
+			name: "BlockedSession",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubFunc: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := newSession(refreshToken, payload)
+				session.IsBlocked = true
+
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assertError(t, errBlockedSession, recorder.Body)
+			},
+		},
+		{
+			name: "IncorrectSessionUser",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubFunc: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := newSession(refreshToken, payload)
+				session.Username = "otheruser"
+
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assertError(t, errIncorrectSession, recorder.Body)
+			},
+		},
+		{
+			name: "MismatchedSessionToken",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubFunc: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := newSession("another-token", payload)
+
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assertError(t, errMismatchedSession, recorder.Body)
+			},
+		},
+		{
+			name: "ExpiredSession",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubFunc: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := newSession(refreshToken, payload)
+				session.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(session, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assertError(t, errExpiredSession, recorder.Body)
+			},
+		},
+		{
+			name: "UserNotFound",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubFunc: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(newSession(refreshToken, payload), nil)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(payload.Username)).
+					Times(1).
+					Return(db.User{}, pgx.ErrNoRows)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assertError(t, errIncorrectSession, recorder.Body)
+			},
+		},
+		{
+			name: "GetUserError",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubFunc: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(newSession(refreshToken, payload), nil)
+
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(payload.Username)).
+					Times(1).
+					Return(db.User{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+		{
+			name: "InternalServerError",
+			body: func(refreshToken string) gin.H {
+				return gin.H{"refresh_token": refreshToken}
+			},
+			buildStubFunc: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+					Times(1).
+					Return(db.Session{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+		{
+			name: "BadRequest",
+			body: func(refreshToken string) gin.H {
+				return gin.H{}
+			},
+			buildStubFunc: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			refreshToken, payload, err := server.tokenMaker.CreateToken(user.Name, user.Role, token.TokenTypeRefresh, time.Hour)
+			assert.NoError(t, err)
+
+			tc.buildStubFunc(store, refreshToken, payload)
+			recorder := httptest.NewRecorder()
+
+			url := "/api/tokens/renew"
+			data, err := json.Marshal(tc.body(refreshToken))
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func TestRenewAccessTokenApiWithAccessToken(t *testing.T) {
+	user, _ := createRandomUser()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	// Only refresh tokens can renew access tokens
+	accessToken, _, err := server.tokenMaker.CreateToken(user.Name, user.Role, token.TokenTypeAccess, time.Hour)
+	assert.NoError(t, err)
+
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	data, err := json.Marshal(gin.H{"refresh_token": accessToken})
+	assert.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/api/tokens/renew", bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assertError(t, token.ErrInvalidToken, recorder.Body)
+}
+
+func TestRenewAccessTokenApiWithDemotedUser(t *testing.T) {
+	user, _ := createRandomUser()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	// The refresh token was created while the user was an admin
+	refreshToken, payload, err := server.tokenMaker.CreateToken(user.Name, utils.AdminRole, token.TokenTypeRefresh, time.Hour)
+	assert.NoError(t, err)
+
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(payload.ID)).
+		Times(1).
+		Return(db.Session{
+			ID:           payload.ID,
+			Username:     payload.Username,
+			RefreshToken: refreshToken,
+			ExpiresAt:    pgtype.Timestamptz{Time: payload.ExpiredAt, Valid: true},
+		}, nil)
+
+	user.Role = utils.DepositorRole
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(user.Name)).
+		Times(1).
+		Return(user, nil)
+
+	data, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+	assert.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/api/tokens/renew", bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response renewAccessTokenResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &response)
+	assert.NoError(t, err)
+
+	accessPayload, err := server.tokenMaker.VerifyToken(response.AccessToken, token.TokenTypeAccess)
+	assert.NoError(t, err)
+	assert.Equal(t, utils.DepositorRole, accessPayload.Role)
+}
@@ -1,35 +1,130 @@
 package api
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+var (
+	errTransferNotVisible = newAPIError(
+		http.StatusForbidden,
+		"transfer_not_visible",
+		"transfer doesn't involve any account of the authenticated user")
+	errInvalidAmountRange = newAPIError(
+		http.StatusBadRequest,
+		"invalid_amount_range",
+		"min_amount must not be greater than max_amount")
+)
+
+var (
+	errTransferNotReversible = newAPIError(
+		http.StatusForbidden,
+		"transfer_not_reversible",
+		"only the receiver of the transfer or an admin can reverse it")
+	errInvalidReversalAmount = newAPIError(
+		http.StatusBadRequest,
+		"invalid_reversal_amount",
+		"reversal amount must be positive and within the allowed range")
+)
+
+var errIdempotencyKeyTooLong = newAPIError(
+	http.StatusBadRequest,
+	"idempotency_key_too_long",
+	fmt.Sprintf("idempotency key must be at most %d characters", maxIdempotencyKeyLength))
+
 type createTransferRequest struct {
 	FromAccountId int64           `json:"from_account_id" binding:"required,min=1"`
-	ToAccountId   int64           `json:"to_account_id" binding:"required,min=1"`
-	Amount        decimal.Decimal `json:"amount" binding:"required"`
-	Currency      db.Currency     `json:"currency" binding:"required"`
+	ToAccountId   int64           `json:"to_account_id" binding:"required,min=1,nefield=FromAccountId"`
+	Amount        decimal.Decimal `json:"amount" binding:"required,amount"`
+	Currency      db.Currency     `json:"currency" binding:"required,currency"`
 }
 
 func (server *Server) createTransferHandler(ctx *gin.Context) {
 	var req createTransferRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, invalidRequest(err))
 		return
 	}
 
-	if !server.validateCurrency(ctx, req.FromAccountId, req.Currency) {
+	if !server.checkAmount(ctx, req.Amount, req.Currency) {
 		return
 	}
 
-	if !server.validateCurrency(ctx, req.ToAccountId, req.Currency) {
+	idempotencyKey := ctx.GetHeader(idempotencyKeyHeader)
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		errorResponse(ctx, errIdempotencyKeyTooLong)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	requestHash := hashRequest(req)
+
+	// A replay returns the stored result before anything else is checked,
+	// so that it doesn't depend on the accounts or on the current exchange rate
+	if idempotencyKey != "" {
+		result, replayed, ok := server.replayTransfer(ctx, authPayload.Username, idempotencyKey, requestHash)
+		if !ok {
+			return
+		}
+
+		if replayed {
+			ctx.Header(idempotentReplayedHeader, "true")
+			ctx.JSON(http.StatusOK, result)
+			return
+		}
+	}
+
+	fromAccount, ok := server.validateAccount(ctx, req.FromAccountId, req.Currency)
+	if !ok {
+		return
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		errorResponse(ctx, errAccountNotOwned)
+		return
+	}
+
+	toAccount, ok := server.getAccount(ctx, req.ToAccountId)
+	if !ok {
+		return
+	}
+
+	if toAccount.IsSystem {
+		errorResponse(ctx, db.ErrSystemAccount)
+		return
+	}
+
+	// The currency of the from account is the one of the request,
+	// checked above, the one of the to account must be enabled too
+	if _, ok := server.checkCurrency(ctx, toAccount.Currency); !ok {
+		return
+	}
+
+	// The amount is given in the currency of the from account,
+	// and is converted to the currency of the to account
+	rate, err := server.rateProvider.GetRate(ctx, string(fromAccount.Currency), string(toAccount.Currency))
+	if err != nil {
+		errorResponse(ctx, err)
 		return
 	}
 
@@ -37,48 +132,295 @@ func (server *Server) createTransferHandler(ctx *gin.Context) {
 		FromAccountId: req.FromAccountId,
 		ToAccountId:   req.ToAccountId,
 		Amount:        req.Amount,
+		ExchangeRate:  rate.Rate,
+		RateTimestamp: rate.Timestamp,
 	}
 
-	result, err := server.store.TransferTx(ctx, arg)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+	if idempotencyKey == "" {
+		result, err := server.store.TransferTx(ctx, arg)
+		if err != nil {
+			errorResponse(ctx, err)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusOK, result)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, result)
+	// The transaction looks up the key again, in case a concurrent
+	// request with the same key completed in the meantime
+	result, err := server.store.IdempotentTransferTx(ctx, db.IdempotentTransferTxParams{
+		TransferTxParams: arg,
+		Username:         authPayload.Username,
+		Key:              idempotencyKey,
+		RequestHash:      requestHash,
+		ExpiresAt:        time.Now().Add(server.config.IdempotencyKeyTTL),
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	if result.Replayed {
+		ctx.Header(idempotentReplayedHeader, "true")
+	}
+
+	ctx.JSON(http.StatusOK, result.TransferTxResult)
 }
 
-func (server *Server) validateCurrency(
+// replayTransfer returns the result stored with the idempotency key of the user, if any.
+// It writes the error response and returns false otherwise.
+func (server *Server) replayTransfer(
 	ctx *gin.Context,
-	accountId int64,
-	expectedCurrency db.Currency) bool {
+	username string,
+	idempotencyKey string,
+	requestHash string) (db.TransferTxResult, bool, bool) {
 
-	account, err := server.store.GetAccount(ctx, accountId)
+	storedKey, err := server.store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		Username: username,
+		Key:      idempotencyKey,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.TransferTxResult{}, false, true
+		}
+
+		errorResponse(ctx, err)
+		return db.TransferTxResult{}, false, false
+	}
+
+	result, replayed, err := db.ReplayTransfer(storedKey, requestHash)
+	if err != nil {
+		errorResponse(ctx, err)
+		return result, false, false
+	}
+
+	return result, replayed, true
+}
+
+type getTransferRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server *Server) getTransferHandler(ctx *gin.Context) {
+	var req getTransferRequest
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, req.ID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	// The transfer is visible to the owners of both its accounts
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	for _, accountId := range []int64{transfer.FromAccountID, transfer.ToAccountID} {
+		account, err := server.store.GetAccount(ctx, accountId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+
+			errorResponse(ctx, err)
+			return
+		}
+
+		if account.Owner == authPayload.Username {
+			ctx.JSON(http.StatusOK, transfer)
+			return
+		}
+	}
+
+	errorResponse(ctx, errTransferNotVisible)
+}
+
+type reverseTransferUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// reverseTransferRequest is in the currency of the to account of the transfer.
+// Without amount, everything left to reverse is reversed.
+type reverseTransferRequest struct {
+	Amount decimal.NullDecimal `json:"amount"`
+}
+
+// reverseTransferHandler refunds a transfer, fully or partially, with a compensating transfer
+// from its to account back to its from account. The receiver of the transfer can refund it,
+// and admins can reverse any transfer.
+func (server *Server) reverseTransferHandler(ctx *gin.Context) {
+	var uri reverseTransferUri
+	var req reverseTransferRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	// The body is optional
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if req.Amount.Valid && (!req.Amount.Decimal.IsPositive() || req.Amount.Decimal.GreaterThan(maxAmount)) {
+		errorResponse(ctx, errInvalidReversalAmount)
+		return
+	}
+
+	transfer, err := server.store.GetTransfer(ctx, uri.ID)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return false
+		errorResponse(ctx, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	isAdmin := authPayload.Role == utils.AdminRole
+	if !isAdmin {
+		toAccount, ok := server.getAccount(ctx, transfer.ToAccountID)
+		if !ok {
+			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return false
+		if toAccount.Owner != authPayload.Username {
+			errorResponse(ctx, errTransferNotReversible)
+			return
+		}
+
+		// Reversing a deposit would be a withdrawal without the checks of withdrawals
+		fromAccount, ok := server.getAccount(ctx, transfer.FromAccountID)
+		if !ok {
+			return
+		}
+
+		if fromAccount.IsSystem || toAccount.IsSystem {
+			errorResponse(ctx, db.ErrSystemAccount)
+			return
+		}
+	}
+
+	// Only the reversals made by an admin are not counted against the transfer limits
+	result, err := server.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransferID: uri.ID,
+		Amount:     req.Amount.Decimal,
+		SkipLimits: isAdmin,
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+const (
+	transferDirectionIn  = "in"
+	transferDirectionOut = "out"
+)
+
+type listAccountTransfersRequest struct {
+	Direction    string              `form:"direction" binding:"omitempty,oneof=in out both"`
+	Counterparty int64               `form:"counterparty" binding:"omitempty,min=1"`
+	MinAmount    decimal.NullDecimal `form:"min_amount"`
+	MaxAmount    decimal.NullDecimal `form:"max_amount"`
+	PageId       int32               `form:"page_id" binding:"required,min=1"`
+	PageSize     int32               `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listAccountTransfersHandler(ctx *gin.Context) {
+	var uriReq getAccountRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	var req listAccountTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if req.MinAmount.Valid && req.MaxAmount.Valid && req.MinAmount.Decimal.GreaterThan(req.MaxAmount.Decimal) {
+		errorResponse(ctx, errInvalidAmountRange)
+		return
+	}
+
+	if _, ok := server.getOwnedAccount(ctx, uriReq.ID); !ok {
+		return
+	}
+
+	arg := db.ListAccountTransfersParams{
+		AccountID:       uriReq.ID,
+		IncludeOutgoing: req.Direction != transferDirectionIn,
+		IncludeIncoming: req.Direction != transferDirectionOut,
+		Counterparty:    pgtype.Int8{Int64: req.Counterparty, Valid: req.Counterparty != 0},
+		MinAmount:       req.MinAmount,
+		MaxAmount:       req.MaxAmount,
+		LimitCount:      req.PageSize,
+		OffsetCount:     (req.PageId - 1) * req.PageSize,
+	}
+
+	transfers, err := server.store.ListAccountTransfers(ctx, arg)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, transfers)
+}
+
+// hashRequest returns the hex encoded SHA-256 of the canonical form of the request,
+// so that replays of an idempotency key can be compared with the original request.
+// The amount is normalized, so "10" and "10.00" are the same request.
+func hashRequest(req createTransferRequest) string {
+	canonical := fmt.Sprintf("%d|%d|%s|%s", req.FromAccountId, req.ToAccountId, req.Amount.String(), req.Currency)
+
+	hash := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(hash[:])
+}
+
+// validateAccount fetches the account and makes sure its currency is the expected one.
+// It writes the error response and returns false otherwise.
+func (server *Server) validateAccount(
+	ctx *gin.Context,
+	accountId int64,
+	expectedCurrency db.Currency) (db.Account, bool) {
+
+	account, ok := server.getAccount(ctx, accountId)
+	if !ok {
+		return account, false
 	}
 
 	if account.Currency != expectedCurrency {
-		err := fmt.Errorf(
+		errorResponse(ctx, errCurrencyMismatch(account, expectedCurrency))
+		return account, false
+	}
+
+	return account, true
+}
+
+func errCurrencyMismatch(account db.Account, givenCurrency db.Currency) error {
+	return newAPIError(
+		http.StatusBadRequest,
+		"currency_mismatch",
+		fmt.Sprintf(
 			"account [%d] currency mismatch, actual: %s, given: %s",
-			accountId,
+			account.ID,
 			account.Currency,
-			expectedCurrency)
+			givenCurrency))
+}
 
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return false
+// getAccount fetches the account.
+// It writes the error response and returns false when it cannot be fetched.
+func (server *Server) getAccount(ctx *gin.Context, accountId int64) (db.Account, bool) {
+	account, err := server.store.GetAccount(ctx, accountId)
+	if err != nil {
+		errorResponse(ctx, err)
+		return account, false
 	}
 
-	return true
+	return account, true
 }
@@ -2,20 +2,54 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
 	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/fx"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
+type eqTransferTxParamsMatcher struct {
+	arg db.TransferTxParams
+}
+
+func (e eqTransferTxParamsMatcher) Matches(x any) bool {
+	arg, ok := x.(db.TransferTxParams)
+	if !ok {
+		return false
+	}
+
+	// The rate timestamp is given by the rate provider
+	return e.arg.FromAccountId == arg.FromAccountId &&
+		e.arg.ToAccountId == arg.ToAccountId &&
+		e.arg.Amount.Equal(arg.Amount) &&
+		e.arg.ExchangeRate.Equal(arg.ExchangeRate) &&
+		!arg.RateTimestamp.IsZero()
+}
+
+func (e eqTransferTxParamsMatcher) String() string {
+	return fmt.Sprintf("matched arg %v", e.arg)
+}
+
+func EqTransferTxParams(arg db.TransferTxParams) gomock.Matcher {
+	return eqTransferTxParamsMatcher{arg}
+}
+
 func TestTransferApi(t *testing.T) {
 
 	fromAccount := createRandomAccount()
@@ -27,9 +61,20 @@ func TestTransferApi(t *testing.T) {
 	toAccountDifferentCurrency := createRandomAccount()
 	toAccountDifferentCurrency.Currency = db.CurrencyINR
 
+	fromAccountUSD := createRandomAccount()
+	fromAccountUSD.Currency = db.CurrencyUSD
+
+	systemAccount := createRandomAccount()
+	systemAccount.Currency = db.CurrencyEUR
+	systemAccount.IsSystem = true
+
+	toAccountDisabledCurrency := createRandomAccount()
+	toAccountDisabledCurrency.Currency = "GBP"
+
 	testCases := []struct {
 		name             string
 		body             gin.H
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubFunc    func(store *mockdb.MockStore)
 		validateResponse func(recorder *httptest.ResponseRecorder)
 	}{
@@ -41,7 +86,9 @@ func TestTransferApi(t *testing.T) {
 				"amount":          decimal.NewFromInt(1),
 				"currency":        toAccount.Currency,
 			},
-
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
@@ -50,13 +97,153 @@ func TestTransferApi(t *testing.T) {
 					FromAccountId: fromAccount.ID,
 					ToAccountId:   toAccount.ID,
 					Amount:        decimal.NewFromInt(1),
+					ExchangeRate:  decimal.NewFromInt(1),
 				}
-				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+				store.EXPECT().TransferTx(gomock.Any(), EqTransferTxParams(arg)).Times(1)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "AccountNotActive",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        fromAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, &db.AccountNotActiveError{
+						AccountID: toAccount.ID,
+						Status:    db.AccountStatusClosed,
+					})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "FromAccountFrozen",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        fromAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, &db.AccountNotActiveError{
+						AccountID: fromAccount.ID,
+						Status:    db.AccountStatusFrozen,
+						Reason:    "court order",
+					})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+
+				var body APIError
+				err := json.Unmarshal(recorder.Body.Bytes(), &body)
+				assert.NoError(t, err)
+				assert.Equal(t, "court order", body.Details["reason"])
+			},
+		},
+		{
+			name: "ToSystemAccount",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   systemAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        fromAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(systemAccount.ID)).Times(1).Return(systemAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, db.ErrSystemAccount, recorder.Body)
+			},
+		},
+		{
+			name: "ToAccountDisabledCurrency",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccountDisabledCurrency.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        fromAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccountDisabledCurrency.ID)).Times(1).Return(toAccountDisabledCurrency, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errUnsupportedCurrency, recorder.Body)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        toAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, toAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, errAccountNotOwned, recorder.Body)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        toAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 		{
 			name: "InternalServerError",
 			body: gin.H{
@@ -65,7 +252,9 @@ func TestTransferApi(t *testing.T) {
 				"amount":          decimal.NewFromInt(1),
 				"currency":        toAccount.Currency,
 			},
-
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
@@ -74,8 +263,9 @@ func TestTransferApi(t *testing.T) {
 					FromAccountId: fromAccount.ID,
 					ToAccountId:   toAccount.ID,
 					Amount:        decimal.NewFromInt(1),
+					ExchangeRate:  decimal.NewFromInt(1),
 				}
-				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, pgx.ErrTxClosed)
+				store.EXPECT().TransferTx(gomock.Any(), EqTransferTxParams(arg)).Times(1).Return(db.TransferTxResult{}, pgx.ErrTxClosed)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -90,7 +280,9 @@ func TestTransferApi(t *testing.T) {
 				"amount":          decimal.NewFromInt(1),
 				"currency":        toAccount.Currency,
 			},
-
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
@@ -99,14 +291,129 @@ func TestTransferApi(t *testing.T) {
 					FromAccountId: fromAccount.ID,
 					ToAccountId:   toAccount.ID,
 					Amount:        decimal.NewFromInt(1),
+					ExchangeRate:  decimal.NewFromInt(1),
 				}
-				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, pgx.ErrNoRows)
+				store.EXPECT().TransferTx(gomock.Any(), EqTransferTxParams(arg)).Times(1).Return(db.TransferTxResult{}, pgx.ErrNoRows)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusNotFound, recorder.Code)
 				assertError(t, pgx.ErrNoRows, recorder.Body)
 			},
 		},
+		{
+			name: "InsufficientFunds",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        toAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountId: fromAccount.ID,
+					ToAccountId:   toAccount.ID,
+					Amount:        decimal.NewFromInt(1),
+					ExchangeRate:  decimal.NewFromInt(1),
+				}
+				err := &db.InsufficientFundsError{
+					AccountID: fromAccount.ID,
+					Available: decimal.NewFromInt(0),
+					Requested: decimal.NewFromInt(1),
+				}
+				store.EXPECT().TransferTx(gomock.Any(), EqTransferTxParams(arg)).Times(1).Return(db.TransferTxResult{}, err)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				var body APIError
+				err := json.Unmarshal(recorder.Body.Bytes(), &body)
+				assert.NoError(t, err)
+				assert.Equal(t, "0", body.Details["available_balance"])
+				assert.Equal(t, "insufficient_funds", body.Code)
+			},
+		},
+		{
+			name: "DailyLimitExceeded",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        toAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+				err := &db.LimitExceededError{
+					LimitID:   1,
+					Scope:     db.LimitScopeUser,
+					Limit:     db.LimitDailyOutgoing,
+					Currency:  fromAccount.Currency,
+					Max:       decimal.NewFromInt(100),
+					Used:      decimal.NewFromInt(100),
+					Requested: decimal.NewFromInt(1),
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, err)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+				var body APIError
+				err := json.Unmarshal(recorder.Body.Bytes(), &body)
+				assert.NoError(t, err)
+				assert.Equal(t, db.LimitDailyOutgoing, body.Details["limit"])
+				assert.Equal(t, db.LimitScopeUser, body.Details["scope"])
+				assert.Equal(t, "100", body.Details["max"])
+				assert.Equal(t, "100", body.Details["used"])
+			},
+		},
+		{
+			name: "MaxSingleTransferExceeded",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        toAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+				err := &db.LimitExceededError{
+					LimitID:   1,
+					Scope:     db.LimitScopeAccount,
+					Limit:     db.LimitMaxSingleTransfer,
+					Currency:  fromAccount.Currency,
+					Max:       decimal.RequireFromString("0.5"),
+					Used:      decimal.NewFromInt(0),
+					Requested: decimal.NewFromInt(1),
+				}
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, err)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+				var body APIError
+				err := json.Unmarshal(recorder.Body.Bytes(), &body)
+				assert.NoError(t, err)
+				assert.Equal(t, db.LimitMaxSingleTransfer, body.Details["limit"])
+				assert.Equal(t, db.LimitScopeAccount, body.Details["scope"])
+				assert.Equal(t, "0.5", body.Details["max"])
+				assert.Equal(t, "0", body.Details["used"])
+			},
+		},
 		{
 			name: "BadRequest",
 			body: gin.H{
@@ -115,7 +422,9 @@ func TestTransferApi(t *testing.T) {
 				"amount":          decimal.NewFromInt(1),
 				"currency":        toAccount.Currency,
 			},
-
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
@@ -125,6 +434,30 @@ func TestTransferApi(t *testing.T) {
 				assert.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "SameAccount",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   fromAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        fromAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				var body APIError
+				err := json.Unmarshal(recorder.Body.Bytes(), &body)
+				assert.NoError(t, err)
+				assert.Equal(t, map[string]any{"ToAccountId": "nefield"}, body.Details["fields"])
+			},
+		},
 		{
 			name: "BadRequestMisMatchFromCurrency",
 			body: gin.H{
@@ -133,7 +466,9 @@ func TestTransferApi(t *testing.T) {
 				"amount":          decimal.NewFromInt(1),
 				"currency":        toAccountDifferentCurrency.Currency,
 			},
-
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
@@ -144,113 +479,304 @@ func TestTransferApi(t *testing.T) {
 			},
 		},
 		{
-			name: "BadRequestMisMatchToCurrency",
+			name: "CrossCurrency",
 			body: gin.H{
 				"from_account_id": fromAccount.ID,
 				"to_account_id":   toAccountDifferentCurrency.ID,
 				"amount":          decimal.NewFromInt(1),
 				"currency":        fromAccount.Currency,
 			},
-
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccountDifferentCurrency.ID)).Times(1).Return(toAccountDifferentCurrency, nil)
+
+				// EUR to INR rate of the test server
+				arg := db.TransferTxParams{
+					FromAccountId: fromAccount.ID,
+					ToAccountId:   toAccountDifferentCurrency.ID,
+					Amount:        decimal.NewFromInt(1),
+					ExchangeRate:  decimal.NewFromInt(90),
+				}
+				store.EXPECT().TransferTx(gomock.Any(), EqTransferTxParams(arg)).Times(1)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ExchangeRateNotFound",
+			body: gin.H{
+				"from_account_id": fromAccountUSD.ID,
+				"to_account_id":   toAccountDifferentCurrency.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        fromAccountUSD.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccountUSD.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccountUSD.ID)).Times(1).Return(fromAccountUSD, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccountDifferentCurrency.ID)).Times(1).Return(toAccountDifferentCurrency, nil)
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				assertError(t, &fx.RateNotFoundError{From: string(db.CurrencyUSD), To: string(db.CurrencyINR)}, recorder.Body)
 			},
 		},
 		{
-			name: "FromAccountNotFound",
+			name: "ZeroAmount",
 			body: gin.H{
 				"from_account_id": fromAccount.ID,
 				"to_account_id":   toAccount.ID,
-				"amount":          decimal.NewFromInt(1),
+				"amount":          decimal.RequireFromString("0"),
 				"currency":        toAccount.Currency,
 			},
-
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(db.Account{}, pgx.ErrNoRows)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusNotFound, recorder.Code)
-				assertError(t, pgx.ErrNoRows, recorder.Body)
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
-			name: "ToAccountNotFound",
+			name: "NegativeAmount",
 			body: gin.H{
 				"from_account_id": fromAccount.ID,
 				"to_account_id":   toAccount.ID,
-				"amount":          decimal.NewFromInt(1),
+				"amount":          decimal.RequireFromString("-10"),
 				"currency":        toAccount.Currency,
 			},
-
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(db.Account{}, pgx.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusNotFound, recorder.Code)
-				assertError(t, pgx.ErrNoRows, recorder.Body)
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
-			name: "FromAccountInternalServerError",
+			name: "TooManyDecimals",
 			body: gin.H{
 				"from_account_id": fromAccount.ID,
 				"to_account_id":   toAccount.ID,
-				"amount":          decimal.NewFromInt(1),
+				"amount":          decimal.RequireFromString("1.001"),
 				"currency":        toAccount.Currency,
 			},
-
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(db.Account{}, pgx.ErrTxClosed)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
-				assertError(t, pgx.ErrTxClosed, recorder.Body)
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, &db.AmountPrecisionError{
+					Amount:   decimal.RequireFromString("1.001"),
+					Currency: toAccount.Currency,
+					Exponent: 2,
+				}, recorder.Body)
 			},
 		},
 		{
-			name: "ToAccountInternalServerError",
+			name: "TooLargeAmount",
 			body: gin.H{
 				"from_account_id": fromAccount.ID,
 				"to_account_id":   toAccount.ID,
-				"amount":          decimal.NewFromInt(1),
+				"amount":          decimal.RequireFromString("1000000000000.01"),
 				"currency":        toAccount.Currency,
 			},
-
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
 			buildStubFunc: func(store *mockdb.MockStore) {
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(db.Account{}, pgx.ErrTxClosed)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
-				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
-				assertError(t, pgx.ErrTxClosed, recorder.Body)
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
-	}
-
-	for i := range testCases {
-		tc := testCases[i]
-
-		t.Run(tc.name, func(t *testing.T) {
-
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
+		{
+			name: "AmountPrecisionRejectedByStore",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.RequireFromString("1.1"),
+				"currency":        toAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, &db.AmountPrecisionError{
+						Amount:   decimal.RequireFromString("1.1"),
+						Currency: fromAccount.Currency,
+						Exponent: 0,
+					})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnsupportedCurrency",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        "XYZ",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errUnsupportedCurrency, recorder.Body)
+			},
+		},
+		{
+			name: "ToAmountTooSmall",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.RequireFromString("0.01"),
+				"currency":        toAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TransferTxResult{}, db.ErrToAmountTooSmall)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+				assertError(t, db.ErrToAmountTooSmall, recorder.Body)
+			},
+		},
+		{
+			name: "FromAccountNotFound",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        toAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(db.Account{}, pgx.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+				assertError(t, pgx.ErrNoRows, recorder.Body)
+			},
+		},
+		{
+			name: "ToAccountNotFound",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        toAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(db.Account{}, pgx.ErrNoRows)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+				assertError(t, pgx.ErrNoRows, recorder.Body)
+			},
+		},
+		{
+			name: "FromAccountInternalServerError",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        toAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(db.Account{}, pgx.ErrTxClosed)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+		{
+			name: "ToAccountInternalServerError",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          decimal.NewFromInt(1),
+				"currency":        toAccount.Currency,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(db.Account{}, pgx.ErrTxClosed)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubFunc(store)
 
-			server := NewServer(store)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
 			url := "/api/transfers"
@@ -261,8 +787,1155 @@ func TestTransferApi(t *testing.T) {
 			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(data))
 			assert.NoError(t, err)
 
+			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
 			tc.validateResponse(recorder)
 		})
 	}
 }
+
+func TestTransferApiIdempotencyKey(t *testing.T) {
+
+	fromAccount := createRandomAccount()
+	fromAccount.Currency = db.CurrencyEUR
+
+	toAccount := createRandomAccount()
+	toAccount.Currency = db.CurrencyEUR
+
+	body := gin.H{
+		"from_account_id": fromAccount.ID,
+		"to_account_id":   toAccount.ID,
+		"amount":          decimal.NewFromInt(1),
+		"currency":        toAccount.Currency,
+	}
+
+	transferArg := db.TransferTxParams{
+		FromAccountId: fromAccount.ID,
+		ToAccountId:   toAccount.ID,
+		Amount:        decimal.NewFromInt(1),
+		ExchangeRate:  decimal.NewFromInt(1),
+	}
+
+	transferResult := db.TransferTxResult{
+		Transfer: db.Transfer{
+			ID:            utils.RandomNumber(1, 1000),
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   toAccount.ID,
+			Amount:        decimal.NewFromInt(1),
+		},
+	}
+
+	idempotencyKey := utils.RandomString(16)
+
+	requestHash := hashRequest(createTransferRequest{
+		FromAccountId: fromAccount.ID,
+		ToAccountId:   toAccount.ID,
+		Amount:        decimal.NewFromInt(1),
+		Currency:      toAccount.Currency,
+	})
+
+	response, err := json.Marshal(transferResult)
+	assert.NoError(t, err)
+
+	storedKey := db.IdempotencyKey{
+		Username:    fromAccount.Owner,
+		Key:         idempotencyKey,
+		RequestHash: requestHash,
+		Response:    response,
+		ExpiresAt:   pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	}
+
+	getKeyArg := db.GetIdempotencyKeyParams{
+		Username: fromAccount.Owner,
+		Key:      idempotencyKey,
+	}
+
+	// checkArg validates the arguments given to the store and returns the given result
+	checkArg := func(result db.IdempotentTransferTxResult, err error) func(
+		ctx context.Context,
+		arg db.IdempotentTransferTxParams) (db.IdempotentTransferTxResult, error) {
+
+		return func(ctx context.Context, arg db.IdempotentTransferTxParams) (db.IdempotentTransferTxResult, error) {
+			assert.True(t, EqTransferTxParams(transferArg).Matches(arg.TransferTxParams))
+			assert.Equal(t, fromAccount.Owner, arg.Username)
+			assert.Equal(t, idempotencyKey, arg.Key)
+			assert.Equal(t, requestHash, arg.RequestHash)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt, time.Second)
+			return result, err
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		idempotencyKey   string
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:           "FirstRequest",
+			idempotencyKey: idempotencyKey,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKeyArg)).Times(1).Return(db.IdempotencyKey{}, pgx.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					IdempotentTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(checkArg(db.IdempotentTransferTxResult{TransferTxResult: transferResult}, nil))
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Empty(t, recorder.Header().Get(idempotentReplayedHeader))
+				assertTransferResult(t, transferResult, recorder.Body)
+			},
+		},
+		{
+			name:           "Replayed",
+			idempotencyKey: idempotencyKey,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKeyArg)).Times(1).Return(storedKey, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().IdempotentTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeader))
+				assertTransferResult(t, transferResult, recorder.Body)
+			},
+		},
+		{
+			name:           "ReplayedConcurrently",
+			idempotencyKey: idempotencyKey,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKeyArg)).Times(1).Return(db.IdempotencyKey{}, pgx.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					IdempotentTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(checkArg(db.IdempotentTransferTxResult{TransferTxResult: transferResult, Replayed: true}, nil))
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeader))
+				assertTransferResult(t, transferResult, recorder.Body)
+			},
+		},
+		{
+			name:           "ExpiredKey",
+			idempotencyKey: idempotencyKey,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				expiredKey := storedKey
+				expiredKey.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKeyArg)).Times(1).Return(expiredKey, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					IdempotentTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(checkArg(db.IdempotentTransferTxResult{TransferTxResult: transferResult}, nil))
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assert.Empty(t, recorder.Header().Get(idempotentReplayedHeader))
+				assertTransferResult(t, transferResult, recorder.Body)
+			},
+		},
+		{
+			name:           "KeyReusedWithDifferentRequest",
+			idempotencyKey: idempotencyKey,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				otherKey := storedKey
+				otherKey.RequestHash = utils.RandomString(64)
+
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKeyArg)).Times(1).Return(otherKey, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().IdempotentTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+				assertError(t, db.ErrIdempotencyKeyReused, recorder.Body)
+			},
+		},
+		{
+			name:           "KeyReusedConcurrently",
+			idempotencyKey: idempotencyKey,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKeyArg)).Times(1).Return(db.IdempotencyKey{}, pgx.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					IdempotentTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(checkArg(db.IdempotentTransferTxResult{}, db.ErrIdempotencyKeyReused))
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+				assertError(t, db.ErrIdempotencyKeyReused, recorder.Body)
+			},
+		},
+		{
+			name:           "InsufficientFunds",
+			idempotencyKey: idempotencyKey,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKeyArg)).Times(1).Return(db.IdempotencyKey{}, pgx.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					IdempotentTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(checkArg(db.IdempotentTransferTxResult{}, &db.InsufficientFundsError{
+						AccountID: fromAccount.ID,
+						Available: decimal.Zero,
+						Requested: decimal.NewFromInt(1),
+					}))
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:           "KeyTooLong",
+			idempotencyKey: utils.RandomString(maxIdempotencyKeyLength + 1),
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().IdempotentTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errIdempotencyKeyTooLong, recorder.Body)
+			},
+		},
+		{
+			name:           "GetIdempotencyKeyError",
+			idempotencyKey: idempotencyKey,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Eq(getKeyArg)).Times(1).Return(db.IdempotencyKey{}, pgx.ErrTxClosed)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().IdempotentTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := "/api/transfers"
+
+			data, err := json.Marshal(body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(data))
+			assert.NoError(t, err)
+
+			request.Header.Set(idempotencyKeyHeader, tc.idempotencyKey)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func TestTransferApiIdempotencyKeyReplayWithoutRate(t *testing.T) {
+	// There is no exchange rate between USD and INR,
+	// which must not matter when replaying a stored transfer
+	fromAccount := createRandomAccount()
+	fromAccount.Currency = db.CurrencyUSD
+
+	toAccount := createRandomAccount()
+	toAccount.Currency = db.CurrencyINR
+
+	req := createTransferRequest{
+		FromAccountId: fromAccount.ID,
+		ToAccountId:   toAccount.ID,
+		Amount:        decimal.NewFromInt(1),
+		Currency:      fromAccount.Currency,
+	}
+
+	transferResult := db.TransferTxResult{
+		Transfer: db.Transfer{
+			ID:            utils.RandomNumber(1, 1000),
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   toAccount.ID,
+			Amount:        decimal.NewFromInt(1),
+			ToAmount:      decimal.NewFromInt(83),
+			ExchangeRate:  decimal.NewFromInt(83),
+		},
+	}
+
+	response, err := json.Marshal(transferResult)
+	assert.NoError(t, err)
+
+	idempotencyKey := utils.RandomString(16)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetIdempotencyKey(gomock.Any(), gomock.Eq(db.GetIdempotencyKeyParams{Username: fromAccount.Owner, Key: idempotencyKey})).
+		Times(1).
+		Return(db.IdempotencyKey{
+			Username:    fromAccount.Owner,
+			Key:         idempotencyKey,
+			RequestHash: hashRequest(req),
+			Response:    response,
+			ExpiresAt:   pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+		}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().IdempotentTransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(req)
+	assert.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/api/transfers", bytes.NewBuffer(data))
+	assert.NoError(t, err)
+
+	request.Header.Set(idempotencyKeyHeader, idempotencyKey)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "true", recorder.Header().Get(idempotentReplayedHeader))
+	assertTransferResult(t, transferResult, recorder.Body)
+}
+
+func TestHashRequest(t *testing.T) {
+	req := createTransferRequest{
+		FromAccountId: 1,
+		ToAccountId:   2,
+		Amount:        decimal.RequireFromString("10"),
+		Currency:      db.CurrencyEUR,
+	}
+
+	hash := hashRequest(req)
+	assert.Len(t, hash, 64)
+
+	// The same amount written differently is the same request
+	sameReq := req
+	sameReq.Amount = decimal.RequireFromString("10.00")
+	assert.Equal(t, hash, hashRequest(sameReq))
+
+	otherReq := req
+	otherReq.Amount = decimal.RequireFromString("10.01")
+	assert.NotEqual(t, hash, hashRequest(otherReq))
+
+	otherReq = req
+	otherReq.ToAccountId = 3
+	assert.NotEqual(t, hash, hashRequest(otherReq))
+}
+
+func assertTransferResult(t *testing.T, expected db.TransferTxResult, body *bytes.Buffer) {
+	data, err := io.ReadAll(body)
+	assert.NoError(t, err)
+
+	var result db.TransferTxResult
+	err = json.Unmarshal(data, &result)
+	assert.NoError(t, err)
+
+	assert.Equal(t, expected.Transfer.ID, result.Transfer.ID)
+	assert.Equal(t, expected.Transfer.FromAccountID, result.Transfer.FromAccountID)
+	assert.Equal(t, expected.Transfer.ToAccountID, result.Transfer.ToAccountID)
+	assert.True(t, expected.Transfer.Amount.Equal(result.Transfer.Amount))
+}
+
+func TestGetTransferApi(t *testing.T) {
+	fromAccount := createRandomAccount()
+	toAccount := createRandomAccount()
+
+	transfer := db.Transfer{
+		ID:            utils.RandomNumber(1, 1000),
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        utils.RandomDecimal(1, 100),
+	}
+
+	testCases := []struct {
+		name             string
+		transferId       int64
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OKSender",
+			transferId: transfer.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assertTransfer(t, transfer, recorder.Body)
+			},
+		},
+		{
+			name:       "OKRecipient",
+			transferId: transfer.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, toAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assertTransfer(t, transfer, recorder.Body)
+			},
+		},
+		{
+			name:       "UnauthorizedUser",
+			transferId: transfer.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "otheruser", utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, errTransferNotVisible, recorder.Body)
+			},
+		},
+		{
+			name:       "NoAuthorization",
+			transferId: transfer.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:       "NotFound",
+			transferId: transfer.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(db.Transfer{}, pgx.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+				assertError(t, pgx.ErrNoRows, recorder.Body)
+			},
+		},
+		{
+			name:       "InternalServerError",
+			transferId: transfer.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(db.Transfer{}, pgx.ErrTxClosed)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+		{
+			name:       "InvalidId",
+			transferId: 0,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/transfers/%d", tc.transferId)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			assert.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func TestReverseTransferApi(t *testing.T) {
+	admin, _ := createRandomUser()
+	fromAccount := createRandomAccount()
+	toAccount := createRandomAccount()
+
+	transfer := db.Transfer{
+		ID:            utils.RandomNumber(1, 1000),
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        decimal.NewFromInt(50),
+		ToAmount:      decimal.NewFromInt(50),
+		ExchangeRate:  decimal.NewFromInt(1),
+	}
+
+	reversal := db.ReverseTransferTxResult{
+		TransferTxResult: db.TransferTxResult{
+			Transfer: db.Transfer{
+				ID:            transfer.ID + 1,
+				FromAccountID: toAccount.ID,
+				ToAccountID:   fromAccount.ID,
+				Amount:        decimal.NewFromInt(20),
+				ToAmount:      decimal.NewFromInt(20),
+				ExchangeRate:  decimal.NewFromInt(1),
+				ReversalOf:    pgtype.Int8{Int64: transfer.ID, Valid: true},
+			},
+		},
+		Remaining: decimal.NewFromInt(30),
+	}
+
+	systemAccount := createRandomAccount()
+	systemAccount.Owner = "system"
+	systemAccount.IsSystem = true
+
+	deposit := transfer
+	deposit.FromAccountID = systemAccount.ID
+
+	receiverAuth := func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+		addAuthorization(t, request, tokenMaker, authorizationTypeBearer, toAccount.Owner, utils.DepositorRole, time.Minute)
+	}
+
+	testCases := []struct {
+		name             string
+		transferId       int64
+		body             gin.H
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "PartialOK",
+			transferId: transfer.ID,
+			body:       gin.H{"amount": "20"},
+			setupAuth:  receiverAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.ReverseTransferTxParams{
+					TransferID: transfer.ID,
+					Amount:     decimal.NewFromInt(20),
+				}
+
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(reversal, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var result db.ReverseTransferTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &result)
+				assert.NoError(t, err)
+				assert.Equal(t, transfer.ID, result.Transfer.ReversalOf.Int64)
+				assert.True(t, decimal.NewFromInt(30).Equal(result.Remaining))
+			},
+		},
+		{
+			name:       "FullByAdmin",
+			transferId: transfer.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.ReverseTransferTxParams{TransferID: transfer.ID, SkipLimits: true}
+
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(reversal, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "SenderForbidden",
+			transferId: transfer.ID,
+			body:       gin.H{"amount": "20"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromAccount.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, errTransferNotReversible, recorder.Body)
+			},
+		},
+		{
+			name:       "DepositForbidden",
+			transferId: deposit.ID,
+			setupAuth:  receiverAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(deposit.ID)).Times(1).Return(deposit, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(systemAccount.ID)).Times(1).Return(systemAccount, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, db.ErrSystemAccount, recorder.Body)
+			},
+		},
+		{
+			name:       "DepositByAdmin",
+			transferId: deposit.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Name, utils.AdminRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.ReverseTransferTxParams{TransferID: deposit.ID, SkipLimits: true}
+
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(deposit.ID)).Times(1).Return(deposit, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(reversal, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "LimitExceeded",
+			transferId: transfer.ID,
+			body:       gin.H{"amount": "20"},
+			setupAuth:  receiverAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				// The reversals of the users are checked against their limits
+				arg := db.ReverseTransferTxParams{
+					TransferID: transfer.ID,
+					Amount:     decimal.NewFromInt(20),
+				}
+
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ReverseTransferTxResult{}, &db.LimitExceededError{
+						LimitID:   1,
+						Scope:     db.LimitScopeAccount,
+						Limit:     db.LimitDailyOutgoing,
+						Currency:  toAccount.Currency,
+						Max:       decimal.NewFromInt(10),
+						Used:      decimal.NewFromInt(0),
+						Requested: decimal.NewFromInt(20),
+					})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			},
+		},
+		{
+			name:       "NegativeAmount",
+			transferId: transfer.ID,
+			body:       gin.H{"amount": "-5"},
+			setupAuth:  receiverAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errInvalidReversalAmount, recorder.Body)
+			},
+		},
+		{
+			name:       "ExceedsRemaining",
+			transferId: transfer.ID,
+			body:       gin.H{"amount": "40"},
+			setupAuth:  receiverAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReverseTransferTxResult{}, &db.ReversalExceedsRemainingError{
+						TransferID: transfer.ID,
+						Remaining:  decimal.NewFromInt(30),
+						Requested:  decimal.NewFromInt(40),
+					})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusConflict, recorder.Code)
+
+				var body struct {
+					Details struct {
+						Remaining decimal.Decimal `json:"remaining"`
+					} `json:"details"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &body)
+				assert.NoError(t, err)
+				assert.True(t, decimal.NewFromInt(30).Equal(body.Details.Remaining))
+			},
+		},
+		{
+			name:       "ReversalOfReversal",
+			transferId: transfer.ID,
+			setupAuth:  receiverAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReverseTransferTxResult{}, db.ErrReversalOfReversal)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:       "InsufficientFunds",
+			transferId: transfer.ID,
+			setupAuth:  receiverAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(transfer, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().
+					ReverseTransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReverseTransferTxResult{}, &db.InsufficientFundsError{AccountID: toAccount.ID})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:       "NotFound",
+			transferId: transfer.ID,
+			setupAuth:  receiverAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransfer(gomock.Any(), gomock.Eq(transfer.ID)).Times(1).Return(db.Transfer{}, pgx.ErrNoRows)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var data []byte
+			if tc.body != nil {
+				var err error
+				data, err = json.Marshal(tc.body)
+				assert.NoError(t, err)
+			}
+
+			url := fmt.Sprintf("/api/transfers/%d/reverse", tc.transferId)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func TestListAccountTransfersApi(t *testing.T) {
+	account := createRandomAccount()
+	counterparty := createRandomAccount()
+
+	n := 5
+	transfers := make([]db.Transfer, n)
+	for i := 0; i < n; i++ {
+		transfers[i] = db.Transfer{
+			ID:            utils.RandomNumber(1, 1000),
+			FromAccountID: account.ID,
+			ToAccountID:   counterparty.ID,
+			Amount:        utils.RandomDecimal(1, 100),
+		}
+	}
+
+	type query struct {
+		direction    string
+		counterparty int64
+		minAmount    string
+		maxAmount    string
+		pageId       int
+		pageSize     int
+	}
+
+	testCases := []struct {
+		name             string
+		query            query
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			query: query{
+				pageId:   1,
+				pageSize: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.ListAccountTransfersParams{
+					AccountID:       account.ID,
+					IncludeOutgoing: true,
+					IncludeIncoming: true,
+					LimitCount:      int32(n),
+					OffsetCount:     0,
+				}
+				store.EXPECT().
+					ListAccountTransfers(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(transfers, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assertTransfers(t, transfers, recorder.Body)
+			},
+		},
+		{
+			name: "OKWithFilters",
+			query: query{
+				direction:    transferDirectionOut,
+				counterparty: counterparty.ID,
+				minAmount:    "1.5",
+				maxAmount:    "100",
+				pageId:       2,
+				pageSize:     n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				store.EXPECT().
+					ListAccountTransfers(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.ListAccountTransfersParams) ([]db.Transfer, error) {
+						assert.Equal(t, account.ID, arg.AccountID)
+						assert.True(t, arg.IncludeOutgoing)
+						assert.False(t, arg.IncludeIncoming)
+						assert.Equal(t, pgtype.Int8{Int64: counterparty.ID, Valid: true}, arg.Counterparty)
+						assert.True(t, arg.MinAmount.Valid)
+						assert.True(t, decimal.RequireFromString("1.5").Equal(arg.MinAmount.Decimal))
+						assert.True(t, arg.MaxAmount.Valid)
+						assert.True(t, decimal.NewFromInt(100).Equal(arg.MaxAmount.Decimal))
+						assert.Equal(t, int32(n), arg.LimitCount)
+						assert.Equal(t, int32(n), arg.OffsetCount)
+						return transfers, nil
+					})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assertTransfers(t, transfers, recorder.Body)
+			},
+		},
+		{
+			name: "IncomingOnly",
+			query: query{
+				direction: transferDirectionIn,
+				pageId:    1,
+				pageSize:  n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+
+				arg := db.ListAccountTransfersParams{
+					AccountID:       account.ID,
+					IncludeOutgoing: false,
+					IncludeIncoming: true,
+					LimitCount:      int32(n),
+					OffsetCount:     0,
+				}
+				store.EXPECT().
+					ListAccountTransfers(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.Transfer{}, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+				assertTransfers(t, []db.Transfer{}, recorder.Body)
+			},
+		},
+		{
+			name: "InvalidDirection",
+			query: query{
+				direction: "sideways",
+				pageId:    1,
+				pageSize:  n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAmount",
+			query: query{
+				minAmount: "abc",
+				pageId:    1,
+				pageSize:  n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAmountRange",
+			query: query{
+				minAmount: "100",
+				maxAmount: "10",
+				pageId:    1,
+				pageSize:  n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, errInvalidAmountRange, recorder.Body)
+			},
+		},
+		{
+			name: "InvalidPageSize",
+			query: query{
+				pageId:   1,
+				pageSize: 100,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			query: query{
+				pageId:   1,
+				pageSize: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "otheruser", utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, errAccountNotOwned, recorder.Body)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			query: query{
+				pageId:   1,
+				pageSize: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InternalServerError",
+			query: query{
+				pageId:   1,
+				pageSize: n,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, account.Owner, utils.DepositorRole, time.Minute)
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ListAccountTransfers(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Transfer{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/accounts/%d/transfers", account.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			assert.NoError(t, err)
+
+			q := request.URL.Query()
+			if tc.query.direction != "" {
+				q.Add("direction", tc.query.direction)
+			}
+			if tc.query.counterparty != 0 {
+				q.Add("counterparty", fmt.Sprintf("%d", tc.query.counterparty))
+			}
+			if tc.query.minAmount != "" {
+				q.Add("min_amount", tc.query.minAmount)
+			}
+			if tc.query.maxAmount != "" {
+				q.Add("max_amount", tc.query.maxAmount)
+			}
+			q.Add("page_id", fmt.Sprintf("%d", tc.query.pageId))
+			q.Add("page_size", fmt.Sprintf("%d", tc.query.pageSize))
+			request.URL.RawQuery = q.Encode()
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func assertTransfer(t *testing.T, expected db.Transfer, body *bytes.Buffer) {
+	data, err := io.ReadAll(body)
+	assert.NoError(t, err)
+
+	var transfer db.Transfer
+	err = json.Unmarshal(data, &transfer)
+	assert.NoError(t, err)
+
+	assert.Equal(t, expected.ID, transfer.ID)
+	assert.Equal(t, expected.FromAccountID, transfer.FromAccountID)
+	assert.Equal(t, expected.ToAccountID, transfer.ToAccountID)
+	assert.True(t, expected.Amount.Equal(transfer.Amount))
+}
+
+func assertTransfers(t *testing.T, expected []db.Transfer, body *bytes.Buffer) {
+	data, err := io.ReadAll(body)
+	assert.NoError(t, err)
+
+	var transfers []db.Transfer
+	err = json.Unmarshal(data, &transfers)
+	assert.NoError(t, err)
+
+	assert.Len(t, transfers, len(expected))
+	for i := range expected {
+		assert.Equal(t, expected[i].ID, transfers[i].ID)
+		assert.True(t, expected[i].Amount.Equal(transfers[i].Amount))
+	}
+}
@@ -1,22 +1,34 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
 	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+var errInvalidCredentials = newAPIError(http.StatusUnauthorized, "invalid_credentials", "invalid name or password")
+
+// dummyHashPassword is verified against for unknown users, so that
+// a login takes as long whether or not the name exists.
+// It is a bcrypt hash with the same cost as utils.HashPassword.
+const dummyHashPassword = "$2a$10$9hdPSzwlyDIc/wLcPH8MWeDqCmpWvDrEFTz0mHO8FNWP34OXNJSda"
+
 type createUserRequest struct {
 	Name     string `json:"name" binding:"required,alphanum"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=8"`
 }
 
-type createUserResponse struct {
+type userResponse struct {
 	Name      string             `json:"name"`
 	Email     string             `json:"email"`
 	CreatedAt pgtype.Timestamptz `json:"createdAt"`
@@ -27,13 +39,13 @@ func (server *Server) createUserHandler(ctx *gin.Context) {
 	var req createUserRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		errorResponse(ctx, invalidRequest(err))
 		return
 	}
 
 	hashPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, err)
 		return
 	}
 
@@ -43,26 +55,116 @@ func (server *Server) createUserHandler(ctx *gin.Context) {
 		HashPassword: hashPassword,
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
+	user, err := server.store.CreateUserTx(ctx, arg)
 	if err != nil {
-		if pgErr, ok := err.(*pgconn.PgError); ok {
-			switch pgErr.Code {
-			case "23505":
-				ctx.JSON(http.StatusForbidden, errorResponse(pgErr))
-				return
-			}
+		// The name or the email is already taken
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			errorResponseWithStatus(ctx, http.StatusForbidden, err)
+			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		errorResponse(ctx, err)
 		return
 	}
 
-	result := createUserResponse{
+	ctx.JSON(http.StatusOK, newUserResponse(user))
+}
+
+func newUserResponse(user db.User) userResponse {
+	return userResponse{
 		Name:      user.Name,
 		Email:     user.Email,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}
+}
+
+type loginUserRequest struct {
+	Name     string `json:"name" binding:"required,alphanum"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+type loginUserResponse struct {
+	SessionID             uuid.UUID    `json:"session_id"`
+	AccessToken           string       `json:"access_token"`
+	AccessTokenExpiresAt  time.Time    `json:"access_token_expires_at"`
+	RefreshToken          string       `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time    `json:"refresh_token_expires_at"`
+	User                  userResponse `json:"user"`
+}
+
+func (server *Server) loginUserHandler(ctx *gin.Context) {
+	var req loginUserRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	// An unknown user gets the same error as a wrong password, after
+	// the same password check, so that the existing names cannot be
+	// found out from the response or from its time
+	user, err := server.store.GetUser(ctx, req.Name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			_ = utils.VerifyPassword(req.Password, dummyHashPassword)
+			errorResponse(ctx, errInvalidCredentials)
+			return
+		}
+
+		errorResponse(ctx, err)
+		return
+	}
+
+	err = utils.VerifyPassword(req.Password, user.HashPassword)
+	if err != nil {
+		errorResponse(ctx, errInvalidCredentials)
+		return
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
+		user.Name,
+		user.Role,
+		token.TokenTypeAccess,
+		server.config.AccessTokenDuration)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(
+		user.Name,
+		user.Role,
+		token.TokenTypeRefresh,
+		server.config.RefreshTokenDuration)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
+		ID:           refreshPayload.ID,
+		Username:     user.Name,
+		RefreshToken: refreshToken,
+		UserAgent:    ctx.Request.UserAgent(),
+		ClientIp:     ctx.ClientIP(),
+		IsBlocked:    false,
+		ExpiresAt:    pgtype.Timestamptz{Time: refreshPayload.ExpiredAt, Valid: true},
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	result := loginUserResponse{
+		SessionID:             session.ID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
+		User:                  newUserResponse(user),
+	}
 
 	ctx.JSON(http.StatusOK, result)
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -19,6 +20,7 @@ import (
 	"github.com/jackc/pgx/v5/pgproto3"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type eqCreateUserParamsMatcher struct {
@@ -74,7 +76,7 @@ func TestCreateUserApi(t *testing.T) {
 				}
 
 				store.EXPECT().
-					CreateUser(gomock.Any(), EqCreateUserParams(arg, password)).
+					CreateUserTx(gomock.Any(), EqCreateUserParams(arg, password)).
 					Times(1).
 					Return(user, nil)
 			},
@@ -92,7 +94,7 @@ func TestCreateUserApi(t *testing.T) {
 			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(db.User{}, pgx.ErrTxClosed)
 			},
@@ -110,7 +112,7 @@ func TestCreateUserApi(t *testing.T) {
 			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
@@ -126,7 +128,7 @@ func TestCreateUserApi(t *testing.T) {
 			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
@@ -142,7 +144,7 @@ func TestCreateUserApi(t *testing.T) {
 			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			validateResponse: func(recorder *httptest.ResponseRecorder) {
@@ -158,7 +160,7 @@ func TestCreateUserApi(t *testing.T) {
 			},
 			buildStubFunc: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
+					CreateUserTx(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(db.User{}, getForbinddenError())
 			},
@@ -180,7 +182,7 @@ func TestCreateUserApi(t *testing.T) {
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubFunc(store)
 
-			server := NewServer(store)
+			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
 			url := "/api/users"
@@ -196,6 +198,181 @@ func TestCreateUserApi(t *testing.T) {
 	}
 }
 
+func TestLoginUserApi(t *testing.T) {
+	user, password := createRandomUser()
+
+	testCases := []struct {
+		name             string
+		body             gin.H
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name":     user.Name,
+				"password": password,
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Name)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+						assert.Equal(t, user.Name, arg.Username)
+						assert.NotEmpty(t, arg.RefreshToken)
+						assert.False(t, arg.IsBlocked)
+
+						return db.Session{
+							ID:           arg.ID,
+							Username:     arg.Username,
+							RefreshToken: arg.RefreshToken,
+							ExpiresAt:    arg.ExpiresAt,
+						}, nil
+					})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var response loginUserResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.NotZero(t, response.SessionID)
+				assert.NotEmpty(t, response.AccessToken)
+				assert.NotZero(t, response.AccessTokenExpiresAt)
+				assert.NotEmpty(t, response.RefreshToken)
+				assert.True(t, response.RefreshTokenExpiresAt.After(response.AccessTokenExpiresAt))
+				assert.Equal(t, user.Name, response.User.Name)
+				assert.Equal(t, user.Email, response.User.Email)
+			},
+		},
+		{
+			name: "CreateSessionError",
+			body: gin.H{
+				"name":     user.Name,
+				"password": password,
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Name)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+		{
+			name: "UserNotFound",
+			body: gin.H{
+				"name":     user.Name,
+				"password": password,
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Name)).
+					Times(1).
+					Return(db.User{}, pgx.ErrNoRows)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assertError(t, errInvalidCredentials, recorder.Body)
+			},
+		},
+		{
+			name: "IncorrectPassword",
+			body: gin.H{
+				"name":     user.Name,
+				"password": utils.RandomString(9),
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Name)).
+					Times(1).
+					Return(user, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+				assertError(t, errInvalidCredentials, recorder.Body)
+			},
+		},
+		{
+			name: "InternalServerError",
+			body: gin.H{
+				"name":     user.Name,
+				"password": password,
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, pgx.ErrTxClosed)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+				assertError(t, pgx.ErrTxClosed, recorder.Body)
+			},
+		},
+		{
+			name: "InvalidUserName",
+			body: gin.H{
+				"name":     "@nitin",
+				"password": password,
+			},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := "/api/users/login"
+			data, err := json.Marshal(tc.body)
+			assert.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
+
+func TestDummyHashPassword(t *testing.T) {
+	cost, err := bcrypt.Cost([]byte(dummyHashPassword))
+	assert.NoError(t, err)
+	assert.Equal(t, bcrypt.DefaultCost, cost)
+}
+
 func createRandomUser() (db.User, string) {
 	password := utils.RandomString(8)
 	hashedPassword, _ := utils.HashPassword(password)
@@ -204,6 +381,7 @@ func createRandomUser() (db.User, string) {
 		Name:         utils.RandomString(6),
 		Email:        utils.RandomEmail(),
 		HashPassword: hashedPassword,
+		Role:         utils.DepositorRole,
 	}
 
 	return user, password
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"regexp"
+	"sync"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+// maxAmount is the largest amount accepted by the API
+var maxAmount = decimal.New(1, 12)
+
+// currencyCodePattern matches the ISO 4217 currency codes
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+var (
+	registerValidatorsOnce sync.Once
+	registerValidatorsErr  error
+)
+
+// registerValidators registers the custom binding validators once, as they are global in gin.
+// The validators only check the request itself, the checks against the currency
+// catalogue are done by the handlers, with checkCurrency and checkAmount.
+func registerValidators() error {
+	registerValidatorsOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		registerValidatorsErr = v.RegisterValidation("currency", validCurrency)
+		if registerValidatorsErr != nil {
+			return
+		}
+
+		registerValidatorsErr = v.RegisterValidation("amount", validAmount)
+	})
+
+	return registerValidatorsErr
+}
+
+// validCurrency is the "currency" binding validator,
+// it accepts the codes formed as currency codes
+func validCurrency(fieldLevel validator.FieldLevel) bool {
+	code, ok := fieldLevel.Field().Interface().(db.Currency)
+	if !ok {
+		return false
+	}
+
+	return currencyCodePattern.MatchString(string(code))
+}
+
+// validAmount is the "amount" binding validator for decimal.Decimal fields,
+// it accepts positive amounts up to maxAmount
+func validAmount(fieldLevel validator.FieldLevel) bool {
+	amount, ok := fieldLevel.Field().Interface().(decimal.Decimal)
+	if !ok {
+		return false
+	}
+
+	return amount.IsPositive() && !amount.GreaterThan(maxAmount)
+}
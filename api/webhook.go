@@ -0,0 +1,192 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/webhook"
+	"github.com/gin-gonic/gin"
+)
+
+var errWebhookEndpointNotOwned = newAPIError(
+	http.StatusForbidden,
+	"webhook_endpoint_not_owned",
+	"webhook endpoint doesn't belong to the authenticated user")
+
+type webhookEndpointResponse struct {
+	ID         int64     `json:"id"`
+	Url        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	Secret     string    `json:"secret,omitempty"`
+}
+
+// newWebhookEndpointResponse hides the secret of the endpoint,
+// it is only returned once when the endpoint is created
+func newWebhookEndpointResponse(endpoint db.WebhookEndpoint) webhookEndpointResponse {
+	return webhookEndpointResponse{
+		ID:         endpoint.ID,
+		Url:        endpoint.Url,
+		EventTypes: endpoint.EventTypes,
+		IsActive:   endpoint.IsActive,
+		CreatedAt:  endpoint.CreatedAt.Time,
+	}
+}
+
+type createWebhookEndpointRequest struct {
+	Url        string   `json:"url" binding:"required,url,max=2048"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,unique,dive,oneof=transfer.created deposit.created withdrawal.created transfer.reversed account.closed"`
+}
+
+func (server *Server) createWebhookEndpointHandler(ctx *gin.Context) {
+	var req createWebhookEndpointRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if err := webhook.ValidateURL(req.Url); err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	endpoint, err := server.store.CreateWebhookEndpoint(ctx, db.CreateWebhookEndpointParams{
+		Owner:      authPayload.Username,
+		Url:        req.Url,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	response := newWebhookEndpointResponse(endpoint)
+	response.Secret = endpoint.Secret
+	ctx.JSON(http.StatusOK, response)
+}
+
+type listWebhookEndpointsRequest struct {
+	PageId   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server *Server) listWebhookEndpointsHandler(ctx *gin.Context) {
+	var req listWebhookEndpointsRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	endpoints, err := server.store.ListWebhookEndpointsByOwner(ctx, db.ListWebhookEndpointsByOwnerParams{
+		Owner:  authPayload.Username,
+		Limit:  req.PageSize,
+		Offset: (req.PageId - 1) * req.PageSize,
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	response := make([]webhookEndpointResponse, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		response = append(response, newWebhookEndpointResponse(endpoint))
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+type webhookEndpointUri struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// disableWebhookEndpointHandler stops the deliveries to an endpoint.
+// The endpoint is kept with its delivery log.
+func (server *Server) disableWebhookEndpointHandler(ctx *gin.Context) {
+	var uri webhookEndpointUri
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if _, ok := server.getOwnedWebhookEndpoint(ctx, uri.ID); !ok {
+		return
+	}
+
+	endpoint, err := server.store.DisableWebhookEndpoint(ctx, uri.ID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newWebhookEndpointResponse(endpoint))
+}
+
+type listWebhookDeliveriesRequest struct {
+	PageId   int32 `form:"page_id" binding:"required,min=1"`
+	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+// listWebhookDeliveriesHandler returns the delivery log of an endpoint, latest first
+func (server *Server) listWebhookDeliveriesHandler(ctx *gin.Context) {
+	var uri webhookEndpointUri
+	var req listWebhookDeliveriesRequest
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		errorResponse(ctx, invalidRequest(err))
+		return
+	}
+
+	if _, ok := server.getOwnedWebhookEndpoint(ctx, uri.ID); !ok {
+		return
+	}
+
+	deliveries, err := server.store.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		EndpointID: uri.ID,
+		Limit:      req.PageSize,
+		Offset:     (req.PageId - 1) * req.PageSize,
+	})
+	if err != nil {
+		errorResponse(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// getOwnedWebhookEndpoint fetches an endpoint of the authenticated user.
+// It writes the error response and returns false when it cannot be fetched or is not owned.
+func (server *Server) getOwnedWebhookEndpoint(ctx *gin.Context, endpointID int64) (db.WebhookEndpoint, bool) {
+	endpoint, err := server.store.GetWebhookEndpoint(ctx, endpointID)
+	if err != nil {
+		errorResponse(ctx, err)
+		return endpoint, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if endpoint.Owner != authPayload.Username {
+		errorResponse(ctx, errWebhookEndpointNotOwned)
+		return endpoint, false
+	}
+
+	return endpoint, true
+}
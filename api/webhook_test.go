@@ -0,0 +1,311 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/token"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWebhookEndpointApi(t *testing.T) {
+	user, _ := createRandomUser()
+	endpoint := db.WebhookEndpoint{
+		ID:         utils.RandomNumber(1, 1000),
+		Owner:      user.Name,
+		Url:        "https://example.com/hooks",
+		Secret:     "whsec_secret",
+		EventTypes: []string{db.EventTransferCreated, db.EventAccountClosed},
+		IsActive:   true,
+	}
+
+	disabledEndpoint := endpoint
+	disabledEndpoint.IsActive = false
+
+	userAuth := func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+		addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Name, utils.DepositorRole, time.Minute)
+	}
+
+	otherUserAuth := func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+		addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "other", utils.DepositorRole, time.Minute)
+	}
+
+	testCases := []struct {
+		name             string
+		method           string
+		url              string
+		body             gin.H
+		setupAuth        func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubFunc    func(store *mockdb.MockStore)
+		validateResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "CreateOK",
+			method: http.MethodPost,
+			url:    "/api/webhooks",
+			body: gin.H{
+				"url":         endpoint.Url,
+				"event_types": endpoint.EventTypes,
+			},
+			setupAuth: userAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateWebhookEndpoint(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateWebhookEndpointParams) (db.WebhookEndpoint, error) {
+						assert.Equal(t, user.Name, arg.Owner)
+						assert.Equal(t, endpoint.Url, arg.Url)
+						assert.Equal(t, endpoint.EventTypes, arg.EventTypes)
+						assert.Contains(t, arg.Secret, "whsec_")
+
+						created := endpoint
+						created.Secret = arg.Secret
+						return created, nil
+					})
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var response webhookEndpointResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, endpoint.ID, response.ID)
+				assert.Contains(t, response.Secret, "whsec_")
+			},
+		},
+		{
+			name:   "CreateInvalidEventType",
+			method: http.MethodPost,
+			url:    "/api/webhooks",
+			body: gin.H{
+				"url":         endpoint.Url,
+				"event_types": []string{"transfer.deleted"},
+			},
+			setupAuth: userAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateInvalidUrl",
+			method: http.MethodPost,
+			url:    "/api/webhooks",
+			body: gin.H{
+				"url":         "not-a-url",
+				"event_types": endpoint.EventTypes,
+			},
+			setupAuth: userAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "CreateInsecureUrl",
+			method: http.MethodPost,
+			url:    "/api/webhooks",
+			body: gin.H{
+				"url":         "http://example.com/hooks",
+				"event_types": endpoint.EventTypes,
+			},
+			setupAuth: userAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, webhook.ErrInsecureURL, recorder.Body)
+			},
+		},
+		{
+			name:   "CreateMetadataAddress",
+			method: http.MethodPost,
+			url:    "/api/webhooks",
+			body: gin.H{
+				"url":         "https://169.254.169.254/latest/meta-data",
+				"event_types": endpoint.EventTypes,
+			},
+			setupAuth: userAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+				assertError(t, webhook.ErrForbiddenAddress, recorder.Body)
+			},
+		},
+		{
+			name:      "CreateNoAuthorization",
+			method:    http.MethodPost,
+			url:       "/api/webhooks",
+			body:      gin.H{"url": endpoint.Url, "event_types": endpoint.EventTypes},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "ListOK",
+			method:    http.MethodGet,
+			url:       "/api/webhooks?page_id=1&page_size=5",
+			setupAuth: userAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.ListWebhookEndpointsByOwnerParams{
+					Owner:  user.Name,
+					Limit:  5,
+					Offset: 0,
+				}
+
+				store.EXPECT().
+					ListWebhookEndpointsByOwner(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.WebhookEndpoint{endpoint}, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var response []webhookEndpointResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Len(t, response, 1)
+				assert.Empty(t, response[0].Secret)
+			},
+		},
+		{
+			name:      "DisableOK",
+			method:    http.MethodDelete,
+			url:       fmt.Sprintf("/api/webhooks/%d", endpoint.ID),
+			setupAuth: userAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().DisableWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(disabledEndpoint, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var response webhookEndpointResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.False(t, response.IsActive)
+				assert.Empty(t, response.Secret)
+			},
+		},
+		{
+			name:      "DisableNotOwned",
+			method:    http.MethodDelete,
+			url:       fmt.Sprintf("/api/webhooks/%d", endpoint.ID),
+			setupAuth: otherUserAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().DisableWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusForbidden, recorder.Code)
+				assertError(t, errWebhookEndpointNotOwned, recorder.Body)
+			},
+		},
+		{
+			name:      "DisableNotFound",
+			method:    http.MethodDelete,
+			url:       fmt.Sprintf("/api/webhooks/%d", endpoint.ID),
+			setupAuth: userAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(db.WebhookEndpoint{}, pgx.ErrNoRows)
+				store.EXPECT().DisableWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "ListDeliveriesOK",
+			method:    http.MethodGet,
+			url:       fmt.Sprintf("/api/webhooks/%d/deliveries?page_id=1&page_size=5", endpoint.ID),
+			setupAuth: userAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				arg := db.ListWebhookDeliveriesParams{
+					EndpointID: endpoint.ID,
+					Limit:      5,
+					Offset:     0,
+				}
+
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().
+					ListWebhookDeliveries(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.WebhookDelivery{{ID: 1, EndpointID: endpoint.ID, Status: db.WebhookDeliveryStatusSucceeded}}, nil)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, recorder.Code)
+
+				var deliveries []db.WebhookDelivery
+				err := json.Unmarshal(recorder.Body.Bytes(), &deliveries)
+				assert.NoError(t, err)
+				assert.Len(t, deliveries, 1)
+			},
+		},
+		{
+			name:      "ListDeliveriesInvalidPageSize",
+			method:    http.MethodGet,
+			url:       fmt.Sprintf("/api/webhooks/%d/deliveries?page_id=1&page_size=50", endpoint.ID),
+			setupAuth: userAuth,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListWebhookDeliveries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateResponse: func(recorder *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var data []byte
+			if tc.body != nil {
+				var err error
+				data, err = json.Marshal(tc.body)
+				assert.NoError(t, err)
+			}
+
+			request, err := http.NewRequest(tc.method, tc.url, bytes.NewReader(data))
+			assert.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.validateResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Command reconcile checks that the balance of every account equals the sum of
+// its entries, and that every entry and transfer has its counterpart.
+// It prints a JSON report and exits with 1 when discrepancies are found,
+// or with 2 when the check could not run.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/reconcile"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	exitDiscrepancies = 1
+	exitFailure       = 2
+)
+
+func main() {
+	configPath := flag.String("config", "./", "directory of the app.env file")
+	batchSize := flag.Int("batch-size", 500, "number of rows read per query")
+	apply := flag.Bool("apply", false, "reset mismatching balances to the sum of their entries")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "reconcile: ", log.LstdFlags)
+
+	config, err := utils.LoadConfig(*configPath)
+	if err != nil {
+		logger.Println("fatal error while reading config file", err)
+		os.Exit(exitFailure)
+	}
+
+	connPool, err := pgxpool.New(context.Background(), config.DbUrl)
+	if err != nil {
+		logger.Println("Failed to connect to db", err)
+		os.Exit(exitFailure)
+	}
+	defer connPool.Close()
+
+	reconciler, err := reconcile.NewReconciler(db.NewStore(connPool), int32(*batchSize))
+	if err != nil {
+		logger.Println("Failed to create the reconciler", err)
+		os.Exit(exitFailure)
+	}
+
+	report, err := reconciler.Run(context.Background(), *apply)
+	if err != nil {
+		logger.Println("Failed to reconcile the ledger", err)
+		os.Exit(exitFailure)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(report)
+	if err != nil {
+		logger.Println("Failed to write the report", err)
+		os.Exit(exitFailure)
+	}
+
+	if report.HasDiscrepancies() {
+		os.Exit(exitDiscrepancies)
+	}
+}
@@ -0,0 +1,14 @@
+CREATE TABLE "idempotency_keys" (
+  "username" varchar NOT NULL,
+  "key" varchar(255) NOT NULL,
+  "request_hash" varchar(64) NOT NULL,
+  "response" jsonb NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+  CONSTRAINT "idempotency_keys_pkey" PRIMARY KEY ("username", "key")
+);
+
+CREATE INDEX ON "idempotency_keys" ("expires_at");
+
+ALTER TABLE "idempotency_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("name");
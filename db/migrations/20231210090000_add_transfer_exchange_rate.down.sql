@@ -0,0 +1,3 @@
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "rate_timestamp";
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "exchange_rate";
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "to_amount";
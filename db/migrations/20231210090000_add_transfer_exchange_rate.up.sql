@@ -0,0 +1,13 @@
+ALTER TABLE "transfers" ADD COLUMN "to_amount" decimal;
+ALTER TABLE "transfers" ADD COLUMN "exchange_rate" decimal NOT NULL DEFAULT 1;
+ALTER TABLE "transfers" ADD COLUMN "rate_timestamp" timestamptz;
+
+UPDATE "transfers" SET "to_amount" = "amount", "rate_timestamp" = "created_at";
+
+ALTER TABLE "transfers" ALTER COLUMN "to_amount" SET NOT NULL;
+ALTER TABLE "transfers" ALTER COLUMN "rate_timestamp" SET NOT NULL;
+ALTER TABLE "transfers" ALTER COLUMN "exchange_rate" DROP DEFAULT;
+
+COMMENT ON COLUMN "transfers"."amount" IS 'amount debited, in the currency of the from account';
+COMMENT ON COLUMN "transfers"."to_amount" IS 'amount credited, in the currency of the to account';
+COMMENT ON COLUMN "transfers"."exchange_rate" IS 'units of the to currency bought by one unit of the from currency';
@@ -0,0 +1,11 @@
+CREATE TYPE Currency AS ENUM (
+  'USD',
+  'EUR',
+  'INR'
+);
+
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "accounts_currency_fkey";
+
+ALTER TABLE "accounts" ALTER COLUMN "currency" TYPE Currency USING "currency"::Currency;
+
+DROP TABLE IF EXISTS "currencies";
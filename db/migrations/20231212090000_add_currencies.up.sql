@@ -0,0 +1,24 @@
+CREATE TABLE "currencies" (
+  "code" varchar(3) PRIMARY KEY,
+  "exponent" smallint NOT NULL,
+  "symbol" varchar NOT NULL,
+  "enabled" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+
+  CONSTRAINT "currencies_code_check" CHECK ("code" ~ '^[A-Z]{3}$'),
+  CONSTRAINT "currencies_exponent_check" CHECK ("exponent" BETWEEN 0 AND 4)
+);
+
+COMMENT ON COLUMN "currencies"."code" IS 'ISO 4217 alphabetic code';
+COMMENT ON COLUMN "currencies"."exponent" IS 'number of decimal places of the minor unit';
+
+INSERT INTO "currencies" ("code", "exponent", "symbol") VALUES
+  ('USD', 2, '$'),
+  ('EUR', 2, '€'),
+  ('INR', 2, '₹');
+
+ALTER TABLE "accounts" ALTER COLUMN "currency" TYPE varchar(3) USING "currency"::text;
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+DROP TYPE IF EXISTS Currency;
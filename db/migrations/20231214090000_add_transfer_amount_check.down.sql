@@ -0,0 +1,3 @@
+ALTER TABLE "transfers" DROP CONSTRAINT IF EXISTS "transfers_to_amount_check";
+
+ALTER TABLE "transfers" DROP CONSTRAINT IF EXISTS "transfers_amount_check";
@@ -0,0 +1,3 @@
+ALTER TABLE "transfers" ADD CONSTRAINT "transfers_amount_check" CHECK ("amount" > 0);
+
+ALTER TABLE "transfers" ADD CONSTRAINT "transfers_to_amount_check" CHECK ("to_amount" > 0);
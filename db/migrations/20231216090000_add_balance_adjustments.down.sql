@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "balance_adjustments";
+
+ALTER TABLE "users" DROP CONSTRAINT IF EXISTS "users_role_check";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "role";
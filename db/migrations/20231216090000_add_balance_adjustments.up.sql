@@ -0,0 +1,29 @@
+ALTER TABLE "users" ADD COLUMN "role" varchar NOT NULL DEFAULT 'depositor';
+
+ALTER TABLE "users" ADD CONSTRAINT "users_role_check" CHECK ("role" IN ('depositor', 'admin'));
+
+CREATE TABLE "balance_adjustments" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "entry_id" bigint NOT NULL,
+  "amount" decimal NOT NULL,
+  "reason_code" varchar NOT NULL,
+  "note" varchar NOT NULL DEFAULT '',
+  "operator" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "balance_adjustments_amount_check" CHECK ("amount" <> 0)
+);
+
+CREATE INDEX ON "balance_adjustments" ("account_id");
+
+CREATE UNIQUE INDEX ON "balance_adjustments" ("entry_id");
+
+COMMENT ON COLUMN "balance_adjustments"."amount" IS 'amount can be positive or negative, never zero';
+
+COMMENT ON COLUMN "balance_adjustments"."operator" IS 'name of the admin who made the adjustment';
+
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("operator") REFERENCES "users" ("name");
@@ -0,0 +1,13 @@
+DELETE FROM "transfers"
+WHERE "from_account_id" IN (SELECT "id" FROM "accounts" WHERE "is_system")
+   OR "to_account_id" IN (SELECT "id" FROM "accounts" WHERE "is_system");
+
+DELETE FROM "entries" WHERE "account_id" IN (SELECT "id" FROM "accounts" WHERE "is_system");
+
+DELETE FROM "accounts" WHERE "is_system";
+
+DROP INDEX IF EXISTS "accounts_system_currency_key";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "is_system";
+
+DELETE FROM "users" WHERE "name" = 'system';
@@ -0,0 +1,26 @@
+-- The system user owns the cash/suspense accounts. It has no password and cannot log in.
+INSERT INTO "users" ("name", "email", "hash_password") VALUES ('system', 'system@localhost', '');
+
+ALTER TABLE "accounts" ADD COLUMN "is_system" boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN "accounts"."is_system" IS 'cash/suspense account of a currency, counterpart of deposits, withdrawals and adjustments';
+
+CREATE UNIQUE INDEX "accounts_system_currency_key" ON "accounts" ("currency") WHERE "is_system";
+
+INSERT INTO "accounts" ("owner", "balance", "currency", "is_system")
+SELECT 'system', 0, "code", true FROM "currencies";
+
+-- Book the opposite of the existing entries on the system accounts,
+-- so that the entries of every currency net to zero from now on
+INSERT INTO "entries" ("account_id", "amount")
+SELECT s."id", -SUM(e."amount")
+FROM "accounts" s
+JOIN "accounts" a ON a."currency" = s."currency" AND NOT a."is_system"
+JOIN "entries" e ON e."account_id" = a."id"
+WHERE s."is_system"
+GROUP BY s."id"
+HAVING SUM(e."amount") <> 0;
+
+UPDATE "accounts" s
+SET "balance" = (SELECT COALESCE(SUM(e."amount"), 0) FROM "entries" e WHERE e."account_id" = s."id")
+WHERE s."is_system";
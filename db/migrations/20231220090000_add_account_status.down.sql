@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS "owner_currency_key";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "owner_currency_key" UNIQUE ("owner", "currency");
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "status";
+
+DROP TYPE IF EXISTS account_status;
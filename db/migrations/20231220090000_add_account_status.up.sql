@@ -0,0 +1,14 @@
+CREATE TYPE account_status AS ENUM (
+  'active',
+  'frozen',
+  'closed'
+);
+
+ALTER TABLE "accounts" ADD COLUMN "status" account_status NOT NULL DEFAULT 'active';
+
+COMMENT ON COLUMN "accounts"."status" IS 'only active accounts can send or receive transfers';
+
+-- A closed account keeps its history, the owner can open a new one in the same currency
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "owner_currency_key";
+
+CREATE UNIQUE INDEX "owner_currency_key" ON "accounts" ("owner", "currency") WHERE "status" <> 'closed';
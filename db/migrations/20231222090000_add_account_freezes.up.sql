@@ -0,0 +1,25 @@
+CREATE TABLE "account_freezes" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "reason" varchar NOT NULL,
+  "frozen_by" varchar NOT NULL,
+  "frozen_at" timestamptz NOT NULL DEFAULT (now()),
+  "unfreeze_reason" varchar,
+  "unfrozen_by" varchar,
+  "unfrozen_at" timestamptz
+);
+
+COMMENT ON COLUMN "account_freezes"."frozen_by" IS 'name of the admin who froze the account';
+
+COMMENT ON COLUMN "account_freezes"."unfrozen_at" IS 'null while the freeze is in place';
+
+CREATE INDEX ON "account_freezes" ("account_id");
+
+-- An account has at most one freeze in place
+CREATE UNIQUE INDEX "account_freezes_active_key" ON "account_freezes" ("account_id") WHERE "unfrozen_at" IS NULL;
+
+ALTER TABLE "account_freezes" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "account_freezes" ADD FOREIGN KEY ("frozen_by") REFERENCES "users" ("name");
+
+ALTER TABLE "account_freezes" ADD FOREIGN KEY ("unfrozen_by") REFERENCES "users" ("name");
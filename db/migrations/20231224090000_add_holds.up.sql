@@ -0,0 +1,37 @@
+CREATE TYPE hold_status AS ENUM (
+  'active',
+  'captured',
+  'released',
+  'expired'
+);
+
+CREATE TABLE "holds" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "amount" numeric NOT NULL,
+  "captured_amount" numeric NOT NULL DEFAULT 0,
+  "status" hold_status NOT NULL DEFAULT 'active',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "settled_at" timestamptz
+);
+
+COMMENT ON COLUMN "holds"."amount" IS 'reserved on the account while the hold is active';
+
+COMMENT ON COLUMN "holds"."captured_amount" IS 'can be lower than amount for partial captures';
+
+COMMENT ON COLUMN "holds"."transfer_id" IS 'transfer created by the capture';
+
+ALTER TABLE "holds" ADD CONSTRAINT "holds_amount_check" CHECK ("amount" > 0);
+
+ALTER TABLE "holds" ADD CONSTRAINT "holds_captured_amount_check" CHECK ("captured_amount" >= 0 AND "captured_amount" <= "amount");
+
+-- Active holds are summed on every transfer and scanned by the expiry
+CREATE INDEX ON "holds" ("account_id") WHERE "status" = 'active';
+
+CREATE INDEX ON "holds" ("expires_at") WHERE "status" = 'active';
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
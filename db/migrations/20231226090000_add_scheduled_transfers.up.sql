@@ -0,0 +1,65 @@
+CREATE TYPE schedule_frequency AS ENUM (
+  'once',
+  'daily',
+  'weekly',
+  'monthly'
+);
+
+CREATE TYPE scheduled_transfer_status AS ENUM (
+  'active',
+  'paused',
+  'cancelled',
+  'completed'
+);
+
+CREATE TABLE "scheduled_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" numeric NOT NULL,
+  "frequency" schedule_frequency NOT NULL,
+  "interval_count" integer NOT NULL DEFAULT 1,
+  "start_at" timestamptz NOT NULL,
+  "end_at" timestamptz,
+  "next_run_at" timestamptz NOT NULL,
+  "status" scheduled_transfer_status NOT NULL DEFAULT 'active',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "scheduled_transfer_runs" (
+  "id" bigserial PRIMARY KEY,
+  "scheduled_transfer_id" bigint NOT NULL,
+  "scheduled_for" timestamptz NOT NULL,
+  "transfer_id" bigint,
+  "error" varchar,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "scheduled_transfers"."interval_count" IS 'number of frequency units between two runs, eg: 2 weeks';
+
+COMMENT ON COLUMN "scheduled_transfers"."end_at" IS 'no run is scheduled after it, null to run until cancelled';
+
+COMMENT ON COLUMN "scheduled_transfer_runs"."error" IS 'set when the transfer failed, the run is not retried';
+
+ALTER TABLE "scheduled_transfers" ADD CONSTRAINT "scheduled_transfers_amount_check" CHECK ("amount" > 0);
+
+ALTER TABLE "scheduled_transfers" ADD CONSTRAINT "scheduled_transfers_interval_count_check" CHECK ("interval_count" >= 1);
+
+CREATE INDEX ON "scheduled_transfers" ("owner");
+
+-- The scheduler only looks for active schedules which are due
+CREATE INDEX ON "scheduled_transfers" ("next_run_at") WHERE "status" = 'active';
+
+-- An occurrence is executed at most once, whichever replica runs it
+ALTER TABLE "scheduled_transfer_runs" ADD CONSTRAINT "scheduled_transfer_runs_occurrence_key" UNIQUE ("scheduled_transfer_id", "scheduled_for");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("name");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("scheduled_transfer_id") REFERENCES "scheduled_transfers" ("id");
+
+ALTER TABLE "scheduled_transfer_runs" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,20 @@
+CREATE TABLE "outbox_events" (
+  "id" bigserial PRIMARY KEY,
+  "event_type" varchar NOT NULL,
+  "aggregate_id" bigint NOT NULL,
+  "payload" jsonb NOT NULL,
+  "attempts" integer NOT NULL DEFAULT 0,
+  "last_error" varchar,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "published_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "outbox_events"."aggregate_id" IS 'id of the record the event is about, eg: the transfer id';
+
+COMMENT ON COLUMN "outbox_events"."next_attempt_at" IS 'pushed back while a relay publishes the event and after each failure';
+
+COMMENT ON COLUMN "outbox_events"."published_at" IS 'null until the event is published';
+
+-- The relay only looks for events left to publish
+CREATE INDEX ON "outbox_events" ("next_attempt_at") WHERE "published_at" IS NULL;
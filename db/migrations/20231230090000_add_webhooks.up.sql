@@ -0,0 +1,50 @@
+CREATE TYPE webhook_delivery_status AS ENUM (
+  'pending',
+  'succeeded',
+  'dead'
+);
+
+CREATE TABLE "webhook_endpoints" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "url" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "event_types" varchar[] NOT NULL,
+  "is_active" boolean NOT NULL DEFAULT true,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "webhook_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "endpoint_id" bigint NOT NULL,
+  "event_id" bigint NOT NULL,
+  "status" webhook_delivery_status NOT NULL DEFAULT 'pending',
+  "attempts" integer NOT NULL DEFAULT 0,
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_status_code" integer,
+  "last_error" varchar,
+  "delivered_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "webhook_endpoints"."secret" IS 'key of the HMAC signature of the deliveries';
+
+COMMENT ON COLUMN "webhook_endpoints"."event_types" IS 'types of the events delivered to the endpoint';
+
+COMMENT ON COLUMN "webhook_deliveries"."status" IS 'dead once all the attempts failed';
+
+CREATE INDEX ON "webhook_endpoints" ("owner");
+
+CREATE INDEX ON "webhook_deliveries" ("endpoint_id");
+
+-- The dispatcher only looks for deliveries left to make
+CREATE INDEX ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+
+-- An event is delivered once to each endpoint
+ALTER TABLE "webhook_deliveries" ADD CONSTRAINT "webhook_deliveries_endpoint_event_key" UNIQUE ("endpoint_id", "event_id");
+
+ALTER TABLE "webhook_endpoints" ADD FOREIGN KEY ("owner") REFERENCES "users" ("name");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("endpoint_id") REFERENCES "webhook_endpoints" ("id");
+
+ALTER TABLE "webhook_deliveries" ADD FOREIGN KEY ("event_id") REFERENCES "outbox_events" ("id");
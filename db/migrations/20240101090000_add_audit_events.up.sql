@@ -0,0 +1,44 @@
+CREATE TABLE "audit_events" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "resource_type" varchar NOT NULL,
+  "resource_id" varchar NOT NULL,
+  "before" json,
+  "after" json,
+  "request_id" varchar NOT NULL DEFAULT '',
+  "client_ip" varchar NOT NULL DEFAULT '',
+  "prev_hash" varchar NOT NULL,
+  "hash" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL
+);
+
+COMMENT ON COLUMN "audit_events"."actor" IS 'name of the user who made the change, system for the background workers';
+
+COMMENT ON COLUMN "audit_events"."before" IS 'state of the resource before the change, null when it is created';
+
+COMMENT ON COLUMN "audit_events"."prev_hash" IS 'hash of the previous event, empty for the first one';
+
+COMMENT ON COLUMN "audit_events"."hash" IS 'SHA-256 of the event fields and prev_hash';
+
+CREATE INDEX ON "audit_events" ("actor");
+
+CREATE INDEX ON "audit_events" ("resource_type", "resource_id");
+
+-- Two events chained to the same one mean the chain forked
+CREATE UNIQUE INDEX "audit_events_prev_hash_key" ON "audit_events" ("prev_hash");
+
+-- The audit log is append only
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "audit_events_no_update_delete"
+  BEFORE UPDATE OR DELETE ON "audit_events"
+  FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER "audit_events_no_truncate"
+  BEFORE TRUNCATE ON "audit_events"
+  FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
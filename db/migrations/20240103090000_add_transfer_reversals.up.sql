@@ -0,0 +1,7 @@
+ALTER TABLE "transfers" ADD COLUMN "reversal_of" bigint;
+
+COMMENT ON COLUMN "transfers"."reversal_of" IS 'id of the transfer this transfer reverses, null for a regular transfer';
+
+CREATE INDEX ON "transfers" ("reversal_of") WHERE "reversal_of" IS NOT NULL;
+
+ALTER TABLE "transfers" ADD FOREIGN KEY ("reversal_of") REFERENCES "transfers" ("id");
@@ -0,0 +1,45 @@
+CREATE TABLE "limits" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint,
+  "owner" varchar,
+  "currency" varchar(3) NOT NULL,
+  "max_single_transfer" numeric,
+  "daily_outgoing" numeric,
+  "monthly_outgoing" numeric,
+  "max_transfers_per_hour" integer,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "limits"."account_id" IS 'set for the limits of an account, null for the limits of a user';
+
+COMMENT ON COLUMN "limits"."owner" IS 'set for the limits of a user, applied to all its accounts in currency';
+
+COMMENT ON COLUMN "limits"."daily_outgoing" IS 'total sent during a calendar day (UTC), null for no limit';
+
+COMMENT ON COLUMN "limits"."monthly_outgoing" IS 'total sent during a calendar month (UTC), null for no limit';
+
+COMMENT ON COLUMN "limits"."max_transfers_per_hour" IS 'number of transfers sent during the last hour, null for no limit';
+
+ALTER TABLE "limits" ADD CONSTRAINT "limits_scope_check" CHECK (("account_id" IS NULL) <> ("owner" IS NULL));
+
+ALTER TABLE "limits" ADD CONSTRAINT "limits_values_check" CHECK (
+  "max_single_transfer" > 0 AND
+  "daily_outgoing" > 0 AND
+  "monthly_outgoing" > 0 AND
+  "max_transfers_per_hour" > 0
+);
+
+-- An account has a single currency, a user has limits per currency
+CREATE UNIQUE INDEX "limits_account_id_key" ON "limits" ("account_id") WHERE "account_id" IS NOT NULL;
+
+CREATE UNIQUE INDEX "limits_owner_currency_key" ON "limits" ("owner", "currency") WHERE "owner" IS NOT NULL;
+
+ALTER TABLE "limits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "limits" ADD FOREIGN KEY ("owner") REFERENCES "users" ("name");
+
+ALTER TABLE "limits" ADD FOREIGN KEY ("currency") REFERENCES "currencies" ("code");
+
+-- Outgoing totals are summed on every transfer from an account with limits
+CREATE INDEX "transfers_from_account_id_created_at_idx" ON "transfers" ("from_account_id", "created_at");
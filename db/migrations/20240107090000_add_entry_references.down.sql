@@ -0,0 +1,5 @@
+DELETE FROM "balance_adjustments" WHERE "reason_code" = 'opening_balance';
+
+ALTER TABLE "balance_adjustments" DROP COLUMN IF EXISTS "counter_entry_id";
+
+ALTER TABLE "entries" DROP COLUMN IF EXISTS "transfer_id";
@@ -0,0 +1,45 @@
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer the entry belongs to, null for the entries of balance adjustments';
+
+CREATE INDEX ON "entries" ("transfer_id") WHERE "transfer_id" IS NOT NULL;
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "balance_adjustments" ADD COLUMN "counter_entry_id" bigint;
+
+COMMENT ON COLUMN "balance_adjustments"."counter_entry_id" IS 'entry on the system account balancing the adjustment, null for opening balances';
+
+CREATE UNIQUE INDEX ON "balance_adjustments" ("counter_entry_id");
+
+ALTER TABLE "balance_adjustments" ADD FOREIGN KEY ("counter_entry_id") REFERENCES "entries" ("id");
+
+-- Until now, entries were only related to their transfer or adjustment by
+-- being created in the same transaction, so they share its created_at
+UPDATE "entries" e
+SET "transfer_id" = t."id"
+FROM "transfers" t
+WHERE t."created_at" = e."created_at"
+  AND ((t."from_account_id" = e."account_id" AND e."amount" = -t."amount")
+    OR (t."to_account_id" = e."account_id" AND e."amount" = t."to_amount"));
+
+UPDATE "balance_adjustments" b
+SET "counter_entry_id" = e."id"
+FROM "accounts" a, "accounts" s, "entries" e
+WHERE a."id" = b."account_id"
+  AND s."currency" = a."currency" AND s."is_system"
+  AND e."account_id" = s."id"
+  AND e."created_at" = b."created_at"
+  AND e."amount" = -b."amount"
+  AND e."transfer_id" IS NULL;
+
+-- The remaining entries were booked without a transfer or an adjustment, like the
+-- balancing entries of the system accounts migration, they are recorded as opening balances
+INSERT INTO "balance_adjustments" ("account_id", "entry_id", "amount", "reason_code", "note", "operator", "created_at")
+SELECT e."account_id", e."id", e."amount", 'opening_balance', 'entry booked before entries referenced their transfer', 'system', e."created_at"
+FROM "entries" e
+WHERE e."transfer_id" IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM "balance_adjustments" b
+    WHERE b."entry_id" = e."id" OR b."counter_entry_id" = e."id"
+  );
@@ -0,0 +1,14 @@
+-- Audit events are recorded with the change they describe, and chained to the
+-- audit log afterwards, so that the writers of the log don't wait for each other
+CREATE TABLE "pending_audit_events" (
+  "id" bigserial PRIMARY KEY,
+  "actor" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "resource_type" varchar NOT NULL,
+  "resource_id" varchar NOT NULL,
+  "before" json,
+  "after" json,
+  "request_id" varchar NOT NULL DEFAULT '',
+  "client_ip" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL
+);
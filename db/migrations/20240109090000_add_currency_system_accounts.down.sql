@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS "currencies_system_account" ON "currencies";
+
+DROP FUNCTION IF EXISTS currencies_create_system_account();
@@ -0,0 +1,23 @@
+-- Every currency added to the catalogue gets its system account,
+-- like the currencies present when the system accounts were added
+CREATE FUNCTION currencies_create_system_account() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO "accounts" ("owner", "balance", "currency", "is_system")
+  VALUES ('system', 0, NEW."code", true)
+  ON CONFLICT DO NOTHING;
+
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "currencies_system_account"
+  AFTER INSERT ON "currencies"
+  FOR EACH ROW EXECUTE FUNCTION currencies_create_system_account();
+
+-- The currencies added since then have none yet
+INSERT INTO "accounts" ("owner", "balance", "currency", "is_system")
+SELECT 'system', 0, c."code", true
+FROM "currencies" c
+WHERE NOT EXISTS (
+  SELECT 1 FROM "accounts" a WHERE a."currency" = c."code" AND a."is_system"
+);
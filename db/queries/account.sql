@@ -7,11 +7,16 @@ SELECT * FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE;
 
--- name: ListAccounts :many
+-- name: GetSystemAccount :one
 SELECT * FROM accounts
+WHERE is_system AND currency = $1 LIMIT 1;
+
+-- name: ListAccountsByOwner :many
+SELECT * FROM accounts
+WHERE owner = $1
 ORDER BY id
-LIMIT $1
-OFFSET $2;
+LIMIT $2
+OFFSET $3;
 
 -- name: CreateAccount :one
 INSERT INTO accounts (
@@ -23,9 +28,9 @@ INSERT INTO accounts (
 )
 RETURNING *;
 
--- name: UpdateAccount :one
+-- name: UpdateAccountOverdraftLimit :one
 UPDATE accounts
-  set balance = $2
+  set overdraft_limit = $2
 WHERE id = $1
 RETURNING *;
 
@@ -35,6 +40,8 @@ UPDATE accounts
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
--- name: DeleteAccount :exec
-DELETE FROM accounts
-WHERE id = $1;
\ No newline at end of file
+-- name: UpdateAccountStatus :one
+UPDATE accounts
+  set status = $2
+WHERE id = $1
+RETURNING *;
\ No newline at end of file
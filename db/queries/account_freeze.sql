@@ -0,0 +1,30 @@
+-- name: CreateAccountFreeze :one
+INSERT INTO account_freezes (
+  account_id,
+  reason,
+  frozen_by
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetActiveAccountFreeze :one
+SELECT * FROM account_freezes
+WHERE account_id = $1 AND unfrozen_at IS NULL
+LIMIT 1;
+
+-- name: ReleaseAccountFreeze :one
+UPDATE account_freezes
+  set
+  unfreeze_reason = sqlc.arg(unfreeze_reason),
+  unfrozen_by = sqlc.arg(unfrozen_by),
+  unfrozen_at = now()
+WHERE account_id = sqlc.arg(account_id) AND unfrozen_at IS NULL
+RETURNING *;
+
+-- name: ListAccountFreezes :many
+SELECT * FROM account_freezes
+WHERE account_id = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
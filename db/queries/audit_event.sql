@@ -0,0 +1,67 @@
+-- name: LockAuditChain :exec
+-- Serializes the chaining of the pending events until the end of the transaction,
+-- so that each event is chained to the last committed one
+SELECT pg_advisory_xact_lock(hashtext('audit_events'));
+
+-- name: GetLastAuditEvent :one
+SELECT * FROM audit_events
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: CreateAuditEvent :one
+INSERT INTO audit_events (
+  actor,
+  action,
+  resource_type,
+  resource_id,
+  before,
+  after,
+  request_id,
+  client_ip,
+  prev_hash,
+  hash,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+)
+RETURNING *;
+
+-- name: ListAuditEvents :many
+SELECT * FROM audit_events
+WHERE (sqlc.narg(actor)::varchar IS NULL OR actor = sqlc.narg(actor))
+  AND (sqlc.narg(resource_type)::varchar IS NULL OR resource_type = sqlc.narg(resource_type))
+  AND (sqlc.narg(resource_id)::varchar IS NULL OR resource_id = sqlc.narg(resource_id))
+ORDER BY id DESC
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: ListAuditEventsAfter :many
+SELECT * FROM audit_events
+WHERE id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: CreatePendingAuditEvent :one
+INSERT INTO pending_audit_events (
+  actor,
+  action,
+  resource_type,
+  resource_id,
+  before,
+  after,
+  request_id,
+  client_ip,
+  created_at
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: ListPendingAuditEvents :many
+SELECT * FROM pending_audit_events
+ORDER BY id
+LIMIT $1;
+
+-- name: DeletePendingAuditEvents :exec
+DELETE FROM pending_audit_events
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
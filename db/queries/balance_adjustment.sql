@@ -0,0 +1,24 @@
+-- name: CreateBalanceAdjustment :one
+INSERT INTO balance_adjustments (
+  account_id,
+  entry_id,
+  amount,
+  reason_code,
+  note,
+  operator,
+  counter_entry_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetBalanceAdjustment :one
+SELECT * FROM balance_adjustments
+WHERE id = $1 LIMIT 1;
+
+-- name: ListAccountBalanceAdjustments :many
+SELECT * FROM balance_adjustments
+WHERE account_id = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
@@ -11,8 +11,26 @@ OFFSET $2;
 -- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  transfer_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3
 )
 RETURNING *;
+
+-- name: ListAccountEntries :many
+SELECT * FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint)
+  AND created_at < sqlc.arg(before_created_at)::timestamptz
+ORDER BY created_at, id
+LIMIT sqlc.arg(limit_count);
+
+-- name: SumAccountEntries :one
+SELECT COALESCE(SUM(amount), 0)::decimal AS total FROM entries
+WHERE account_id = $1;
+
+-- name: SumAccountEntriesAfter :one
+SELECT COALESCE(SUM(amount), 0)::decimal AS total FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND (created_at, id) > (sqlc.arg(after_created_at)::timestamptz, sqlc.arg(after_id)::bigint);
@@ -0,0 +1,52 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+  account_id,
+  amount,
+  expires_at
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListAccountHolds :many
+SELECT * FROM holds
+WHERE account_id = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: GetAccountHeldAmount :one
+SELECT COALESCE(SUM(amount), 0)::numeric AS held_amount FROM holds
+WHERE account_id = $1 AND status = 'active' AND expires_at > now();
+
+-- name: SettleHold :one
+UPDATE holds
+  set
+  status = sqlc.arg(status),
+  captured_amount = sqlc.arg(captured_amount),
+  settled_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SetHoldTransfer :one
+UPDATE holds
+  set transfer_id = sqlc.arg(transfer_id)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ExpireHolds :many
+UPDATE holds
+  set
+  status = 'expired',
+  settled_at = now()
+WHERE status = 'active' AND expires_at <= now()
+RETURNING *;
@@ -0,0 +1,27 @@
+-- name: LockIdempotencyKey :exec
+SELECT pg_advisory_xact_lock(hashtextextended(sqlc.arg(username)::text || ':' || sqlc.arg(key)::text, 0));
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE username = $1 AND key = $2 LIMIT 1;
+
+-- name: UpsertIdempotencyKey :one
+INSERT INTO idempotency_keys (
+  username,
+  key,
+  request_hash,
+  response,
+  expires_at
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (username, key) DO UPDATE
+  SET request_hash = EXCLUDED.request_hash,
+      response = EXCLUDED.response,
+      expires_at = EXCLUDED.expires_at,
+      created_at = now()
+RETURNING *;
+
+-- name: DeleteExpiredIdempotencyKeys :exec
+DELETE FROM idempotency_keys
+WHERE expires_at <= now();
@@ -0,0 +1,97 @@
+-- name: UpsertAccountLimit :one
+INSERT INTO limits (
+  account_id,
+  currency,
+  max_single_transfer,
+  daily_outgoing,
+  monthly_outgoing,
+  max_transfers_per_hour
+) VALUES (
+  sqlc.arg(account_id)::bigint,
+  sqlc.arg(currency),
+  sqlc.narg(max_single_transfer),
+  sqlc.narg(daily_outgoing),
+  sqlc.narg(monthly_outgoing),
+  sqlc.narg(max_transfers_per_hour)
+)
+ON CONFLICT (account_id) WHERE account_id IS NOT NULL DO UPDATE
+  set
+  max_single_transfer = EXCLUDED.max_single_transfer,
+  daily_outgoing = EXCLUDED.daily_outgoing,
+  monthly_outgoing = EXCLUDED.monthly_outgoing,
+  max_transfers_per_hour = EXCLUDED.max_transfers_per_hour,
+  updated_at = now()
+RETURNING *;
+
+-- name: UpsertUserLimit :one
+INSERT INTO limits (
+  owner,
+  currency,
+  max_single_transfer,
+  daily_outgoing,
+  monthly_outgoing,
+  max_transfers_per_hour
+) VALUES (
+  sqlc.arg(owner)::varchar,
+  sqlc.arg(currency),
+  sqlc.narg(max_single_transfer),
+  sqlc.narg(daily_outgoing),
+  sqlc.narg(monthly_outgoing),
+  sqlc.narg(max_transfers_per_hour)
+)
+ON CONFLICT (owner, currency) WHERE owner IS NOT NULL DO UPDATE
+  set
+  max_single_transfer = EXCLUDED.max_single_transfer,
+  daily_outgoing = EXCLUDED.daily_outgoing,
+  monthly_outgoing = EXCLUDED.monthly_outgoing,
+  max_transfers_per_hour = EXCLUDED.max_transfers_per_hour,
+  updated_at = now()
+RETURNING *;
+
+-- name: GetLimit :one
+SELECT * FROM limits
+WHERE id = $1 LIMIT 1;
+
+-- name: ListLimits :many
+SELECT * FROM limits
+WHERE
+  (sqlc.narg(account_id)::bigint IS NULL OR account_id = sqlc.narg(account_id))
+  AND (sqlc.narg(owner)::varchar IS NULL OR owner = sqlc.narg(owner))
+ORDER BY id
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: DeleteLimit :exec
+DELETE FROM limits
+WHERE id = $1;
+
+-- name: ListTransferLimits :many
+-- Limits of the account and of its owner in the currency of the account
+SELECT * FROM limits
+WHERE account_id = sqlc.arg(account_id)::bigint
+  OR (owner = sqlc.arg(owner)::varchar AND currency = sqlc.arg(currency))
+ORDER BY id;
+
+-- name: GetAccountOutgoingUsage :one
+-- Reversals are refunds, they don't count against the limits of the sender
+SELECT
+  COALESCE(SUM(amount) FILTER (WHERE created_at >= sqlc.arg(day_start)), 0)::numeric AS daily_total,
+  COALESCE(SUM(amount) FILTER (WHERE created_at >= sqlc.arg(month_start)), 0)::numeric AS monthly_total,
+  COUNT(*) FILTER (WHERE created_at >= sqlc.arg(hour_start)) AS hourly_count
+FROM transfers
+WHERE from_account_id = sqlc.arg(account_id)
+  AND reversal_of IS NULL
+  AND created_at >= LEAST(sqlc.arg(month_start)::timestamptz, sqlc.arg(hour_start)::timestamptz);
+
+-- name: GetUserOutgoingUsage :one
+-- Same as GetAccountOutgoingUsage, over all the accounts of the owner in the currency
+SELECT
+  COALESCE(SUM(t.amount) FILTER (WHERE t.created_at >= sqlc.arg(day_start)), 0)::numeric AS daily_total,
+  COALESCE(SUM(t.amount) FILTER (WHERE t.created_at >= sqlc.arg(month_start)), 0)::numeric AS monthly_total,
+  COUNT(*) FILTER (WHERE t.created_at >= sqlc.arg(hour_start)) AS hourly_count
+FROM transfers t
+JOIN accounts a ON a.id = t.from_account_id
+WHERE a.owner = sqlc.arg(owner)
+  AND a.currency = sqlc.arg(currency)
+  AND t.reversal_of IS NULL
+  AND t.created_at >= LEAST(sqlc.arg(month_start)::timestamptz, sqlc.arg(hour_start)::timestamptz);
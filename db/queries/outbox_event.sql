@@ -0,0 +1,42 @@
+-- name: CreateOutboxEvent :one
+INSERT INTO outbox_events (
+  event_type,
+  aggregate_id,
+  payload
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetOutboxEvent :one
+SELECT * FROM outbox_events
+WHERE id = $1 LIMIT 1;
+
+-- name: LeaseOutboxEvents :many
+-- The leased events are hidden from the other relays until leased_until,
+-- they are published again if the relay stops before marking them
+UPDATE outbox_events
+  set next_attempt_at = sqlc.arg(leased_until)
+WHERE id IN (
+  SELECT id FROM outbox_events
+  WHERE published_at IS NULL AND next_attempt_at <= now()
+  ORDER BY id
+  LIMIT sqlc.arg(limit_count)
+  FOR NO KEY UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkOutboxEventPublished :one
+UPDATE outbox_events
+  set published_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkOutboxEventFailed :one
+UPDATE outbox_events
+  set
+  attempts = attempts + 1,
+  last_error = sqlc.arg(last_error),
+  next_attempt_at = sqlc.arg(next_attempt_at)
+WHERE id = sqlc.arg(id)
+RETURNING *;
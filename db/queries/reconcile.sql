@@ -0,0 +1,44 @@
+-- name: ListAccountBalances :many
+SELECT
+  a.id,
+  a.currency,
+  a.balance,
+  COALESCE(SUM(e.amount), 0)::decimal AS entries_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id
+WHERE a.id > sqlc.arg(after_id)
+GROUP BY a.id
+ORDER BY a.id
+LIMIT sqlc.arg(limit_count);
+
+-- name: ListOrphanEntries :many
+-- Every entry references its transfer, or is referenced by its balance adjustment
+SELECT e.* FROM entries e
+WHERE e.id > sqlc.arg(after_id)
+  AND e.transfer_id IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM balance_adjustments b
+    WHERE b.entry_id = e.id OR b.counter_entry_id = e.id
+  )
+ORDER BY e.id
+LIMIT sqlc.arg(limit_count);
+
+-- name: ListOrphanTransfers :many
+SELECT t.* FROM transfers t
+WHERE t.id > sqlc.arg(after_id)
+  AND (
+    NOT EXISTS (
+      SELECT 1 FROM entries e
+      WHERE e.transfer_id = t.id
+        AND e.account_id = t.from_account_id
+        AND e.amount = -t.amount
+    )
+    OR NOT EXISTS (
+      SELECT 1 FROM entries e
+      WHERE e.transfer_id = t.id
+        AND e.account_id = t.to_account_id
+        AND e.amount = t.to_amount
+    )
+  )
+ORDER BY t.id
+LIMIT sqlc.arg(limit_count);
@@ -0,0 +1,79 @@
+-- name: CreateScheduledTransfer :one
+-- Nothing is inserted when start_at is not in the future,
+-- as the schedules are run with the clock of the database
+INSERT INTO scheduled_transfers (
+  owner,
+  from_account_id,
+  to_account_id,
+  amount,
+  frequency,
+  interval_count,
+  start_at,
+  end_at,
+  next_run_at
+)
+SELECT
+  sqlc.arg(owner),
+  sqlc.arg(from_account_id),
+  sqlc.arg(to_account_id),
+  sqlc.arg(amount),
+  sqlc.arg(frequency),
+  sqlc.arg(interval_count),
+  sqlc.arg(start_at),
+  sqlc.narg(end_at),
+  sqlc.arg(start_at)
+WHERE sqlc.arg(start_at)::timestamptz > now()
+RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetScheduledTransferForUpdate :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListScheduledTransfersByOwner :many
+SELECT * FROM scheduled_transfers
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: ClaimDueScheduledTransfer :one
+-- Rows locked by another replica are skipped, so each due
+-- schedule is picked by a single scheduler at a time.
+-- The time of the claim is returned for the next run to be
+-- computed with the same clock as the due runs
+SELECT sqlc.embed(scheduled_transfers), now()::timestamptz AS claimed_at FROM scheduled_transfers
+WHERE status = 'active' AND next_run_at <= now()
+ORDER BY next_run_at
+LIMIT 1
+FOR NO KEY UPDATE SKIP LOCKED;
+
+-- name: UpdateScheduledTransferSchedule :one
+UPDATE scheduled_transfers
+  set
+  status = sqlc.arg(status),
+  next_run_at = sqlc.arg(next_run_at)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateScheduledTransferRun :one
+INSERT INTO scheduled_transfer_runs (
+  scheduled_transfer_id,
+  scheduled_for,
+  transfer_id,
+  error
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListScheduledTransferRuns :many
+SELECT * FROM scheduled_transfer_runs
+WHERE scheduled_transfer_id = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
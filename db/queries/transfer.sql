@@ -2,6 +2,11 @@
 SELECT * FROM transfers
 WHERE id = $1 LIMIT 1;
 
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
 -- name: ListTransfers :many
 SELECT * FROM transfers
 ORDER BY id
@@ -12,8 +17,40 @@ OFFSET $2;
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  to_amount,
+  exchange_rate,
+  rate_timestamp,
+  reversal_of
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6, $7
 )
 RETURNING *;
+
+-- name: ListAccountTransfers :many
+SELECT * FROM transfers
+WHERE (
+    (sqlc.arg(include_outgoing)::bool AND from_account_id = sqlc.arg(account_id))
+    OR (sqlc.arg(include_incoming)::bool AND to_account_id = sqlc.arg(account_id))
+  )
+  AND (sqlc.narg(counterparty)::bigint IS NULL
+    OR from_account_id = sqlc.narg(counterparty) OR to_account_id = sqlc.narg(counterparty))
+  -- The amount range applies to the amount in the currency of the account,
+  -- which is the to_amount for incoming transfers
+  AND (sqlc.narg(min_amount)::decimal IS NULL
+    OR CASE WHEN from_account_id = sqlc.arg(account_id) THEN amount ELSE to_amount END >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::decimal IS NULL
+    OR CASE WHEN from_account_id = sqlc.arg(account_id) THEN amount ELSE to_amount END <= sqlc.narg(max_amount))
+ORDER BY id
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: SumTransferReversals :one
+-- Reversals are debited from the to account of the transfer they reverse,
+-- so their amounts are in the currency of its to_amount, and their to_amounts
+-- in the currency of its amount
+SELECT
+  COALESCE(SUM(amount), 0)::decimal AS total,
+  COALESCE(SUM(to_amount), 0)::decimal AS refunded
+FROM transfers
+WHERE reversal_of = $1;
@@ -25,6 +25,13 @@ UPDATE users
 WHERE name = $1
 RETURNING *;
 
+-- name: UpdateUserRole :one
+UPDATE users
+  set
+  role = $2
+WHERE name = $1
+RETURNING *;
+
 -- name: DeleteUser :exec
 DELETE FROM users
 WHERE name = $1;
\ No newline at end of file
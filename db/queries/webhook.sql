@@ -0,0 +1,71 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO webhook_endpoints (
+  owner,
+  url,
+  secret,
+  event_types
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetWebhookEndpoint :one
+SELECT * FROM webhook_endpoints
+WHERE id = $1 LIMIT 1;
+
+-- name: ListWebhookEndpointsByOwner :many
+SELECT * FROM webhook_endpoints
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: DisableWebhookEndpoint :one
+UPDATE webhook_endpoints
+  set is_active = false
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateWebhookDeliveries :exec
+-- Queues the event for the active endpoints of the given owners subscribed to its type
+INSERT INTO webhook_deliveries (
+  endpoint_id,
+  event_id
+)
+SELECT id, sqlc.arg(event_id)::bigint FROM webhook_endpoints
+WHERE is_active
+  AND owner = ANY(sqlc.arg(owners)::varchar[])
+  AND sqlc.arg(event_type)::varchar = ANY(event_types)
+ON CONFLICT DO NOTHING;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE endpoint_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: LeaseWebhookDeliveries :many
+-- The leased deliveries are hidden from the other dispatchers until leased_until
+UPDATE webhook_deliveries
+  set next_attempt_at = sqlc.arg(leased_until)
+WHERE id IN (
+  SELECT id FROM webhook_deliveries
+  WHERE status = 'pending' AND next_attempt_at <= now()
+  ORDER BY id
+  LIMIT sqlc.arg(limit_count)
+  FOR NO KEY UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateWebhookDeliveryAttempt :one
+UPDATE webhook_deliveries
+  set
+  status = sqlc.arg(status),
+  attempts = attempts + 1,
+  next_attempt_at = sqlc.arg(next_attempt_at),
+  last_status_code = sqlc.narg(last_status_code),
+  last_error = sqlc.narg(last_error),
+  delivered_at = sqlc.narg(delivered_at)
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// StatementCursor identifies the last entry of a statement page.
+// Entries are ordered by (created_at, id), so the next page starts right after it.
+type StatementCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+}
+
+// AccountStatementTxParams contains the input parameters of the account statement transaction
+type AccountStatementTxParams struct {
+	AccountID int64
+	From      time.Time
+	To        time.Time
+	// After is the cursor of the previous page, nil for the first page
+	After    *StatementCursor
+	PageSize int32
+}
+
+// StatementEntry is an entry of the statement along with the account balance right after it
+type StatementEntry struct {
+	Entry
+	RunningBalance decimal.Decimal `json:"runningBalance"`
+}
+
+// AccountStatementTxResult contains the out parameters of the account statement transaction
+type AccountStatementTxResult struct {
+	Account        Account
+	OpeningBalance decimal.Decimal
+	ClosingBalance decimal.Decimal
+	Entries        []StatementEntry
+	// Next is the cursor of the next page, nil when there is no more entries in the period
+	Next *StatementCursor
+}
+
+// AccountStatementTx returns one page of the entries of an account created within [From, To),
+// along with the running balance after each entry and the opening/closing balances of the period.
+// Balances are derived from the current account balance minus the entries created after a given point,
+// so all the queries run on the same snapshot.
+func (store *SqlStore) AccountStatementTx(
+	ctx context.Context,
+	arg AccountStatementTxParams) (AccountStatementTxResult, error) {
+
+	var result AccountStatementTxResult
+
+	// Stronger isolation levels configured on the store already provide a stable snapshot
+	isoLevel := store.isoLevel
+	if isoLevel != pgx.Serializable {
+		isoLevel = pgx.RepeatableRead
+	}
+
+	txOptions := pgx.TxOptions{
+		IsoLevel:   isoLevel,
+		AccessMode: pgx.ReadOnly,
+	}
+
+	txErr := store.execTxWithOptions(ctx, txOptions, func(q *Queries) error {
+		var err error
+		result = AccountStatementTxResult{}
+
+		result.Account, err = q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		periodStart := StatementCursor{CreatedAt: arg.From}
+		periodEnd := StatementCursor{CreatedAt: arg.To}
+
+		result.OpeningBalance, err = balanceAt(ctx, q, result.Account, periodStart)
+		if err != nil {
+			return err
+		}
+
+		result.ClosingBalance, err = balanceAt(ctx, q, result.Account, periodEnd)
+		if err != nil {
+			return err
+		}
+
+		pageStart := periodStart
+		if arg.After != nil {
+			pageStart = *arg.After
+		}
+
+		entries, err := q.ListAccountEntries(ctx, ListAccountEntriesParams{
+			AccountID:       arg.AccountID,
+			AfterCreatedAt:  pgtype.Timestamptz{Time: pageStart.CreatedAt, Valid: true},
+			AfterID:         pageStart.ID,
+			BeforeCreatedAt: pgtype.Timestamptz{Time: arg.To, Valid: true},
+			LimitCount:      arg.PageSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		runningBalance := result.OpeningBalance
+		if arg.After != nil {
+			runningBalance, err = balanceAt(ctx, q, result.Account, pageStart)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Entries = make([]StatementEntry, len(entries))
+		for i, entry := range entries {
+			runningBalance = runningBalance.Add(entry.Amount)
+			result.Entries[i] = StatementEntry{
+				Entry:          entry,
+				RunningBalance: runningBalance,
+			}
+		}
+
+		// A full page may be followed by more entries
+		if len(entries) > 0 && len(entries) == int(arg.PageSize) {
+			last := entries[len(entries)-1]
+			result.Next = &StatementCursor{
+				CreatedAt: last.CreatedAt.Time,
+				ID:        last.ID,
+			}
+		}
+
+		return nil
+	})
+
+	return result, txErr
+}
+
+// balanceAt returns the balance the account had right after the entry identified by cursor,
+// which is its current balance minus all the entries created after that one
+func balanceAt(ctx context.Context, q *Queries, account Account, cursor StatementCursor) (decimal.Decimal, error) {
+	total, err := q.SumAccountEntriesAfter(ctx, SumAccountEntriesAfterParams{
+		AccountID:      account.ID,
+		AfterCreatedAt: pgtype.Timestamptz{Time: cursor.CreatedAt, Valid: true},
+		AfterID:        cursor.ID,
+	})
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return account.Balance.Sub(total), nil
+}
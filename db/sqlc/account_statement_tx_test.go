@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountStatementTx(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	from := time.Now().Add(-time.Minute)
+
+	// Make n transfers from account1 to account2
+	n := 5
+	amountToTransfer := decimal.NewFromInt32(10)
+	for i := 0; i < n; i++ {
+		_, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountId: account1.ID,
+			ToAccountId:   account2.ID,
+			Amount:        amountToTransfer,
+		})
+		assert.NoError(t, err)
+	}
+
+	to := time.Now().Add(time.Minute)
+	expectedClosingBalance := account1.Balance.Sub(amountToTransfer.Mul(decimal.NewFromInt(int64(n))))
+
+	// Read the statement page by page
+	var after *StatementCursor
+	var entries []StatementEntry
+	for page := 0; page < n; page++ {
+		result, err := store.AccountStatementTx(context.Background(), AccountStatementTxParams{
+			AccountID: account1.ID,
+			From:      from,
+			To:        to,
+			After:     after,
+			PageSize:  2,
+		})
+		assert.NoError(t, err)
+
+		assert.True(t, account1.Balance.Equal(result.OpeningBalance))
+		assert.True(t, expectedClosingBalance.Equal(result.ClosingBalance))
+
+		entries = append(entries, result.Entries...)
+		if result.Next == nil {
+			break
+		}
+
+		after = result.Next
+	}
+
+	assert.Len(t, entries, n)
+
+	runningBalance := account1.Balance
+	for i, entry := range entries {
+		assert.Equal(t, account1.ID, entry.AccountID)
+		assert.True(t, amountToTransfer.Neg().Equal(entry.Amount))
+
+		runningBalance = runningBalance.Add(entry.Amount)
+		assert.True(t, runningBalance.Equal(entry.RunningBalance))
+
+		if i > 0 {
+			assert.False(t, entry.CreatedAt.Time.Before(entries[i-1].CreatedAt.Time))
+		}
+	}
+}
+
+func TestAccountStatementTxEmptyPeriod(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.NewFromInt32(10),
+	})
+	assert.NoError(t, err)
+
+	// The period ends before the transfer
+	result, err := store.AccountStatementTx(context.Background(), AccountStatementTxParams{
+		AccountID: account1.ID,
+		From:      time.Now().Add(-2 * time.Hour),
+		To:        time.Now().Add(-time.Hour),
+		PageSize:  10,
+	})
+	assert.NoError(t, err)
+
+	assert.Empty(t, result.Entries)
+	assert.Nil(t, result.Next)
+	assert.True(t, account1.Balance.Equal(result.OpeningBalance))
+	assert.True(t, account1.Balance.Equal(result.ClosingBalance))
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Errors returned when an operation needs an active account
+var (
+	ErrAccountFrozen = errors.New("account is frozen")
+	ErrAccountClosed = errors.New("account is closed")
+)
+
+// ErrAccountNotFrozen is returned when unfreezing an account which is not frozen
+var ErrAccountNotFrozen = errors.New("account is not frozen")
+
+// AccountNotActiveError describes an operation rejected because of the status of the account.
+// Reason is the reason of the freeze, when known.
+type AccountNotActiveError struct {
+	AccountID int64
+	Status    AccountStatus
+	Reason    string
+}
+
+func (e *AccountNotActiveError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("account [%d] is %s: %s", e.AccountID, e.Status, e.Reason)
+	}
+
+	return fmt.Sprintf("account [%d] is %s", e.AccountID, e.Status)
+}
+
+func (e *AccountNotActiveError) Unwrap() error {
+	if e.Status == AccountStatusClosed {
+		return ErrAccountClosed
+	}
+
+	return ErrAccountFrozen
+}
+
+// checkAccountActive returns an AccountNotActiveError unless the account is active
+func checkAccountActive(account Account) error {
+	if account.Status != AccountStatusActive {
+		return &AccountNotActiveError{
+			AccountID: account.ID,
+			Status:    account.Status,
+		}
+	}
+
+	return nil
+}
+
+// checkCanSend makes sure money can leave the account, which must be active
+func (store *SqlStore) checkCanSend(ctx context.Context, q *Queries, account Account) error {
+	if account.Status == AccountStatusFrozen {
+		return frozenAccountError(ctx, q, account)
+	}
+
+	return checkAccountActive(account)
+}
+
+// checkCanReceive makes sure money can enter the account. Frozen accounts
+// can receive money unless the store is configured otherwise.
+func (store *SqlStore) checkCanReceive(ctx context.Context, q *Queries, account Account) error {
+	if account.Status == AccountStatusFrozen {
+		if !store.frozenBlocksIncoming {
+			return nil
+		}
+
+		return frozenAccountError(ctx, q, account)
+	}
+
+	return checkAccountActive(account)
+}
+
+// frozenAccountError returns an AccountNotActiveError with the reason of the freeze in place
+func frozenAccountError(ctx context.Context, q *Queries, account Account) error {
+	freeze, err := q.GetActiveAccountFreeze(ctx, account.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	return &AccountNotActiveError{
+		AccountID: account.ID,
+		Status:    account.Status,
+		Reason:    freeze.Reason,
+	}
+}
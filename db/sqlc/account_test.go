@@ -7,18 +7,21 @@ import (
 	"time"
 
 	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
-	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 )
 
 func createRandomAccount(t *testing.T) Account {
+	return createRandomAccountWithCurrency(t, randomCurrency())
+}
+
+func createRandomAccountWithCurrency(t *testing.T, currency Currency) Account {
 
 	user := createRandomUser(t)
 
 	arg := CreateAccountParams{
 		Owner:    user.Name,
-		Balance:  utils.RandomDecimal(1, 1000),
-		Currency: randomCurrency(),
+		Balance:  utils.RandomDecimal(100, 1000),
+		Currency: currency,
 	}
 
 	account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -52,55 +55,57 @@ func TestGetAccount(t *testing.T) {
 	assert.WithinDuration(t, account1.CreatedAt.Time, account2.CreatedAt.Time, time.Second)
 }
 
-func TestUpdateAccount(t *testing.T) {
+func TestUpdateAccountOverdraftLimit(t *testing.T) {
 	account1 := createRandomAccount(t)
+	assert.True(t, account1.OverdraftLimit.IsZero())
 
-	arg := UpdateAccountParams{
-		ID:      account1.ID,
-		Balance: utils.RandomDecimal(10, 10000),
+	arg := UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: utils.RandomDecimal(10, 100),
 	}
 
-	account2, err := testQueries.UpdateAccount(context.Background(), arg)
+	account2, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), arg)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, account2)
 
 	assert.Equal(t, account1.ID, account2.ID)
-	assert.Equal(t, account1.Owner, account2.Owner)
-	assert.Equal(t, arg.Balance, account2.Balance)
-	assert.Equal(t, account1.Currency, account2.Currency)
-	assert.WithinDuration(t, account1.CreatedAt.Time, account2.CreatedAt.Time, time.Second)
+	assert.Equal(t, account1.Balance, account2.Balance)
+	assert.Equal(t, arg.OverdraftLimit, account2.OverdraftLimit)
 }
 
-func TestDeleteAccount(t *testing.T) {
+func TestUpdateAccountStatus(t *testing.T) {
 	account1 := createRandomAccount(t)
+	assert.Equal(t, AccountStatusActive, account1.Status)
 
-	err := testQueries.DeleteAccount(context.Background(), account1.ID)
+	account2, err := testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:     account1.ID,
+		Status: AccountStatusFrozen,
+	})
 	assert.NoError(t, err)
-
-	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
-	assert.Error(t, err)
-	assert.EqualError(t, err, pgx.ErrNoRows.Error())
-	assert.Empty(t, account2)
+	assert.Equal(t, account1.ID, account2.ID)
+	assert.Equal(t, AccountStatusFrozen, account2.Status)
+	assert.Equal(t, account1.Balance, account2.Balance)
 }
 
-func TestListAccounts(t *testing.T) {
+func TestListAccountsByOwner(t *testing.T) {
+	var lastAccount Account
 	for i := 0; i < 10; i++ {
-		createRandomAccount(t)
+		lastAccount = createRandomAccount(t)
 	}
 
-	arg := ListAccountsParams{
+	arg := ListAccountsByOwnerParams{
+		Owner:  lastAccount.Owner,
 		Limit:  5,
-		Offset: 5,
+		Offset: 0,
 	}
 
-	accounts, err := testQueries.ListAccounts(context.Background(), arg)
+	accounts, err := testQueries.ListAccountsByOwner(context.Background(), arg)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, accounts)
 
-	assert.Len(t, accounts, 5)
-
 	for _, account := range accounts {
 		assert.NotEmpty(t, account)
+		assert.Equal(t, lastAccount.Owner, account.Owner)
 	}
 }
 
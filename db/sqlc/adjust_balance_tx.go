@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// ErrZeroAdjustment is returned when a balance adjustment doesn't change the balance
+var ErrZeroAdjustment = errors.New("adjustment amount must not be zero")
+
+// AdjustBalanceTxParams contains the input parameters of the balance adjustment transaction.
+// Amount is in the currency of the account and is credited when positive, debited when negative.
+type AdjustBalanceTxParams struct {
+	AccountID  int64           `json:"account_id"`
+	Amount     decimal.Decimal `json:"amount"`
+	ReasonCode string          `json:"reason_code"`
+	Note       string          `json:"note"`
+	Operator   string          `json:"operator"`
+}
+
+// AdjustBalanceTxResult contains the out parameters of the balance adjustment transaction
+type AdjustBalanceTxResult struct {
+	Account    Account           `json:"account"`
+	Entry      Entry             `json:"entry"`
+	Adjustment BalanceAdjustment `json:"adjustment"`
+}
+
+// AdjustBalanceTx corrects the balance of an account outside of a transfer.
+// It locks the account and the system account of its currency, creates the entries
+// of both accounts and the audit record of the adjustment, and updates the balances,
+// so that the balance stays equal to the sum of entries and the entries of the currency
+// still net to zero. The overdraft limit is not enforced, the operator is trusted to know better.
+// The change of the account is recorded in the audit log.
+func (store *SqlStore) AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error) {
+	var result AdjustBalanceTxResult
+
+	if arg.Amount.IsZero() {
+		return result, ErrZeroAdjustment
+	}
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		systemAccount, err := getSystemAccountFor(ctx, q, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		account, _, err := lockAccounts(ctx, q, arg.AccountID, systemAccount.ID)
+		if err != nil {
+			return err
+		}
+
+		// Frozen accounts can still be corrected, closed ones are final
+		if account.Status == AccountStatusClosed {
+			return &AccountNotActiveError{
+				AccountID: account.ID,
+				Status:    account.Status,
+			}
+		}
+
+		currency, err := q.GetCurrency(ctx, account.Currency)
+		if err != nil {
+			return err
+		}
+
+		err = checkAmountPrecision(arg.Amount, currency)
+		if err != nil {
+			return err
+		}
+
+		result.Entry, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: arg.AccountID,
+			Amount:    arg.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		counterEntry, err := q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: systemAccount.ID,
+			Amount:    arg.Amount.Neg(),
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Adjustment, err = q.CreateBalanceAdjustment(ctx, CreateBalanceAdjustmentParams{
+			AccountID:      arg.AccountID,
+			EntryID:        result.Entry.ID,
+			Amount:         arg.Amount,
+			ReasonCode:     arg.ReasonCode,
+			Note:           arg.Note,
+			Operator:       arg.Operator,
+			CounterEntryID: pgtype.Int8{Int64: counterEntry.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Update balances in the same order as the accounts were locked
+		if arg.AccountID < systemAccount.ID {
+			result.Account, _, err = addAmount(ctx, q, arg.AccountID, arg.Amount, systemAccount.ID, arg.Amount.Neg())
+		} else {
+			_, result.Account, err = addAmount(ctx, q, systemAccount.ID, arg.Amount.Neg(), arg.AccountID, arg.Amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		return recordAccountAudit(ctx, q, AuditActionAdjustBalance, &account, result.Account)
+	})
+
+	return result, txErr
+}
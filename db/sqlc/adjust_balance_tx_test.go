@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func createRandomAdmin(t *testing.T) User {
+	user := createRandomUser(t)
+
+	admin, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Name: user.Name,
+		Role: utils.AdminRole,
+	})
+	assert.NoError(t, err)
+
+	return admin
+}
+
+func TestAdjustBalanceTx(t *testing.T) {
+	store := NewStore(connPool)
+
+	admin := createRandomAdmin(t)
+	account := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	systemAccount, err := store.GetSystemAccount(context.Background(), CurrencyUSD)
+	assert.NoError(t, err)
+
+	// Debit more than the balance, overdraft limit is not enforced
+	amount := account.Balance.Add(decimal.NewFromInt(5)).Neg()
+
+	result, err := store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  account.ID,
+		Amount:     amount,
+		ReasonCode: "write_off",
+		Note:       utils.RandomString(12),
+		Operator:   admin.Name,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, account.ID, result.Account.ID)
+	assert.True(t, decimal.NewFromInt(-5).Equal(result.Account.Balance))
+
+	assert.Equal(t, account.ID, result.Entry.AccountID)
+	assert.True(t, amount.Equal(result.Entry.Amount))
+
+	adjustment, err := store.GetBalanceAdjustment(context.Background(), result.Adjustment.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, account.ID, adjustment.AccountID)
+	assert.Equal(t, result.Entry.ID, adjustment.EntryID)
+	assert.True(t, amount.Equal(adjustment.Amount))
+	assert.Equal(t, "write_off", adjustment.ReasonCode)
+	assert.Equal(t, admin.Name, adjustment.Operator)
+	assert.NotZero(t, adjustment.CreatedAt)
+
+	adjustments, err := store.ListAccountBalanceAdjustments(context.Background(), ListAccountBalanceAdjustmentsParams{
+		AccountID: account.ID,
+		Limit:     5,
+		Offset:    0,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, adjustments, 1)
+
+	// The system account takes the other side of the adjustment
+	updatedSystemAccount, err := store.GetAccount(context.Background(), systemAccount.ID)
+	assert.NoError(t, err)
+	assert.True(t, systemAccount.Balance.Sub(amount).Equal(updatedSystemAccount.Balance))
+}
+
+func TestAdjustBalanceTxInvalidAmount(t *testing.T) {
+	store := NewStore(connPool)
+
+	admin := createRandomAdmin(t)
+	account := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	_, err := store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  account.ID,
+		Amount:     decimal.Zero,
+		ReasonCode: "correction",
+		Operator:   admin.Name,
+	})
+	assert.ErrorIs(t, err, ErrZeroAdjustment)
+
+	_, err = store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  account.ID,
+		Amount:     decimal.RequireFromString("0.001"),
+		ReasonCode: "correction",
+		Operator:   admin.Name,
+	})
+	assert.ErrorIs(t, err, ErrInvalidAmountPrecision)
+
+	systemAccount, err := store.GetSystemAccount(context.Background(), CurrencyUSD)
+	assert.NoError(t, err)
+
+	_, err = store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  systemAccount.ID,
+		Amount:     decimal.NewFromInt(1),
+		ReasonCode: "correction",
+		Operator:   admin.Name,
+	})
+	assert.ErrorIs(t, err, ErrSystemAccount)
+
+	_, err = store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  math.MaxInt64,
+		Amount:     decimal.NewFromInt(1),
+		ReasonCode: "correction",
+		Operator:   admin.Name,
+	})
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	// Nothing was written
+	updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, account.Balance, updatedAccount.Balance)
+}
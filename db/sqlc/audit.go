@@ -0,0 +1,329 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Types of the resources recorded in the audit log
+const (
+	AuditResourceUser     = "user"
+	AuditResourceAccount  = "account"
+	AuditResourceTransfer = "transfer"
+)
+
+// Actions recorded in the audit log
+const (
+	AuditActionCreate        = "create"
+	AuditActionClose         = "close"
+	AuditActionFreeze        = "freeze"
+	AuditActionUnfreeze      = "unfreeze"
+	AuditActionAdjustBalance = "adjust_balance"
+)
+
+const (
+	// systemActor is recorded for the changes made outside of an API request, eg: by the scheduler
+	systemActor = "system"
+
+	// anonymousActor is recorded for the changes made by an API request without authenticated user
+	anonymousActor = "anonymous"
+
+	auditChainBatchSize = 1000
+)
+
+// AuditInfo describes the API request a change is made by
+type AuditInfo struct {
+	Actor     string
+	RequestID string
+	ClientIP  string
+}
+
+type auditInfoKey struct{}
+
+// WithAuditInfo returns a copy of ctx carrying info, which is recorded
+// in the audit log together with the changes made with the context
+func WithAuditInfo(ctx context.Context, info AuditInfo) context.Context {
+	return context.WithValue(ctx, auditInfoKey{}, info)
+}
+
+// AuditInfoFromContext returns the audit info carried by ctx.
+// Changes made without audit info are made by the system.
+func AuditInfoFromContext(ctx context.Context) AuditInfo {
+	info, ok := ctx.Value(auditInfoKey{}).(AuditInfo)
+	if !ok {
+		return AuditInfo{Actor: systemActor}
+	}
+
+	if info.Actor == "" {
+		info.Actor = anonymousActor
+	}
+
+	return info
+}
+
+// recordAudit records an event with the JSON of the states of the resource before and after
+// the change, in the transaction of the change. before is nil when the resource is created.
+// The event is pending until ChainAuditEventsTx appends it to the audit log, so that
+// the transactions recording events don't wait for each other.
+func recordAudit(
+	ctx context.Context,
+	q *Queries,
+	action string,
+	resourceType string,
+	resourceID string,
+	before any,
+	after any) error {
+
+	beforeData, err := marshalAuditState(before)
+	if err != nil {
+		return err
+	}
+
+	afterData, err := marshalAuditState(after)
+	if err != nil {
+		return err
+	}
+
+	info := AuditInfoFromContext(ctx)
+	_, err = q.CreatePendingAuditEvent(ctx, CreatePendingAuditEventParams{
+		Actor:        info.Actor,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Before:       beforeData,
+		After:        afterData,
+		RequestID:    info.RequestID,
+		ClientIp:     info.ClientIP,
+		// Postgres keeps microseconds, the hash must be computed on what is stored
+		CreatedAt: pgtype.Timestamptz{Time: time.Now().UTC().Truncate(time.Microsecond), Valid: true},
+	})
+	return err
+}
+
+// ChainAuditEventsTx appends at most limit pending events to the audit log, each one chained
+// to the previous one, and returns how many were appended. The transaction is read committed
+// whatever the isolation level of the store, so that the last event is read after the chain is locked.
+func (store *SqlStore) ChainAuditEventsTx(ctx context.Context, limit int32) (int, error) {
+	var chained int
+
+	txOptions := pgx.TxOptions{IsoLevel: pgx.ReadCommitted}
+	txErr := store.execTxWithOptions(ctx, txOptions, func(q *Queries) error {
+		chained = 0
+
+		err := q.LockAuditChain(ctx)
+		if err != nil {
+			return err
+		}
+
+		var prevHash string
+		last, err := q.GetLastAuditEvent(ctx)
+		switch {
+		case err == nil:
+			prevHash = last.Hash
+		case !errors.Is(err, pgx.ErrNoRows):
+			return err
+		}
+
+		pendingEvents, err := q.ListPendingAuditEvents(ctx, limit)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]int64, 0, len(pendingEvents))
+		for _, pending := range pendingEvents {
+			event := AuditEvent{
+				Actor:        pending.Actor,
+				Action:       pending.Action,
+				ResourceType: pending.ResourceType,
+				ResourceID:   pending.ResourceID,
+				Before:       pending.Before,
+				After:        pending.After,
+				RequestID:    pending.RequestID,
+				ClientIp:     pending.ClientIp,
+				PrevHash:     prevHash,
+				CreatedAt:    pending.CreatedAt,
+			}
+			event.Hash = AuditEventHash(event)
+
+			_, err = q.CreateAuditEvent(ctx, CreateAuditEventParams{
+				Actor:        event.Actor,
+				Action:       event.Action,
+				ResourceType: event.ResourceType,
+				ResourceID:   event.ResourceID,
+				Before:       event.Before,
+				After:        event.After,
+				RequestID:    event.RequestID,
+				ClientIp:     event.ClientIp,
+				PrevHash:     event.PrevHash,
+				Hash:         event.Hash,
+				CreatedAt:    event.CreatedAt,
+			})
+			if err != nil {
+				return err
+			}
+
+			prevHash = event.Hash
+			ids = append(ids, pending.ID)
+		}
+
+		chained = len(ids)
+		return q.DeletePendingAuditEvents(ctx, ids)
+	})
+
+	return chained, txErr
+}
+
+func marshalAuditState(state any) ([]byte, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(state)
+}
+
+// AuditEventHash returns the hex encoded SHA-256 of the fields of event and the hash of the previous event.
+// The id and the hash of the event itself are not part of it.
+func AuditEventHash(event AuditEvent) string {
+	fields := []string{
+		event.PrevHash,
+		event.Actor,
+		event.Action,
+		event.ResourceType,
+		event.ResourceID,
+		string(event.Before),
+		string(event.After),
+		event.RequestID,
+		event.ClientIp,
+		event.CreatedAt.Time.UTC().Format(time.RFC3339Nano),
+	}
+
+	hash := sha256.New()
+	for _, field := range fields {
+		// The separator keeps adjacent fields from being shifted into each other
+		hash.Write([]byte(field))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// AuditChainResult contains the outcome of the verification of the audit log.
+// BrokenEventID is the first event which doesn't match the chain, when the chain is broken.
+type AuditChainResult struct {
+	Valid         bool   `json:"valid"`
+	Checked       int64  `json:"checked"`
+	BrokenEventID int64  `json:"broken_event_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// VerifyAuditChain walks the audit log in order and recomputes the hash of every event,
+// so that events changed, removed or inserted behind the application are detected
+func (store *SqlStore) VerifyAuditChain(ctx context.Context) (AuditChainResult, error) {
+	result := AuditChainResult{Valid: true}
+
+	var lastID int64
+	var prevHash string
+	for {
+		events, err := store.ListAuditEventsAfter(ctx, ListAuditEventsAfterParams{
+			ID:    lastID,
+			Limit: auditChainBatchSize,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		for _, event := range events {
+			switch {
+			case event.PrevHash != prevHash:
+				result.Valid = false
+				result.BrokenEventID = event.ID
+				result.Reason = "previous hash doesn't match the previous event"
+				return result, nil
+			case AuditEventHash(event) != event.Hash:
+				result.Valid = false
+				result.BrokenEventID = event.ID
+				result.Reason = "hash doesn't match the event"
+				return result, nil
+			}
+
+			result.Checked++
+			lastID = event.ID
+			prevHash = event.Hash
+		}
+
+		if len(events) < auditChainBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// userAuditState is the state of a user recorded in the audit log, without its password
+type userAuditState struct {
+	Name      string             `json:"name"`
+	Email     string             `json:"email"`
+	Role      string             `json:"role"`
+	CreatedAt pgtype.Timestamptz `json:"createdAt"`
+	UpdatedAt pgtype.Timestamptz `json:"updatedAt"`
+}
+
+func newUserAuditState(user User) userAuditState {
+	return userAuditState{
+		Name:      user.Name,
+		Email:     user.Email,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+// CreateUserTx creates a user and records its creation in the audit log
+func (store *SqlStore) CreateUserTx(ctx context.Context, arg CreateUserParams) (User, error) {
+	var result User
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateUser(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, AuditActionCreate, AuditResourceUser, result.Name, nil, newUserAuditState(result))
+	})
+
+	return result, txErr
+}
+
+// CreateAccountTx creates an account and records its creation in the audit log
+func (store *SqlStore) CreateAccountTx(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	var result Account
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = q.CreateAccount(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordAccountAudit(ctx, q, AuditActionCreate, nil, result)
+	})
+
+	return result, txErr
+}
+
+// recordAccountAudit records a change of account in the audit log. before is nil when the account is created.
+func recordAccountAudit(ctx context.Context, q *Queries, action string, before *Account, after Account) error {
+	var beforeState any
+	if before != nil {
+		beforeState = *before
+	}
+
+	return recordAudit(ctx, q, action, AuditResourceAccount, strconv.FormatInt(after.ID, 10), beforeState, after)
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// chainAuditEvents appends all the pending events to the audit log
+func chainAuditEvents(t *testing.T, store Store) {
+	for {
+		chained, err := store.ChainAuditEventsTx(context.Background(), 100)
+		assert.NoError(t, err)
+
+		if err != nil || chained < 100 {
+			return
+		}
+	}
+}
+
+func TestCreateAccountAuditEvent(t *testing.T) {
+	store := NewStore(connPool)
+	user := createRandomUser(t)
+
+	ctx := WithAuditInfo(context.Background(), AuditInfo{
+		Actor:     user.Name,
+		RequestID: "req-" + utils.RandomString(6),
+		ClientIP:  "10.0.0.1",
+	})
+
+	account, err := store.CreateAccountTx(ctx, CreateAccountParams{
+		Owner:    user.Name,
+		Balance:  decimal.Zero,
+		Currency: CurrencyUSD,
+	})
+	assert.NoError(t, err)
+
+	chainAuditEvents(t, store)
+
+	events, err := store.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		ResourceType: pgtype.Text{String: AuditResourceAccount, Valid: true},
+		ResourceID:   pgtype.Text{String: strconv.FormatInt(account.ID, 10), Valid: true},
+		LimitCount:   5,
+	})
+	assert.NoError(t, err)
+	if !assert.Len(t, events, 1) {
+		return
+	}
+
+	event := events[0]
+	assert.Equal(t, user.Name, event.Actor)
+	assert.Equal(t, AuditActionCreate, event.Action)
+	assert.Equal(t, "10.0.0.1", event.ClientIp)
+	assert.Nil(t, event.Before)
+	assert.Equal(t, AuditEventHash(event), event.Hash)
+
+	var after Account
+	err = json.Unmarshal(event.After, &after)
+	assert.NoError(t, err)
+	assert.Equal(t, account.ID, after.ID)
+}
+
+func TestTransferTxAuditEvent(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	// Without audit info, the change is made by the system
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.NewFromInt(10),
+	})
+	assert.NoError(t, err)
+
+	chainAuditEvents(t, store)
+
+	events, err := store.ListAuditEvents(context.Background(), ListAuditEventsParams{
+		ResourceType: pgtype.Text{String: AuditResourceTransfer, Valid: true},
+		ResourceID:   pgtype.Text{String: strconv.FormatInt(result.Transfer.ID, 10), Valid: true},
+		LimitCount:   5,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, systemActor, events[0].Actor)
+	}
+
+	chain, err := store.VerifyAuditChain(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, chain.Valid)
+	assert.NotZero(t, chain.Checked)
+}
+
+func TestAuditEventsAppendOnly(t *testing.T) {
+	store := NewStore(connPool)
+	user := createRandomUser(t)
+
+	_, err := store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    user.Name,
+		Balance:  decimal.Zero,
+		Currency: CurrencyEUR,
+	})
+	assert.NoError(t, err)
+
+	chainAuditEvents(t, store)
+
+	_, err = connPool.Exec(context.Background(), "UPDATE audit_events SET actor = 'someone'")
+	assert.Error(t, err)
+
+	_, err = connPool.Exec(context.Background(), "DELETE FROM audit_events")
+	assert.Error(t, err)
+
+	chain, err := store.VerifyAuditChain(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, chain.Valid)
+}
+
+func TestChainAuditEventsTxConcurrentTransfers(t *testing.T) {
+	// The transfers record their events without waiting for each other, even repeatable read
+	store := NewStore(connPool, WithIsoLevel(pgx.RepeatableRead))
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	n := 5
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        decimal.NewFromInt(1),
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		assert.NoError(t, <-errs)
+	}
+
+	chainAuditEvents(t, store)
+
+	pending, err := store.ListPendingAuditEvents(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+
+	chain, err := store.VerifyAuditChain(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, chain.Valid)
+}
+
+func TestAuditEventHash(t *testing.T) {
+	event := AuditEvent{
+		Actor:        "alice",
+		Action:       AuditActionCreate,
+		ResourceType: AuditResourceAccount,
+		ResourceID:   "1",
+		After:        []byte(`{"id":1}`),
+		PrevHash:     "abc",
+		CreatedAt:    pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+
+	hash := AuditEventHash(event)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, AuditEventHash(event))
+
+	// Any change of the event or of its place in the chain changes the hash
+	tampered := event
+	tampered.After = []byte(`{"id":2}`)
+	assert.NotEqual(t, hash, AuditEventHash(tampered))
+
+	tampered = event
+	tampered.PrevHash = "abd"
+	assert.NotEqual(t, hash, AuditEventHash(tampered))
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrSystemAccount is returned when a customer operation targets a system account
+var ErrSystemAccount = errors.New("system accounts cannot be used directly")
+
+// CashTxParams contains the input parameters of the deposit and withdrawal transactions.
+// Amount is in the currency of the account.
+type CashTxParams struct {
+	AccountID int64           `json:"account_id"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+// DepositTx credits the account with money entering the bank.
+// It is a transfer from the system account of the account currency,
+// so the entries of each currency always net to zero.
+func (store *SqlStore) DepositTx(ctx context.Context, arg CashTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		systemAccount, err := getSystemAccountFor(ctx, q, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		result, err = store.transfer(ctx, q, TransferTxParams{
+			FromAccountId: systemAccount.ID,
+			ToAccountId:   arg.AccountID,
+			Amount:        arg.Amount,
+		})
+		return err
+	})
+
+	return result, txErr
+}
+
+// WithdrawTx debits the account with money leaving the bank.
+// It is a transfer to the system account of the account currency,
+// and fails like a transfer when the account cannot cover the amount.
+func (store *SqlStore) WithdrawTx(ctx context.Context, arg CashTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		systemAccount, err := getSystemAccountFor(ctx, q, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		result, err = store.transfer(ctx, q, TransferTxParams{
+			FromAccountId: arg.AccountID,
+			ToAccountId:   systemAccount.ID,
+			Amount:        arg.Amount,
+		})
+		return err
+	})
+
+	return result, txErr
+}
+
+// getSystemAccountFor returns the system account of the currency of the given account,
+// which must be a customer account
+func getSystemAccountFor(ctx context.Context, q *Queries, accountID int64) (Account, error) {
+	account, err := q.GetAccount(ctx, accountID)
+	if err != nil {
+		return account, err
+	}
+
+	if account.IsSystem {
+		return account, ErrSystemAccount
+	}
+
+	return q.GetSystemAccount(ctx, account.Currency)
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDepositAndWithdrawTx(t *testing.T) {
+	store := NewStore(connPool)
+
+	account := createRandomAccountWithCurrency(t, CurrencyEUR)
+
+	systemAccount, err := store.GetSystemAccount(context.Background(), CurrencyEUR)
+	assert.NoError(t, err)
+	assert.True(t, systemAccount.IsSystem)
+	assert.Equal(t, CurrencyEUR, systemAccount.Currency)
+
+	// The system account has no funds but can always pay out a deposit
+	amount := decimal.RequireFromString("250.75")
+
+	deposit, err := store.DepositTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    amount,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, systemAccount.ID, deposit.Transfer.FromAccountID)
+	assert.Equal(t, account.ID, deposit.Transfer.ToAccountID)
+	assert.True(t, amount.Neg().Equal(deposit.FromEntry.Amount))
+	assert.True(t, amount.Equal(deposit.ToEntry.Amount))
+	assert.True(t, account.Balance.Add(amount).Equal(deposit.ToAccount.Balance))
+	assert.True(t, systemAccount.Balance.Sub(amount).Equal(deposit.FromAccount.Balance))
+
+	withdrawal, err := store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    amount,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, account.ID, withdrawal.Transfer.FromAccountID)
+	assert.Equal(t, systemAccount.ID, withdrawal.Transfer.ToAccountID)
+
+	// Both sides are back where they started
+	assert.True(t, account.Balance.Equal(withdrawal.FromAccount.Balance))
+	assert.True(t, systemAccount.Balance.Equal(withdrawal.ToAccount.Balance))
+}
+
+func TestDepositTxAddedCurrency(t *testing.T) {
+	store := NewStore(connPool)
+
+	// A currency added after the migrations gets its system account
+	currency := createRandomCurrency(t)
+
+	systemAccount, err := store.GetSystemAccount(context.Background(), currency)
+	assert.NoError(t, err)
+	assert.True(t, systemAccount.IsSystem)
+	assert.True(t, systemAccount.Balance.IsZero())
+
+	account := createRandomAccountWithCurrency(t, currency)
+	amount := decimal.RequireFromString("10.50")
+
+	deposit, err := store.DepositTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    amount,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, systemAccount.ID, deposit.Transfer.FromAccountID)
+	assert.True(t, account.Balance.Add(amount).Equal(deposit.ToAccount.Balance))
+	assert.True(t, amount.Neg().Equal(deposit.FromAccount.Balance))
+}
+
+func TestWithdrawTxInsufficientFunds(t *testing.T) {
+	store := NewStore(connPool)
+
+	account := createRandomAccountWithCurrency(t, CurrencyEUR)
+
+	_, err := store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: account.ID,
+		Amount:    account.Balance.Add(decimal.NewFromInt(1)),
+	})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	updatedAccount, err := testQueries.GetAccount(context.Background(), account.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, account.Balance, updatedAccount.Balance)
+}
+
+func TestCashTxInvalidAccount(t *testing.T) {
+	store := NewStore(connPool)
+
+	systemAccount, err := store.GetSystemAccount(context.Background(), CurrencyEUR)
+	assert.NoError(t, err)
+
+	_, err = store.DepositTx(context.Background(), CashTxParams{
+		AccountID: systemAccount.ID,
+		Amount:    decimal.NewFromInt(1),
+	})
+	assert.ErrorIs(t, err, ErrSystemAccount)
+
+	_, err = store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: math.MaxInt64,
+		Amount:    decimal.NewFromInt(1),
+	})
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrNonZeroBalance is returned when closing an account which still holds or owes money
+var ErrNonZeroBalance = errors.New("account balance is not zero")
+
+// NonZeroBalanceError describes an account which cannot be closed because of its balance
+type NonZeroBalanceError struct {
+	AccountID int64
+	Balance   decimal.Decimal
+}
+
+func (e *NonZeroBalanceError) Error() string {
+	return fmt.Sprintf("account [%d] balance must be zero to be closed, balance: %s", e.AccountID, e.Balance)
+}
+
+func (e *NonZeroBalanceError) Unwrap() error {
+	return ErrNonZeroBalance
+}
+
+// ErrActiveHolds is returned when closing an account with money reserved by active holds
+var ErrActiveHolds = errors.New("account has active holds")
+
+// ActiveHoldsError describes an account which cannot be closed because of its active holds
+type ActiveHoldsError struct {
+	AccountID  int64
+	HeldAmount decimal.Decimal
+}
+
+func (e *ActiveHoldsError) Error() string {
+	return fmt.Sprintf("account [%d] must have no active holds to be closed, held amount: %s", e.AccountID, e.HeldAmount)
+}
+
+func (e *ActiveHoldsError) Unwrap() error {
+	return ErrActiveHolds
+}
+
+// CloseAccountTx closes an active account with a zero balance and no active holds.
+// The account is locked, so no transfer or hold can change them in the meantime.
+// Its entries and transfers are kept, and stay readable, and an account closed event is recorded in the outbox.
+// The closure is recorded in the audit log.
+func (store *SqlStore) CloseAccountTx(ctx context.Context, accountID int64) (Account, error) {
+	var result Account
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, accountID)
+		if err != nil {
+			return err
+		}
+
+		if account.IsSystem {
+			return ErrSystemAccount
+		}
+
+		err = checkAccountActive(account)
+		if err != nil {
+			return err
+		}
+
+		if !account.Balance.IsZero() {
+			return &NonZeroBalanceError{
+				AccountID: account.ID,
+				Balance:   account.Balance,
+			}
+		}
+
+		// The holds must be captured or released first
+		heldAmount, err := q.GetAccountHeldAmount(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+
+		if !heldAmount.IsZero() {
+			return &ActiveHoldsError{
+				AccountID:  account.ID,
+				HeldAmount: heldAmount,
+			}
+		}
+
+		result, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:     accountID,
+			Status: AccountStatusClosed,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = recordEvent(ctx, q, EventAccountClosed, result.ID, result, result.Owner)
+		if err != nil {
+			return err
+		}
+
+		return recordAccountAudit(ctx, q, AuditActionClose, &account, result)
+	})
+
+	return result, txErr
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloseAccountTx(t *testing.T) {
+	store := NewStore(connPool)
+
+	account := createRandomAccountWithCurrency(t, CurrencyUSD)
+	other := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	// The balance must be zero
+	_, err := store.CloseAccountTx(context.Background(), account.ID)
+	assert.ErrorIs(t, err, ErrNonZeroBalance)
+
+	var nonZeroBalanceErr *NonZeroBalanceError
+	if assert.ErrorAs(t, err, &nonZeroBalanceErr) {
+		assert.True(t, account.Balance.Equal(nonZeroBalanceErr.Balance))
+	}
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account.ID,
+		ToAccountId:   other.ID,
+		Amount:        account.Balance,
+	})
+	assert.NoError(t, err)
+
+	closedAccount, err := store.CloseAccountTx(context.Background(), account.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, AccountStatusClosed, closedAccount.Status)
+	assert.True(t, closedAccount.Balance.IsZero())
+
+	// Closing twice fails
+	_, err = store.CloseAccountTx(context.Background(), account.ID)
+	assert.ErrorIs(t, err, ErrAccountClosed)
+
+	// No money in or out of a closed account
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: other.ID,
+		ToAccountId:   account.ID,
+		Amount:        decimal.NewFromInt(1),
+	})
+	assert.ErrorIs(t, err, ErrAccountClosed)
+
+	var notActiveErr *AccountNotActiveError
+	if assert.ErrorAs(t, err, &notActiveErr) {
+		assert.Equal(t, account.ID, notActiveErr.AccountID)
+		assert.Equal(t, AccountStatusClosed, notActiveErr.Status)
+	}
+
+	// History stays readable
+	transfers, err := store.ListAccountTransfers(context.Background(), ListAccountTransfersParams{
+		AccountID:       account.ID,
+		IncludeOutgoing: true,
+		IncludeIncoming: true,
+		LimitCount:      10,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 1)
+
+	// The owner can open a new account in the same currency
+	_, err = store.CreateAccountTx(context.Background(), CreateAccountParams{
+		Owner:    account.Owner,
+		Balance:  decimal.NewFromInt(0),
+		Currency: account.Currency,
+	})
+	assert.NoError(t, err)
+}
+
+func TestTransferTxFrozenAccount(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	_, err := store.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:     account1.ID,
+		Status: AccountStatusFrozen,
+	})
+	assert.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.NewFromInt(1),
+	})
+	assert.ErrorIs(t, err, ErrAccountFrozen)
+
+	_, err = store.CloseAccountTx(context.Background(), account1.ID)
+	assert.ErrorIs(t, err, ErrAccountFrozen)
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, account1.Balance, updatedAccount1.Balance)
+	assert.Equal(t, AccountStatusFrozen, updatedAccount1.Status)
+}
+
+func TestCloseAccountTxActiveHolds(t *testing.T) {
+	store := NewStore(connPool)
+
+	account := createRandomAccountWithCurrency(t, CurrencyUSD)
+	other := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account.ID,
+		ToAccountId:   other.ID,
+		Amount:        account.Balance,
+	})
+	assert.NoError(t, err)
+
+	_, err = store.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account.ID,
+		OverdraftLimit: decimal.NewFromInt(10),
+	})
+	assert.NoError(t, err)
+
+	// The balance is zero, but money is still reserved
+	hold, err := store.AuthorizeHold(context.Background(), AuthorizeHoldParams{
+		AccountID: account.ID,
+		Amount:    decimal.NewFromInt(5),
+	})
+	assert.NoError(t, err)
+
+	_, err = store.CloseAccountTx(context.Background(), account.ID)
+	assert.ErrorIs(t, err, ErrActiveHolds)
+
+	var activeHoldsErr *ActiveHoldsError
+	if assert.ErrorAs(t, err, &activeHoldsErr) {
+		assert.True(t, decimal.NewFromInt(5).Equal(activeHoldsErr.HeldAmount))
+	}
+
+	_, err = store.ReleaseHold(context.Background(), hold.ID)
+	assert.NoError(t, err)
+
+	closedAccount, err := store.CloseAccountTx(context.Background(), account.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, AccountStatusClosed, closedAccount.Status)
+}
@@ -0,0 +1,12 @@
+package db
+
+// Currency is an ISO 4217 alphabetic currency code.
+// The supported currencies and their minor units are stored in the currencies table.
+type Currency string
+
+// Currencies seeded by the migrations
+const (
+	CurrencyUSD Currency = "USD"
+	CurrencyEUR Currency = "EUR"
+	CurrencyINR Currency = "INR"
+)
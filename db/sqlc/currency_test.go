@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// createRandomCurrency adds a currency with a random code to the catalogue
+func createRandomCurrency(t *testing.T) Currency {
+	for {
+		code := Currency(strings.ToUpper(utils.RandomString(3)))
+
+		tag, err := connPool.Exec(context.Background(),
+			`INSERT INTO currencies (code, exponent, symbol) VALUES ($1, 2, $1) ON CONFLICT DO NOTHING`,
+			code)
+		assert.NoError(t, err)
+
+		if tag.RowsAffected() == 1 {
+			return code
+		}
+	}
+}
+
+func TestGetCurrency(t *testing.T) {
+	currency, err := testQueries.GetCurrency(context.Background(), CurrencyUSD)
+	assert.NoError(t, err)
+
+	assert.Equal(t, CurrencyUSD, currency.Code)
+	assert.Equal(t, int16(2), currency.Exponent)
+	assert.Equal(t, "$", currency.Symbol)
+	assert.True(t, currency.Enabled)
+}
+
+func TestListCurrencies(t *testing.T) {
+	currencies, err := testQueries.ListCurrencies(context.Background())
+	assert.NoError(t, err)
+
+	codes := make([]Currency, len(currencies))
+	for i, currency := range currencies {
+		codes[i] = currency.Code
+	}
+
+	assert.Contains(t, codes, CurrencyEUR)
+	assert.Contains(t, codes, CurrencyINR)
+	assert.Contains(t, codes, CurrencyUSD)
+}
@@ -8,38 +8,42 @@ package db
 import (
 	"context"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  transfer_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3
 )
-RETURNING id, account_id, amount, created_at
+RETURNING id, account_id, amount, created_at, transfer_id
 `
 
 type CreateEntryParams struct {
-	AccountID int64           `json:"accountId"`
-	Amount    decimal.Decimal `json:"amount"`
+	AccountID  int64           `json:"accountId"`
+	Amount     decimal.Decimal `json:"amount"`
+	TransferID pgtype.Int8     `json:"transferId"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRow(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createEntry, arg.AccountID, arg.Amount, arg.TransferID)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
 WHERE id = $1 LIMIT 1
 `
 
@@ -51,12 +55,62 @@ func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
+const listAccountEntries = `-- name: ListAccountEntries :many
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
+WHERE account_id = $1
+  AND (created_at, id) > ($2::timestamptz, $3::bigint)
+  AND created_at < $4::timestamptz
+ORDER BY created_at, id
+LIMIT $5
+`
+
+type ListAccountEntriesParams struct {
+	AccountID       int64              `json:"accountId"`
+	AfterCreatedAt  pgtype.Timestamptz `json:"afterCreatedAt"`
+	AfterID         int64              `json:"afterId"`
+	BeforeCreatedAt pgtype.Timestamptz `json:"beforeCreatedAt"`
+	LimitCount      int32              `json:"limitCount"`
+}
+
+func (q *Queries) ListAccountEntries(ctx context.Context, arg ListAccountEntriesParams) ([]Entry, error) {
+	rows, err := q.db.Query(ctx, listAccountEntries,
+		arg.AccountID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.BeforeCreatedAt,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -81,6 +135,7 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
 		); err != nil {
 			return nil, err
 		}
@@ -91,3 +146,34 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 	}
 	return items, nil
 }
+
+const sumAccountEntries = `-- name: SumAccountEntries :one
+SELECT COALESCE(SUM(amount), 0)::decimal AS total FROM entries
+WHERE account_id = $1
+`
+
+func (q *Queries) SumAccountEntries(ctx context.Context, accountID int64) (decimal.Decimal, error) {
+	row := q.db.QueryRow(ctx, sumAccountEntries, accountID)
+	var total decimal.Decimal
+	err := row.Scan(&total)
+	return total, err
+}
+
+const sumAccountEntriesAfter = `-- name: SumAccountEntriesAfter :one
+SELECT COALESCE(SUM(amount), 0)::decimal AS total FROM entries
+WHERE account_id = $1
+  AND (created_at, id) > ($2::timestamptz, $3::bigint)
+`
+
+type SumAccountEntriesAfterParams struct {
+	AccountID      int64              `json:"accountId"`
+	AfterCreatedAt pgtype.Timestamptz `json:"afterCreatedAt"`
+	AfterID        int64              `json:"afterId"`
+}
+
+func (q *Queries) SumAccountEntriesAfter(ctx context.Context, arg SumAccountEntriesAfterParams) (decimal.Decimal, error) {
+	row := q.db.QueryRow(ctx, sumAccountEntriesAfter, arg.AccountID, arg.AfterCreatedAt, arg.AfterID)
+	var total decimal.Decimal
+	err := row.Scan(&total)
+	return total, err
+}
@@ -2,21 +2,70 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	defaultMaxTxRetries = 3
+	txRetryBaseDelay    = 10 * time.Millisecond
+
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
+	uniqueViolationCode      = "23505"
+
+	// auditChainConstraint is violated when two events are chained to the same one,
+	// the transaction chained to a stale last event and can chain to the new one
+	auditChainConstraint = "audit_events_prev_hash_key"
 )
 
-// execTx executes operations within a single transaction
+// execTx executes operations within a single transaction.
+// All the queries of fn run on the transaction, and the whole transaction
+// is retried when it fails because of a serialization failure or a deadlock.
 func (store *SqlStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+	txOptions := pgx.TxOptions{
+		IsoLevel: store.isoLevel,
+	}
+
+	return store.execTxWithOptions(ctx, txOptions, fn)
+}
 
-	tx, err := store.connPool.Begin(ctx)
+// execTxWithOptions is like execTx but begins the transaction with the given options
+func (store *SqlStore) execTxWithOptions(ctx context.Context, txOptions pgx.TxOptions, fn func(*Queries) error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = store.runTx(ctx, txOptions, fn)
+		if err == nil || !isRetryableTxError(err) || attempt >= store.maxTxRetries {
+			return err
+		}
+
+		// Back off a little before retrying so that the conflicting
+		// transaction has a chance to complete
+		delay := txRetryBaseDelay * time.Duration(1<<attempt)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// runTx executes fn within a single transaction, committing it on success
+// and rolling it back on failure
+func (store *SqlStore) runTx(ctx context.Context, txOptions pgx.TxOptions, fn func(*Queries) error) error {
+	tx, err := store.connPool.BeginTx(ctx, txOptions)
 	if err != nil {
 		return err
 	}
 
-	err = fn(store.Queries)
+	err = fn(store.Queries.WithTx(tx))
 	if err != nil {
 		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
-			return fmt.Errorf("Tx err: %v, Rollback err: %v", err, rollbackErr)
+			return fmt.Errorf("Tx err: %w, Rollback err: %v", err, rollbackErr)
 		}
 
 		return err
@@ -24,3 +73,46 @@ func (store *SqlStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 
 	return tx.Commit(ctx)
 }
+
+// isRetryableTxError reports whether the transaction failed because of a serialization
+// failure, a deadlock or a fork of the audit chain, in which case it is safe to retry it
+func isRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	switch pgErr.Code {
+	case serializationFailureCode, deadlockDetectedCode:
+		return true
+	case uniqueViolationCode:
+		return pgErr.ConstraintName == auditChainConstraint
+	default:
+		return false
+	}
+}
+
+// execSavepoint runs fn within a savepoint of the transaction q is bound to.
+// When fn fails, only its own queries are rolled back and the transaction can go on.
+func execSavepoint(ctx context.Context, q *Queries, fn func(*Queries) error) error {
+	tx, ok := q.db.(pgx.Tx)
+	if !ok {
+		return errors.New("savepoint requires a transaction")
+	}
+
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = fn(q.WithTx(savepoint))
+	if err != nil {
+		if rollbackErr := savepoint.Rollback(ctx); rollbackErr != nil {
+			return fmt.Errorf("Savepoint err: %w, Rollback err: %v", err, rollbackErr)
+		}
+
+		return err
+	}
+
+	return savepoint.Commit(ctx)
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgproto3"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecTxRollbackLeavesNoPartialRows(t *testing.T) {
+	store := NewStore(connPool).(*SqlStore)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	amountToTransfer := decimal.NewFromInt32(10)
+	errMidTransfer := errors.New("failure in the middle of the transfer")
+
+	// Run n failing transfers concurrently, each one failing
+	// after some of its rows have already been written
+	n := 5
+	errs := make(chan error)
+	transferIds := make(chan int64)
+	entryIds := make(chan int64)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			var transferId, entryId int64
+
+			err := store.execTx(context.Background(), func(q *Queries) error {
+				transfer, err := q.CreateTransfer(context.Background(), CreateTransferParams{
+					FromAccountID: account1.ID,
+					ToAccountID:   account2.ID,
+					Amount:        amountToTransfer,
+					ToAmount:      amountToTransfer,
+					ExchangeRate:  decimal.NewFromInt(1),
+					RateTimestamp: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+				})
+				if err != nil {
+					return err
+				}
+				transferId = transfer.ID
+
+				entry, err := q.CreateEntry(context.Background(), CreateEntryParams{
+					AccountID: account1.ID,
+					Amount:    amountToTransfer.Neg(),
+				})
+				if err != nil {
+					return err
+				}
+				entryId = entry.ID
+
+				_, err = q.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+					ID:     account1.ID,
+					Amount: amountToTransfer.Neg(),
+				})
+				if err != nil {
+					return err
+				}
+
+				return errMidTransfer
+			})
+
+			errs <- err
+			transferIds <- transferId
+			entryIds <- entryId
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+		assert.ErrorIs(t, err, errMidTransfer)
+
+		transferId := <-transferIds
+		assert.NotZero(t, transferId)
+
+		entryId := <-entryIds
+		assert.NotZero(t, entryId)
+
+		// Rows written before the failure must have been rolled back
+		_, err = testQueries.GetTransfer(context.Background(), transferId)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+		_, err = testQueries.GetEntry(context.Background(), entryId)
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	}
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, account2.Balance, updatedAccount2.Balance)
+}
+
+func TestTransferTxSerializable(t *testing.T) {
+	store := NewStore(connPool, WithIsoLevel(pgx.Serializable), WithMaxTxRetries(10))
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+	fmt.Println(">> before:", account1.Balance, account2.Balance)
+
+	amountToTransfer := decimal.NewFromInt32(1)
+
+	// Concurrent transfers on the same accounts end up in serialization
+	// failures, which must be retried transparently by the store
+	n := 5
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        amountToTransfer,
+			})
+
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+		assert.NoError(t, err)
+	}
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	assert.NoError(t, err)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	assert.NoError(t, err)
+
+	fmt.Println(">> after:", updatedAccount1.Balance, updatedAccount2.Balance)
+	expectedTotalTransferredAmount := amountToTransfer.Mul(decimal.NewFromInt(int64(n)))
+
+	assert.Equal(t, expectedTotalTransferredAmount, account1.Balance.Sub(updatedAccount1.Balance))
+	assert.Equal(t, expectedTotalTransferredAmount, updatedAccount2.Balance.Sub(account2.Balance))
+}
+
+func TestIsRetryableTxError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "SerializationFailure",
+			err:      pgconn.ErrorResponseToPgError(&pgproto3.ErrorResponse{Code: serializationFailureCode}),
+			expected: true,
+		},
+		{
+			name:     "DeadlockDetected",
+			err:      pgconn.ErrorResponseToPgError(&pgproto3.ErrorResponse{Code: deadlockDetectedCode}),
+			expected: true,
+		},
+		{
+			name:     "WrappedSerializationFailure",
+			err:      fmt.Errorf("Tx err: %w", pgconn.ErrorResponseToPgError(&pgproto3.ErrorResponse{Code: serializationFailureCode})),
+			expected: true,
+		},
+		{
+			name:     "UniqueViolation",
+			err:      pgconn.ErrorResponseToPgError(&pgproto3.ErrorResponse{Code: "23505"}),
+			expected: false,
+		},
+		{
+			name:     "AuditChainFork",
+			err:      pgconn.ErrorResponseToPgError(&pgproto3.ErrorResponse{Code: uniqueViolationCode, ConstraintName: auditChainConstraint}),
+			expected: true,
+		},
+		{
+			name:     "NoRows",
+			err:      pgx.ErrNoRows,
+			expected: false,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isRetryableTxError(tc.err))
+		})
+	}
+}
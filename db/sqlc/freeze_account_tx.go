@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// FreezeAccountTxParams contains the input parameters of the freeze account transaction
+type FreezeAccountTxParams struct {
+	AccountID int64  `json:"account_id"`
+	Reason    string `json:"reason"`
+	Operator  string `json:"operator"`
+}
+
+// UnfreezeAccountTxParams contains the input parameters of the unfreeze account transaction
+type UnfreezeAccountTxParams struct {
+	AccountID int64  `json:"account_id"`
+	Reason    string `json:"reason"`
+	Operator  string `json:"operator"`
+}
+
+// AccountFreezeTxResult contains the out parameters of the freeze and unfreeze account transactions
+type AccountFreezeTxResult struct {
+	Account Account       `json:"account"`
+	Freeze  AccountFreeze `json:"freeze"`
+}
+
+// FreezeAccountTx freezes an active account and records who froze it and why.
+// The account is locked, so transfers in flight complete before the freeze.
+// The freeze is recorded in the audit log.
+func (store *SqlStore) FreezeAccountTx(ctx context.Context, arg FreezeAccountTxParams) (AccountFreezeTxResult, error) {
+	var result AccountFreezeTxResult
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if account.IsSystem {
+			return ErrSystemAccount
+		}
+
+		err = checkAccountActive(account)
+		if err != nil {
+			return err
+		}
+
+		result.Account, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:     arg.AccountID,
+			Status: AccountStatusFrozen,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Freeze, err = q.CreateAccountFreeze(ctx, CreateAccountFreezeParams{
+			AccountID: arg.AccountID,
+			Reason:    arg.Reason,
+			FrozenBy:  arg.Operator,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAccountAudit(ctx, q, AuditActionFreeze, &account, result.Account)
+	})
+
+	return result, txErr
+}
+
+// UnfreezeAccountTx makes a frozen account active again and records who released it and why,
+// and records the release in the audit log
+func (store *SqlStore) UnfreezeAccountTx(ctx context.Context, arg UnfreezeAccountTxParams) (AccountFreezeTxResult, error) {
+	var result AccountFreezeTxResult
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if account.Status != AccountStatusFrozen {
+			return ErrAccountNotFrozen
+		}
+
+		result.Account, err = q.UpdateAccountStatus(ctx, UpdateAccountStatusParams{
+			ID:     arg.AccountID,
+			Status: AccountStatusActive,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Freeze, err = q.ReleaseAccountFreeze(ctx, ReleaseAccountFreezeParams{
+			AccountID:      arg.AccountID,
+			UnfreezeReason: pgtype.Text{String: arg.Reason, Valid: true},
+			UnfrozenBy:     pgtype.Text{String: arg.Operator, Valid: true},
+		})
+		// The status may have been changed without freeze record, there is nothing to release then
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		return recordAccountAudit(ctx, q, AuditActionUnfreeze, &account, result.Account)
+	})
+
+	return result, txErr
+}
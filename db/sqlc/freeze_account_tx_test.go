@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreezeAccountTx(t *testing.T) {
+	store := NewStore(connPool)
+
+	admin := createRandomAdmin(t)
+	account := createRandomAccountWithCurrency(t, CurrencyUSD)
+	other := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	result, err := store.FreezeAccountTx(context.Background(), FreezeAccountTxParams{
+		AccountID: account.ID,
+		Reason:    "court order",
+		Operator:  admin.Name,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, AccountStatusFrozen, result.Account.Status)
+	assert.Equal(t, account.ID, result.Freeze.AccountID)
+	assert.Equal(t, "court order", result.Freeze.Reason)
+	assert.Equal(t, admin.Name, result.Freeze.FrozenBy)
+	assert.False(t, result.Freeze.UnfrozenAt.Valid)
+
+	// Freezing twice fails
+	_, err = store.FreezeAccountTx(context.Background(), FreezeAccountTxParams{
+		AccountID: account.ID,
+		Reason:    "court order",
+		Operator:  admin.Name,
+	})
+	assert.ErrorIs(t, err, ErrAccountFrozen)
+
+	// Outgoing transfers are blocked with the freeze reason
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account.ID,
+		ToAccountId:   other.ID,
+		Amount:        decimal.NewFromInt(1),
+	})
+	assert.ErrorIs(t, err, ErrAccountFrozen)
+
+	var notActiveErr *AccountNotActiveError
+	if assert.ErrorAs(t, err, &notActiveErr) {
+		assert.Equal(t, account.ID, notActiveErr.AccountID)
+		assert.Equal(t, "court order", notActiveErr.Reason)
+	}
+
+	// Incoming transfers are allowed by default
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: other.ID,
+		ToAccountId:   account.ID,
+		Amount:        decimal.NewFromInt(1),
+	})
+	assert.NoError(t, err)
+
+	// ... unless the store is configured to block them
+	strictStore := NewStore(connPool, WithFrozenAccountsBlockIncoming(true))
+	_, err = strictStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: other.ID,
+		ToAccountId:   account.ID,
+		Amount:        decimal.NewFromInt(1),
+	})
+	assert.ErrorIs(t, err, ErrAccountFrozen)
+
+	unfrozen, err := store.UnfreezeAccountTx(context.Background(), UnfreezeAccountTxParams{
+		AccountID: account.ID,
+		Reason:    "order lifted",
+		Operator:  admin.Name,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, AccountStatusActive, unfrozen.Account.Status)
+	assert.Equal(t, result.Freeze.ID, unfrozen.Freeze.ID)
+	assert.Equal(t, "order lifted", unfrozen.Freeze.UnfreezeReason.String)
+	assert.Equal(t, admin.Name, unfrozen.Freeze.UnfrozenBy.String)
+	assert.True(t, unfrozen.Freeze.UnfrozenAt.Valid)
+
+	// Unfreezing twice fails
+	_, err = store.UnfreezeAccountTx(context.Background(), UnfreezeAccountTxParams{
+		AccountID: account.ID,
+		Reason:    "order lifted",
+		Operator:  admin.Name,
+	})
+	assert.ErrorIs(t, err, ErrAccountNotFrozen)
+
+	// Money flows again
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account.ID,
+		ToAccountId:   other.ID,
+		Amount:        decimal.NewFromInt(1),
+	})
+	assert.NoError(t, err)
+
+	freezes, err := store.ListAccountFreezes(context.Background(), ListAccountFreezesParams{
+		AccountID: account.ID,
+		Limit:     10,
+		Offset:    0,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, freezes, 1)
+}
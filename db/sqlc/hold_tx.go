@@ -0,0 +1,216 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultHoldDuration is how long a hold reserves money when no expiry is given
+const DefaultHoldDuration = 7 * 24 * time.Hour
+
+var (
+	// ErrInvalidHoldAmount is returned when a hold is authorized for a non positive amount
+	ErrInvalidHoldAmount = errors.New("hold amount must be positive")
+
+	// ErrInvalidCaptureAmount is returned when a capture is negative or exceeds the held amount
+	ErrInvalidCaptureAmount = errors.New("capture amount must be positive and at most the held amount")
+)
+
+var (
+	// ErrHoldNotActive is returned when a hold was already captured or released
+	ErrHoldNotActive = errors.New("hold is not active")
+
+	// ErrHoldExpired is returned when a hold expired before being captured
+	ErrHoldExpired = errors.New("hold is expired")
+)
+
+// HoldNotActiveError describes an operation rejected because the hold is already settled.
+// It unwraps to ErrHoldExpired for expired holds and to ErrHoldNotActive otherwise.
+type HoldNotActiveError struct {
+	HoldID int64
+	Status HoldStatus
+}
+
+func (e *HoldNotActiveError) Error() string {
+	return fmt.Sprintf("hold [%d] is %s", e.HoldID, e.Status)
+}
+
+func (e *HoldNotActiveError) Unwrap() error {
+	if e.Status == HoldStatusExpired {
+		return ErrHoldExpired
+	}
+
+	return ErrHoldNotActive
+}
+
+// AuthorizeHoldParams contains the input parameters of the authorize hold transaction.
+// Amount is in the currency of the account. A zero ExpiresAt defaults to DefaultHoldDuration from now.
+type AuthorizeHoldParams struct {
+	AccountID int64           `json:"account_id"`
+	Amount    decimal.Decimal `json:"amount"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// CaptureHoldParams contains the input parameters of the capture hold transaction.
+// A zero Amount captures the full hold, a lower amount captures it partially
+// and the remainder is released.
+type CaptureHoldParams struct {
+	HoldID      int64           `json:"hold_id"`
+	ToAccountID int64           `json:"to_account_id"`
+	Amount      decimal.Decimal `json:"amount"`
+}
+
+// CaptureHoldResult contains the out parameters of the capture hold transaction
+type CaptureHoldResult struct {
+	Hold     Hold             `json:"hold"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// AuthorizeHold reserves amount on the account until the hold is captured, released or expires.
+// The account is locked, so the hold and concurrent transfers cannot both spend the same money.
+func (store *SqlStore) AuthorizeHold(ctx context.Context, arg AuthorizeHoldParams) (Hold, error) {
+	var hold Hold
+
+	if !arg.Amount.IsPositive() {
+		return hold, ErrInvalidHoldAmount
+	}
+
+	expiresAt := arg.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(DefaultHoldDuration)
+	}
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		if account.IsSystem {
+			return ErrSystemAccount
+		}
+
+		err = store.checkCanSend(ctx, q, account)
+		if err != nil {
+			return err
+		}
+
+		currency, err := q.GetCurrency(ctx, account.Currency)
+		if err != nil {
+			return err
+		}
+
+		err = checkAmountPrecision(arg.Amount, currency)
+		if err != nil {
+			return err
+		}
+
+		err = checkAvailableFunds(ctx, q, account, arg.Amount)
+		if err != nil {
+			return err
+		}
+
+		hold, err = q.CreateHold(ctx, CreateHoldParams{
+			AccountID: arg.AccountID,
+			Amount:    arg.Amount,
+			ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+		})
+		return err
+	})
+
+	return hold, txErr
+}
+
+// CaptureHold settles an active hold with a transfer from the held account to the to account.
+// The hold stops reserving money before the transfer is made, so the captured amount is
+// only counted once against the available balance.
+func (store *SqlStore) CaptureHold(ctx context.Context, arg CaptureHoldParams) (CaptureHoldResult, error) {
+	var result CaptureHoldResult
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		hold, err := lockActiveHold(ctx, q, arg.HoldID)
+		if err != nil {
+			return err
+		}
+
+		amount := arg.Amount
+		if amount.IsZero() {
+			amount = hold.Amount
+		}
+
+		if amount.IsNegative() || amount.GreaterThan(hold.Amount) {
+			return ErrInvalidCaptureAmount
+		}
+
+		_, err = q.SettleHold(ctx, SettleHoldParams{
+			ID:             hold.ID,
+			Status:         HoldStatusCaptured,
+			CapturedAmount: amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = store.transfer(ctx, q, TransferTxParams{
+			FromAccountId: hold.AccountID,
+			ToAccountId:   arg.ToAccountID,
+			Amount:        amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.SetHoldTransfer(ctx, SetHoldTransferParams{
+			ID:         hold.ID,
+			TransferID: pgtype.Int8{Int64: result.Transfer.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, txErr
+}
+
+// ReleaseHold cancels an active hold, the reserved money becomes available again
+func (store *SqlStore) ReleaseHold(ctx context.Context, holdID int64) (Hold, error) {
+	var hold Hold
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		_, err := lockActiveHold(ctx, q, holdID)
+		if err != nil {
+			return err
+		}
+
+		hold, err = q.SettleHold(ctx, SettleHoldParams{
+			ID:             holdID,
+			Status:         HoldStatusReleased,
+			CapturedAmount: decimal.NewFromInt(0),
+		})
+		return err
+	})
+
+	return hold, txErr
+}
+
+// lockActiveHold locks the hold for the rest of the transaction and makes sure it can still be settled.
+// A hold past its expiry is rejected even if the expiry job did not mark it yet.
+func lockActiveHold(ctx context.Context, q *Queries, holdID int64) (Hold, error) {
+	hold, err := q.GetHoldForUpdate(ctx, holdID)
+	if err != nil {
+		return hold, err
+	}
+
+	if hold.Status != HoldStatusActive {
+		return hold, &HoldNotActiveError{HoldID: hold.ID, Status: hold.Status}
+	}
+
+	if !hold.ExpiresAt.Time.After(time.Now()) {
+		return hold, &HoldNotActiveError{HoldID: hold.ID, Status: HoldStatusExpired}
+	}
+
+	return hold, nil
+}
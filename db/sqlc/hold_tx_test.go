@@ -0,0 +1,183 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizeHold(t *testing.T) {
+	store := NewStore(connPool)
+
+	account := createRandomAccountWithCurrency(t, CurrencyUSD)
+	other := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	available := account.Balance.Add(account.OverdraftLimit)
+	hold, err := store.AuthorizeHold(context.Background(), AuthorizeHoldParams{
+		AccountID: account.ID,
+		Amount:    available,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, account.ID, hold.AccountID)
+	assert.True(t, available.Equal(hold.Amount))
+	assert.Equal(t, HoldStatusActive, hold.Status)
+	assert.WithinDuration(t, time.Now().Add(DefaultHoldDuration), hold.ExpiresAt.Time, time.Minute)
+
+	// The balance is untouched but nothing is available anymore
+	updatedAccount, err := store.GetAccount(context.Background(), account.ID)
+	assert.NoError(t, err)
+	assert.True(t, account.Balance.Equal(updatedAccount.Balance))
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account.ID,
+		ToAccountId:   other.ID,
+		Amount:        decimal.NewFromInt(1),
+	})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.AuthorizeHold(context.Background(), AuthorizeHoldParams{
+		AccountID: account.ID,
+		Amount:    decimal.NewFromInt(1),
+	})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = store.AuthorizeHold(context.Background(), AuthorizeHoldParams{
+		AccountID: account.ID,
+		Amount:    decimal.NewFromInt(0),
+	})
+	assert.ErrorIs(t, err, ErrInvalidHoldAmount)
+}
+
+func TestCaptureHold(t *testing.T) {
+	store := NewStore(connPool)
+
+	account := createRandomAccountWithCurrency(t, CurrencyUSD)
+	merchant := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	hold, err := store.AuthorizeHold(context.Background(), AuthorizeHoldParams{
+		AccountID: account.ID,
+		Amount:    decimal.NewFromInt(10),
+	})
+	assert.NoError(t, err)
+
+	// Partial capture, the remainder is released
+	result, err := store.CaptureHold(context.Background(), CaptureHoldParams{
+		HoldID:      hold.ID,
+		ToAccountID: merchant.ID,
+		Amount:      decimal.NewFromInt(4),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, HoldStatusCaptured, result.Hold.Status)
+	assert.True(t, decimal.NewFromInt(4).Equal(result.Hold.CapturedAmount))
+	assert.Equal(t, result.Transfer.Transfer.ID, result.Hold.TransferID.Int64)
+	assert.True(t, result.Hold.SettledAt.Valid)
+
+	assert.True(t, account.Balance.Sub(decimal.NewFromInt(4)).Equal(result.Transfer.FromAccount.Balance))
+	assert.True(t, merchant.Balance.Add(decimal.NewFromInt(4)).Equal(result.Transfer.ToAccount.Balance))
+
+	heldAmount, err := store.GetAccountHeldAmount(context.Background(), account.ID)
+	assert.NoError(t, err)
+	assert.True(t, heldAmount.IsZero())
+
+	// A hold is captured only once
+	_, err = store.CaptureHold(context.Background(), CaptureHoldParams{
+		HoldID:      hold.ID,
+		ToAccountID: merchant.ID,
+	})
+	assert.ErrorIs(t, err, ErrHoldNotActive)
+
+	// A capture cannot exceed the hold
+	hold, err = store.AuthorizeHold(context.Background(), AuthorizeHoldParams{
+		AccountID: account.ID,
+		Amount:    decimal.NewFromInt(1),
+	})
+	assert.NoError(t, err)
+
+	_, err = store.CaptureHold(context.Background(), CaptureHoldParams{
+		HoldID:      hold.ID,
+		ToAccountID: merchant.ID,
+		Amount:      decimal.NewFromInt(2),
+	})
+	assert.ErrorIs(t, err, ErrInvalidCaptureAmount)
+
+	// Full capture
+	result, err = store.CaptureHold(context.Background(), CaptureHoldParams{
+		HoldID:      hold.ID,
+		ToAccountID: merchant.ID,
+	})
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(1).Equal(result.Transfer.Transfer.Amount))
+}
+
+func TestReleaseHold(t *testing.T) {
+	store := NewStore(connPool)
+
+	account := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	hold, err := store.AuthorizeHold(context.Background(), AuthorizeHoldParams{
+		AccountID: account.ID,
+		Amount:    decimal.NewFromInt(5),
+	})
+	assert.NoError(t, err)
+
+	heldAmount, err := store.GetAccountHeldAmount(context.Background(), account.ID)
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(5).Equal(heldAmount))
+
+	releasedHold, err := store.ReleaseHold(context.Background(), hold.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, HoldStatusReleased, releasedHold.Status)
+	assert.True(t, releasedHold.CapturedAmount.IsZero())
+
+	heldAmount, err = store.GetAccountHeldAmount(context.Background(), account.ID)
+	assert.NoError(t, err)
+	assert.True(t, heldAmount.IsZero())
+
+	_, err = store.ReleaseHold(context.Background(), hold.ID)
+	assert.ErrorIs(t, err, ErrHoldNotActive)
+}
+
+func TestExpireHolds(t *testing.T) {
+	store := NewStore(connPool)
+
+	account := createRandomAccountWithCurrency(t, CurrencyUSD)
+	other := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	hold, err := store.AuthorizeHold(context.Background(), AuthorizeHoldParams{
+		AccountID: account.ID,
+		Amount:    decimal.NewFromInt(5),
+		ExpiresAt: time.Now().Add(time.Second),
+	})
+	assert.NoError(t, err)
+
+	time.Sleep(time.Second)
+
+	// A stale hold reserves nothing, even before it is marked expired
+	heldAmount, err := store.GetAccountHeldAmount(context.Background(), account.ID)
+	assert.NoError(t, err)
+	assert.True(t, heldAmount.IsZero())
+
+	_, err = store.CaptureHold(context.Background(), CaptureHoldParams{
+		HoldID:      hold.ID,
+		ToAccountID: other.ID,
+	})
+	assert.ErrorIs(t, err, ErrHoldExpired)
+
+	expiredHolds, err := store.ExpireHolds(context.Background())
+	assert.NoError(t, err)
+
+	var expired bool
+	for _, expiredHold := range expiredHolds {
+		if expiredHold.ID == hold.ID {
+			expired = true
+			assert.Equal(t, HoldStatusExpired, expiredHold.Status)
+		}
+	}
+	assert.True(t, expired)
+
+	_, err = store.ReleaseHold(context.Background(), hold.ID)
+	assert.ErrorIs(t, err, ErrHoldExpired)
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is replayed
+// with a request different from the one it was first used with
+var ErrIdempotencyKeyReused = errors.New("idempotency key already used with a different request")
+
+// IdempotentTransferTxParams contains the input parameters of the idempotent transfer transaction
+type IdempotentTransferTxParams struct {
+	TransferTxParams
+	Username    string
+	Key         string
+	RequestHash string
+	ExpiresAt   time.Time
+}
+
+// IdempotentTransferTxResult contains the out parameters of the idempotent transfer transaction
+type IdempotentTransferTxResult struct {
+	TransferTxResult
+	// Replayed is true when the result is the one stored by a previous request
+	Replayed bool
+}
+
+// IdempotentTransferTx performs the transfer at most once per username and idempotency key.
+// Concurrent requests with the same key are serialized on an advisory lock.
+// A replay of the same request returns the stored result without transferring again,
+// while a replay with a different request fails with ErrIdempotencyKeyReused.
+// Failed transfers are not stored, so they can be retried with the same key.
+func (store *SqlStore) IdempotentTransferTx(
+	ctx context.Context,
+	arg IdempotentTransferTxParams) (IdempotentTransferTxResult, error) {
+
+	var result IdempotentTransferTxResult
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		result = IdempotentTransferTxResult{}
+
+		err := q.LockIdempotencyKey(ctx, LockIdempotencyKeyParams{
+			Username: arg.Username,
+			Key:      arg.Key,
+		})
+		if err != nil {
+			return err
+		}
+
+		idempotencyKey, err := q.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{
+			Username: arg.Username,
+			Key:      arg.Key,
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		// An expired key is simply overwritten below
+		if err == nil {
+			result.TransferTxResult, result.Replayed, err = ReplayTransfer(idempotencyKey, arg.RequestHash)
+			if err != nil || result.Replayed {
+				return err
+			}
+		}
+
+		result.TransferTxResult, err = store.transfer(ctx, q, arg.TransferTxParams)
+		if err != nil {
+			return err
+		}
+
+		response, err := json.Marshal(result.TransferTxResult)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.UpsertIdempotencyKey(ctx, UpsertIdempotencyKeyParams{
+			Username:    arg.Username,
+			Key:         arg.Key,
+			RequestHash: arg.RequestHash,
+			Response:    response,
+			ExpiresAt:   pgtype.Timestamptz{Time: arg.ExpiresAt, Valid: true},
+		})
+
+		return err
+	})
+
+	return result, txErr
+}
+
+// ReplayTransfer returns the transfer result stored with an idempotency key.
+// It returns false when the key has expired, and fails with ErrIdempotencyKeyReused
+// when the key was first used with a request other than the one of requestHash.
+func ReplayTransfer(idempotencyKey IdempotencyKey, requestHash string) (TransferTxResult, bool, error) {
+	var result TransferTxResult
+
+	if !time.Now().Before(idempotencyKey.ExpiresAt.Time) {
+		return result, false, nil
+	}
+
+	if idempotencyKey.RequestHash != requestHash {
+		return result, false, ErrIdempotencyKeyReused
+	}
+
+	err := json.Unmarshal(idempotencyKey.Response, &result)
+	if err != nil {
+		return result, false, err
+	}
+
+	return result, true, nil
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotentTransferTx(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	arg := IdempotentTransferTxParams{
+		TransferTxParams: TransferTxParams{
+			FromAccountId: account1.ID,
+			ToAccountId:   account2.ID,
+			Amount:        decimal.NewFromInt32(10),
+		},
+		Username:    account1.Owner,
+		Key:         utils.RandomString(16),
+		RequestHash: utils.RandomString(64),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	// Send the same request concurrently, only one transfer must be made
+	n := 5
+	errs := make(chan error)
+	results := make(chan IdempotentTransferTxResult)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			result, err := store.IdempotentTransferTx(context.Background(), arg)
+
+			errs <- err
+			results <- result
+		}()
+	}
+
+	var transferId int64
+	replayed := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		assert.NoError(t, err)
+
+		result := <-results
+		assert.NotZero(t, result.Transfer.ID)
+
+		if transferId == 0 {
+			transferId = result.Transfer.ID
+		}
+		assert.Equal(t, transferId, result.Transfer.ID)
+
+		if result.Replayed {
+			replayed++
+		}
+	}
+	assert.Equal(t, n-1, replayed)
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	assert.NoError(t, err)
+	assert.True(t, account1.Balance.Sub(arg.Amount).Equal(updatedAccount1.Balance))
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	assert.NoError(t, err)
+	assert.True(t, account2.Balance.Add(arg.Amount).Equal(updatedAccount2.Balance))
+
+	// Replaying the key with a different request must fail
+	otherArg := arg
+	otherArg.RequestHash = utils.RandomString(64)
+
+	_, err = store.IdempotentTransferTx(context.Background(), otherArg)
+	assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+}
+
+func TestIdempotentTransferTxExpiredKey(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	arg := IdempotentTransferTxParams{
+		TransferTxParams: TransferTxParams{
+			FromAccountId: account1.ID,
+			ToAccountId:   account2.ID,
+			Amount:        decimal.NewFromInt32(10),
+		},
+		Username:    account1.Owner,
+		Key:         utils.RandomString(16),
+		RequestHash: utils.RandomString(64),
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	}
+
+	result1, err := store.IdempotentTransferTx(context.Background(), arg)
+	assert.NoError(t, err)
+	assert.False(t, result1.Replayed)
+
+	// The key already expired, so the transfer is made again
+	result2, err := store.IdempotentTransferTx(context.Background(), arg)
+	assert.NoError(t, err)
+	assert.False(t, result2.Replayed)
+	assert.NotEqual(t, result1.Transfer.ID, result2.Transfer.ID)
+}
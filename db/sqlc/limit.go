@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// Scopes of the transfer limits
+const (
+	LimitScopeAccount = "account"
+	LimitScopeUser    = "user"
+)
+
+// Transfer limits, named after their column
+const (
+	LimitMaxSingleTransfer   = "max_single_transfer"
+	LimitDailyOutgoing       = "daily_outgoing"
+	LimitMonthlyOutgoing     = "monthly_outgoing"
+	LimitMaxTransfersPerHour = "max_transfers_per_hour"
+)
+
+// ErrLimitExceeded is returned when a transfer would breach a limit of its from account or of its owner
+var ErrLimitExceeded = errors.New("transfer limit exceeded")
+
+// LimitExceededError describes a transfer rejected because of a limit.
+// Max, Used and Requested are amounts in Currency, or numbers of transfers
+// for LimitMaxTransfersPerHour. Used is zero for LimitMaxSingleTransfer.
+type LimitExceededError struct {
+	LimitID   int64
+	Scope     string
+	Limit     string
+	Currency  Currency
+	Max       decimal.Decimal
+	Used      decimal.Decimal
+	Requested decimal.Decimal
+}
+
+func (e *LimitExceededError) Error() string {
+	unit := string(e.Currency)
+	if e.Limit == LimitMaxTransfersPerHour {
+		unit = "transfers"
+	}
+
+	return fmt.Sprintf(
+		"%s limit %s of %s %s exceeded, used: %s, requested: %s",
+		e.Scope,
+		e.Limit,
+		e.Max,
+		unit,
+		e.Used,
+		e.Requested)
+}
+
+func (e *LimitExceededError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// limitUsage is what has been sent in the windows of the limits
+type limitUsage struct {
+	dailyTotal   decimal.Decimal
+	monthlyTotal decimal.Decimal
+	hourlyCount  int64
+}
+
+// limitWindows are the starts of the windows of the limits: the calendar day and month
+// in UTC for the outgoing totals and the last hour for the number of transfers
+type limitWindows struct {
+	dayStart   pgtype.Timestamptz
+	monthStart pgtype.Timestamptz
+	hourStart  pgtype.Timestamptz
+}
+
+func newLimitWindows(now time.Time) limitWindows {
+	now = now.UTC()
+
+	return limitWindows{
+		dayStart:   pgtype.Timestamptz{Time: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), Valid: true},
+		monthStart: pgtype.Timestamptz{Time: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		hourStart:  pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+	}
+}
+
+// checkTransferLimits makes sure a transfer of amount from account stays within the limits
+// of the account and of its owner in the currency of the account. The account is expected
+// to be locked, so that concurrent transfers are counted against the limits one after the other.
+// The limits of the owner are covered by the same lock, as only one account of the owner
+// per currency can be open, the closed ones cannot send transfers.
+func checkTransferLimits(ctx context.Context, q *Queries, account Account, amount decimal.Decimal) error {
+	limits, err := q.ListTransferLimits(ctx, ListTransferLimitsParams{
+		AccountID: account.ID,
+		Owner:     account.Owner,
+		Currency:  account.Currency,
+	})
+	if err != nil {
+		return err
+	}
+
+	windows := newLimitWindows(time.Now())
+	for _, limit := range limits {
+		scope := LimitScopeAccount
+		if limit.Owner.Valid {
+			scope = LimitScopeUser
+		}
+
+		if limit.MaxSingleTransfer.Valid && amount.GreaterThan(limit.MaxSingleTransfer.Decimal) {
+			return &LimitExceededError{
+				LimitID:   limit.ID,
+				Scope:     scope,
+				Limit:     LimitMaxSingleTransfer,
+				Currency:  limit.Currency,
+				Max:       limit.MaxSingleTransfer.Decimal,
+				Used:      decimal.Zero,
+				Requested: amount,
+			}
+		}
+
+		if !limit.DailyOutgoing.Valid && !limit.MonthlyOutgoing.Valid && !limit.MaxTransfersPerHour.Valid {
+			continue
+		}
+
+		usage, err := getLimitUsage(ctx, q, account, scope, windows)
+		if err != nil {
+			return err
+		}
+
+		err = checkLimitUsage(limit, scope, usage, amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getLimitUsage returns what has been sent from the account, or from all the accounts
+// of its owner in its currency, closed ones included, for the user scope
+func getLimitUsage(ctx context.Context, q *Queries, account Account, scope string, windows limitWindows) (limitUsage, error) {
+	if scope == LimitScopeAccount {
+		usage, err := q.GetAccountOutgoingUsage(ctx, GetAccountOutgoingUsageParams{
+			DayStart:   windows.dayStart,
+			MonthStart: windows.monthStart,
+			HourStart:  windows.hourStart,
+			AccountID:  account.ID,
+		})
+		return limitUsage{usage.DailyTotal, usage.MonthlyTotal, usage.HourlyCount}, err
+	}
+
+	usage, err := q.GetUserOutgoingUsage(ctx, GetUserOutgoingUsageParams{
+		DayStart:   windows.dayStart,
+		MonthStart: windows.monthStart,
+		HourStart:  windows.hourStart,
+		Owner:      account.Owner,
+		Currency:   account.Currency,
+	})
+	return limitUsage{usage.DailyTotal, usage.MonthlyTotal, usage.HourlyCount}, err
+}
+
+// checkLimitUsage compares the usage including the transfer with the velocity limits
+func checkLimitUsage(limit Limit, scope string, usage limitUsage, amount decimal.Decimal) error {
+	newLimitExceededError := func(name string, max decimal.Decimal, used decimal.Decimal, requested decimal.Decimal) error {
+		return &LimitExceededError{
+			LimitID:   limit.ID,
+			Scope:     scope,
+			Limit:     name,
+			Currency:  limit.Currency,
+			Max:       max,
+			Used:      used,
+			Requested: requested,
+		}
+	}
+
+	if limit.MaxTransfersPerHour.Valid && usage.hourlyCount >= int64(limit.MaxTransfersPerHour.Int32) {
+		return newLimitExceededError(
+			LimitMaxTransfersPerHour,
+			decimal.NewFromInt32(limit.MaxTransfersPerHour.Int32),
+			decimal.NewFromInt(usage.hourlyCount),
+			decimal.NewFromInt(1))
+	}
+
+	if limit.DailyOutgoing.Valid && usage.dailyTotal.Add(amount).GreaterThan(limit.DailyOutgoing.Decimal) {
+		return newLimitExceededError(LimitDailyOutgoing, limit.DailyOutgoing.Decimal, usage.dailyTotal, amount)
+	}
+
+	if limit.MonthlyOutgoing.Valid && usage.monthlyTotal.Add(amount).GreaterThan(limit.MonthlyOutgoing.Decimal) {
+		return newLimitExceededError(LimitMonthlyOutgoing, limit.MonthlyOutgoing.Decimal, usage.monthlyTotal, amount)
+	}
+
+	return nil
+}
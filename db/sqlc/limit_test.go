@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertAccountLimit(t *testing.T) {
+	account := createRandomAccount(t)
+
+	arg := UpsertAccountLimitParams{
+		AccountID:         account.ID,
+		Currency:          account.Currency,
+		MaxSingleTransfer: decimal.NewNullDecimal(decimal.NewFromInt(50)),
+	}
+
+	limit, err := testQueries.UpsertAccountLimit(context.Background(), arg)
+	assert.NoError(t, err)
+	assert.Equal(t, account.ID, limit.AccountID.Int64)
+	assert.False(t, limit.Owner.Valid)
+	assert.True(t, decimal.NewFromInt(50).Equal(limit.MaxSingleTransfer.Decimal))
+	assert.False(t, limit.DailyOutgoing.Valid)
+
+	// The limits of the account are replaced
+	arg.MaxSingleTransfer = decimal.NullDecimal{}
+	arg.MaxTransfersPerHour = pgtype.Int4{Int32: 3, Valid: true}
+
+	updated, err := testQueries.UpsertAccountLimit(context.Background(), arg)
+	assert.NoError(t, err)
+	assert.Equal(t, limit.ID, updated.ID)
+	assert.False(t, updated.MaxSingleTransfer.Valid)
+	assert.Equal(t, int32(3), updated.MaxTransfersPerHour.Int32)
+
+	// Limits must be positive
+	arg.MaxTransfersPerHour = pgtype.Int4{Int32: 0, Valid: true}
+	_, err = testQueries.UpsertAccountLimit(context.Background(), arg)
+	assert.Error(t, err)
+}
+
+func TestTransferTxMaxSingleTransfer(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	_, err := testQueries.UpsertAccountLimit(context.Background(), UpsertAccountLimitParams{
+		AccountID:         account1.ID,
+		Currency:          account1.Currency,
+		MaxSingleTransfer: decimal.NewNullDecimal(decimal.NewFromInt(50)),
+	})
+	assert.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.NewFromInt(50),
+	})
+	assert.NoError(t, err)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.NewFromInt(51),
+	})
+
+	var limitErr *LimitExceededError
+	if assert.ErrorAs(t, err, &limitErr) {
+		assert.Equal(t, LimitScopeAccount, limitErr.Scope)
+		assert.Equal(t, LimitMaxSingleTransfer, limitErr.Limit)
+	}
+
+	// Limits only apply to transfers sent by the account
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account2.ID,
+		ToAccountId:   account1.ID,
+		Amount:        decimal.NewFromInt(60),
+	})
+	assert.NoError(t, err)
+}
+
+func TestTransferTxDailyOutgoingUserLimit(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	_, err := testQueries.UpsertUserLimit(context.Background(), UpsertUserLimitParams{
+		Owner:         account1.Owner,
+		Currency:      CurrencyUSD,
+		DailyOutgoing: decimal.NewNullDecimal(decimal.NewFromInt(30)),
+	})
+	assert.NoError(t, err)
+
+	// The limits of the user in another currency don't apply
+	_, err = testQueries.UpsertUserLimit(context.Background(), UpsertUserLimitParams{
+		Owner:             account1.Owner,
+		Currency:          CurrencyEUR,
+		MaxSingleTransfer: decimal.NewNullDecimal(decimal.NewFromInt(1)),
+	})
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountId: account1.ID,
+			ToAccountId:   account2.ID,
+			Amount:        decimal.NewFromInt(10),
+		})
+		assert.NoError(t, err)
+	}
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.NewFromInt(1),
+	})
+
+	var limitErr *LimitExceededError
+	if assert.ErrorAs(t, err, &limitErr) {
+		assert.Equal(t, LimitScopeUser, limitErr.Scope)
+		assert.Equal(t, LimitDailyOutgoing, limitErr.Limit)
+		assert.True(t, decimal.NewFromInt(30).Equal(limitErr.Used))
+	}
+}
+
+func TestTransferTxMaxTransfersPerHourConcurrent(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	_, err := testQueries.UpsertAccountLimit(context.Background(), UpsertAccountLimitParams{
+		AccountID:           account1.ID,
+		Currency:            account1.Currency,
+		MaxTransfersPerHour: pgtype.Int4{Int32: 3, Valid: true},
+	})
+	assert.NoError(t, err)
+
+	// Send 5 transfers concurrently, only 3 of them fit in the limit
+	n := 5
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountId: account1.ID,
+				ToAccountId:   account2.ID,
+				Amount:        decimal.NewFromInt(1),
+			})
+
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrLimitExceeded):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	assert.Equal(t, 3, succeeded)
+
+	windows := newLimitWindows(time.Now())
+	usage, err := testQueries.GetAccountOutgoingUsage(context.Background(), GetAccountOutgoingUsageParams{
+		DayStart:   windows.dayStart,
+		MonthStart: windows.monthStart,
+		HourStart:  windows.hourStart,
+		AccountID:  account1.ID,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), usage.HourlyCount)
+}
+
+func TestNewLimitWindows(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 10, 30, 0, 0, time.FixedZone("CET", 3600))
+	windows := newLimitWindows(now)
+
+	assert.Equal(t, time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC), windows.dayStart.Time)
+	assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), windows.monthStart.Time)
+	assert.Equal(t, time.Date(2024, time.March, 15, 8, 30, 0, 0, time.UTC), windows.hourStart.Time)
+}
@@ -8,51 +8,227 @@ import (
 	"database/sql/driver"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
-type Currency string
+type AccountStatus string
 
 const (
-	CurrencyUSD Currency = "USD"
-	CurrencyEUR Currency = "EUR"
-	CurrencyINR Currency = "INR"
+	AccountStatusActive AccountStatus = "active"
+	AccountStatusFrozen AccountStatus = "frozen"
+	AccountStatusClosed AccountStatus = "closed"
 )
 
-func (e *Currency) Scan(src interface{}) error {
+func (e *AccountStatus) Scan(src interface{}) error {
 	switch s := src.(type) {
 	case []byte:
-		*e = Currency(s)
+		*e = AccountStatus(s)
 	case string:
-		*e = Currency(s)
+		*e = AccountStatus(s)
 	default:
-		return fmt.Errorf("unsupported scan type for Currency: %T", src)
+		return fmt.Errorf("unsupported scan type for AccountStatus: %T", src)
 	}
 	return nil
 }
 
-type NullCurrency struct {
-	Currency Currency `json:"currency"`
-	Valid    bool     `json:"valid"` // Valid is true if Currency is not NULL
+type NullAccountStatus struct {
+	AccountStatus AccountStatus `json:"accountStatus"`
+	Valid         bool          `json:"valid"` // Valid is true if AccountStatus is not NULL
 }
 
 // Scan implements the Scanner interface.
-func (ns *NullCurrency) Scan(value interface{}) error {
+func (ns *NullAccountStatus) Scan(value interface{}) error {
 	if value == nil {
-		ns.Currency, ns.Valid = "", false
+		ns.AccountStatus, ns.Valid = "", false
 		return nil
 	}
 	ns.Valid = true
-	return ns.Currency.Scan(value)
+	return ns.AccountStatus.Scan(value)
 }
 
 // Value implements the driver Valuer interface.
-func (ns NullCurrency) Value() (driver.Value, error) {
+func (ns NullAccountStatus) Value() (driver.Value, error) {
 	if !ns.Valid {
 		return nil, nil
 	}
-	return string(ns.Currency), nil
+	return string(ns.AccountStatus), nil
+}
+
+type HoldStatus string
+
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusReleased HoldStatus = "released"
+	HoldStatusExpired  HoldStatus = "expired"
+)
+
+func (e *HoldStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = HoldStatus(s)
+	case string:
+		*e = HoldStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for HoldStatus: %T", src)
+	}
+	return nil
+}
+
+type NullHoldStatus struct {
+	HoldStatus HoldStatus `json:"holdStatus"`
+	Valid      bool       `json:"valid"` // Valid is true if HoldStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullHoldStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.HoldStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.HoldStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullHoldStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.HoldStatus), nil
+}
+
+type ScheduleFrequency string
+
+const (
+	ScheduleFrequencyOnce    ScheduleFrequency = "once"
+	ScheduleFrequencyDaily   ScheduleFrequency = "daily"
+	ScheduleFrequencyWeekly  ScheduleFrequency = "weekly"
+	ScheduleFrequencyMonthly ScheduleFrequency = "monthly"
+)
+
+func (e *ScheduleFrequency) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduleFrequency(s)
+	case string:
+		*e = ScheduleFrequency(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduleFrequency: %T", src)
+	}
+	return nil
+}
+
+type NullScheduleFrequency struct {
+	ScheduleFrequency ScheduleFrequency `json:"scheduleFrequency"`
+	Valid             bool              `json:"valid"` // Valid is true if ScheduleFrequency is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduleFrequency) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduleFrequency, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduleFrequency.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduleFrequency) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScheduleFrequency), nil
+}
+
+type ScheduledTransferStatus string
+
+const (
+	ScheduledTransferStatusActive    ScheduledTransferStatus = "active"
+	ScheduledTransferStatusPaused    ScheduledTransferStatus = "paused"
+	ScheduledTransferStatusCancelled ScheduledTransferStatus = "cancelled"
+	ScheduledTransferStatusCompleted ScheduledTransferStatus = "completed"
+)
+
+func (e *ScheduledTransferStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduledTransferStatus(s)
+	case string:
+		*e = ScheduledTransferStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduledTransferStatus: %T", src)
+	}
+	return nil
+}
+
+type NullScheduledTransferStatus struct {
+	ScheduledTransferStatus ScheduledTransferStatus `json:"scheduledTransferStatus"`
+	Valid                   bool                    `json:"valid"` // Valid is true if ScheduledTransferStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduledTransferStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduledTransferStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduledTransferStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduledTransferStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ScheduledTransferStatus), nil
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusDead      WebhookDeliveryStatus = "dead"
+)
+
+func (e *WebhookDeliveryStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WebhookDeliveryStatus(s)
+	case string:
+		*e = WebhookDeliveryStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WebhookDeliveryStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWebhookDeliveryStatus struct {
+	WebhookDeliveryStatus WebhookDeliveryStatus `json:"webhookDeliveryStatus"`
+	Valid                 bool                  `json:"valid"` // Valid is true if WebhookDeliveryStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWebhookDeliveryStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WebhookDeliveryStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WebhookDeliveryStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWebhookDeliveryStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WebhookDeliveryStatus), nil
 }
 
 type Account struct {
@@ -61,6 +237,69 @@ type Account struct {
 	Balance   decimal.Decimal    `json:"balance"`
 	Currency  Currency           `json:"currency"`
 	CreatedAt pgtype.Timestamptz `json:"createdAt"`
+	// how far below zero the balance is allowed to go
+	OverdraftLimit decimal.Decimal `json:"overdraftLimit"`
+	// cash/suspense account of a currency, counterpart of deposits, withdrawals and adjustments
+	IsSystem bool `json:"isSystem"`
+	// only active accounts can send or receive transfers
+	Status AccountStatus `json:"status"`
+}
+
+type AccountFreeze struct {
+	ID        int64  `json:"id"`
+	AccountID int64  `json:"accountId"`
+	Reason    string `json:"reason"`
+	// name of the admin who froze the account
+	FrozenBy       string             `json:"frozenBy"`
+	FrozenAt       pgtype.Timestamptz `json:"frozenAt"`
+	UnfreezeReason pgtype.Text        `json:"unfreezeReason"`
+	UnfrozenBy     pgtype.Text        `json:"unfrozenBy"`
+	// null while the freeze is in place
+	UnfrozenAt pgtype.Timestamptz `json:"unfrozenAt"`
+}
+
+type AuditEvent struct {
+	ID int64 `json:"id"`
+	// name of the user who made the change, system for the background workers
+	Actor        string `json:"actor"`
+	Action       string `json:"action"`
+	ResourceType string `json:"resourceType"`
+	ResourceID   string `json:"resourceId"`
+	// state of the resource before the change, null when it is created
+	Before    []byte `json:"before"`
+	After     []byte `json:"after"`
+	RequestID string `json:"requestId"`
+	ClientIp  string `json:"clientIp"`
+	// hash of the previous event, empty for the first one
+	PrevHash string `json:"prevHash"`
+	// SHA-256 of the event fields and prev_hash
+	Hash      string             `json:"hash"`
+	CreatedAt pgtype.Timestamptz `json:"createdAt"`
+}
+
+type BalanceAdjustment struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"accountId"`
+	EntryID   int64 `json:"entryId"`
+	// amount can be positive or negative, never zero
+	Amount     decimal.Decimal `json:"amount"`
+	ReasonCode string          `json:"reasonCode"`
+	Note       string          `json:"note"`
+	// name of the admin who made the adjustment
+	Operator  string             `json:"operator"`
+	CreatedAt pgtype.Timestamptz `json:"createdAt"`
+	// entry on the system account balancing the adjustment, null for opening balances
+	CounterEntryID pgtype.Int8 `json:"counterEntryId"`
+}
+
+type CurrencyInfo struct {
+	// ISO 4217 alphabetic code
+	Code Currency `json:"code"`
+	// number of decimal places of the minor unit
+	Exponent  int16              `json:"exponent"`
+	Symbol    string             `json:"symbol"`
+	Enabled   bool               `json:"enabled"`
+	CreatedAt pgtype.Timestamptz `json:"createdAt"`
 }
 
 type Entry struct {
@@ -69,15 +308,132 @@ type Entry struct {
 	// amount can be positive or negative
 	Amount    decimal.Decimal    `json:"amount"`
 	CreatedAt pgtype.Timestamptz `json:"createdAt"`
+	// transfer the entry belongs to, null for the entries of balance adjustments
+	TransferID pgtype.Int8 `json:"transferId"`
+}
+
+type Hold struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"accountId"`
+	// reserved on the account while the hold is active
+	Amount decimal.Decimal `json:"amount"`
+	// can be lower than amount for partial captures
+	CapturedAmount decimal.Decimal `json:"capturedAmount"`
+	Status         HoldStatus      `json:"status"`
+	// transfer created by the capture
+	TransferID pgtype.Int8        `json:"transferId"`
+	ExpiresAt  pgtype.Timestamptz `json:"expiresAt"`
+	CreatedAt  pgtype.Timestamptz `json:"createdAt"`
+	SettledAt  pgtype.Timestamptz `json:"settledAt"`
+}
+
+type IdempotencyKey struct {
+	Username    string             `json:"username"`
+	Key         string             `json:"key"`
+	RequestHash string             `json:"requestHash"`
+	Response    []byte             `json:"response"`
+	ExpiresAt   pgtype.Timestamptz `json:"expiresAt"`
+	CreatedAt   pgtype.Timestamptz `json:"createdAt"`
+}
+
+type Limit struct {
+	ID int64 `json:"id"`
+	// set for the limits of an account, null for the limits of a user
+	AccountID pgtype.Int8 `json:"accountId"`
+	// set for the limits of a user, applied to all its accounts in currency
+	Owner             pgtype.Text         `json:"owner"`
+	Currency          Currency            `json:"currency"`
+	MaxSingleTransfer decimal.NullDecimal `json:"maxSingleTransfer"`
+	// total sent during a calendar day (UTC), null for no limit
+	DailyOutgoing decimal.NullDecimal `json:"dailyOutgoing"`
+	// total sent during a calendar month (UTC), null for no limit
+	MonthlyOutgoing decimal.NullDecimal `json:"monthlyOutgoing"`
+	// number of transfers sent during the last hour, null for no limit
+	MaxTransfersPerHour pgtype.Int4        `json:"maxTransfersPerHour"`
+	CreatedAt           pgtype.Timestamptz `json:"createdAt"`
+	UpdatedAt           pgtype.Timestamptz `json:"updatedAt"`
+}
+
+type OutboxEvent struct {
+	ID        int64  `json:"id"`
+	EventType string `json:"eventType"`
+	// id of the record the event is about, eg: the transfer id
+	AggregateID int64       `json:"aggregateId"`
+	Payload     []byte      `json:"payload"`
+	Attempts    int32       `json:"attempts"`
+	LastError   pgtype.Text `json:"lastError"`
+	// pushed back while a relay publishes the event and after each failure
+	NextAttemptAt pgtype.Timestamptz `json:"nextAttemptAt"`
+	// null until the event is published
+	PublishedAt pgtype.Timestamptz `json:"publishedAt"`
+	CreatedAt   pgtype.Timestamptz `json:"createdAt"`
+}
+
+type PendingAuditEvent struct {
+	ID           int64              `json:"id"`
+	Actor        string             `json:"actor"`
+	Action       string             `json:"action"`
+	ResourceType string             `json:"resourceType"`
+	ResourceID   string             `json:"resourceId"`
+	Before       []byte             `json:"before"`
+	After        []byte             `json:"after"`
+	RequestID    string             `json:"requestId"`
+	ClientIp     string             `json:"clientIp"`
+	CreatedAt    pgtype.Timestamptz `json:"createdAt"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64             `json:"id"`
+	Owner         string            `json:"owner"`
+	FromAccountID int64             `json:"fromAccountId"`
+	ToAccountID   int64             `json:"toAccountId"`
+	Amount        decimal.Decimal   `json:"amount"`
+	Frequency     ScheduleFrequency `json:"frequency"`
+	// number of frequency units between two runs, eg: 2 weeks
+	IntervalCount int32              `json:"intervalCount"`
+	StartAt       pgtype.Timestamptz `json:"startAt"`
+	// no run is scheduled after it, null to run until cancelled
+	EndAt     pgtype.Timestamptz      `json:"endAt"`
+	NextRunAt pgtype.Timestamptz      `json:"nextRunAt"`
+	Status    ScheduledTransferStatus `json:"status"`
+	CreatedAt pgtype.Timestamptz      `json:"createdAt"`
+}
+
+type ScheduledTransferRun struct {
+	ID                  int64              `json:"id"`
+	ScheduledTransferID int64              `json:"scheduledTransferId"`
+	ScheduledFor        pgtype.Timestamptz `json:"scheduledFor"`
+	TransferID          pgtype.Int8        `json:"transferId"`
+	// set when the transfer failed, the run is not retried
+	Error     pgtype.Text        `json:"error"`
+	CreatedAt pgtype.Timestamptz `json:"createdAt"`
+}
+
+type Session struct {
+	ID           uuid.UUID          `json:"id"`
+	Username     string             `json:"username"`
+	RefreshToken string             `json:"refreshToken"`
+	UserAgent    string             `json:"userAgent"`
+	ClientIp     string             `json:"clientIp"`
+	IsBlocked    bool               `json:"isBlocked"`
+	ExpiresAt    pgtype.Timestamptz `json:"expiresAt"`
+	CreatedAt    pgtype.Timestamptz `json:"createdAt"`
 }
 
 type Transfer struct {
 	ID            int64 `json:"id"`
 	FromAccountID int64 `json:"fromAccountId"`
 	ToAccountID   int64 `json:"toAccountId"`
-	// amount must be positive
+	// amount debited, in the currency of the from account
 	Amount    decimal.Decimal    `json:"amount"`
 	CreatedAt pgtype.Timestamptz `json:"createdAt"`
+	// amount credited, in the currency of the to account
+	ToAmount decimal.Decimal `json:"toAmount"`
+	// units of the to currency bought by one unit of the from currency
+	ExchangeRate  decimal.Decimal    `json:"exchangeRate"`
+	RateTimestamp pgtype.Timestamptz `json:"rateTimestamp"`
+	// id of the transfer this transfer reverses, null for a regular transfer
+	ReversalOf pgtype.Int8 `json:"reversalOf"`
 }
 
 type User struct {
@@ -86,4 +442,31 @@ type User struct {
 	HashPassword string             `json:"hashPassword"`
 	CreatedAt    pgtype.Timestamptz `json:"createdAt"`
 	UpdatedAt    pgtype.Timestamptz `json:"updatedAt"`
+	Role         string             `json:"role"`
+}
+
+type WebhookDelivery struct {
+	ID         int64 `json:"id"`
+	EndpointID int64 `json:"endpointId"`
+	EventID    int64 `json:"eventId"`
+	// dead once all the attempts failed
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int32                 `json:"attempts"`
+	NextAttemptAt  pgtype.Timestamptz    `json:"nextAttemptAt"`
+	LastStatusCode pgtype.Int4           `json:"lastStatusCode"`
+	LastError      pgtype.Text           `json:"lastError"`
+	DeliveredAt    pgtype.Timestamptz    `json:"deliveredAt"`
+	CreatedAt      pgtype.Timestamptz    `json:"createdAt"`
+}
+
+type WebhookEndpoint struct {
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	Url   string `json:"url"`
+	// key of the HMAC signature of the deliveries
+	Secret string `json:"secret"`
+	// types of the events delivered to the endpoint
+	EventTypes []string           `json:"eventTypes"`
+	IsActive   bool               `json:"isActive"`
+	CreatedAt  pgtype.Timestamptz `json:"createdAt"`
 }
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Types of the events written to the outbox
+const (
+	EventTransferCreated   = "transfer.created"
+	EventDepositCreated    = "deposit.created"
+	EventWithdrawalCreated = "withdrawal.created"
+	EventTransferReversed  = "transfer.reversed"
+	EventAccountClosed     = "account.closed"
+)
+
+// EventTypes lists all the types of the events written to the outbox
+var EventTypes = []string{
+	EventTransferCreated,
+	EventDepositCreated,
+	EventWithdrawalCreated,
+	EventTransferReversed,
+	EventAccountClosed,
+}
+
+// recordEvent records an event with the JSON of payload in the outbox, and queues its
+// delivery to the webhook endpoints of owners subscribed to its type.
+// It is expected to run in the transaction of the change the event is about,
+// so that the event is published if and only if the change is committed.
+func recordEvent(
+	ctx context.Context,
+	q *Queries,
+	eventType string,
+	aggregateID int64,
+	payload any,
+	owners ...string) error {
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	event, err := q.CreateOutboxEvent(ctx, CreateOutboxEventParams{
+		EventType:   eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(owners) == 0 {
+		return nil
+	}
+
+	return q.CreateWebhookDeliveries(ctx, CreateWebhookDeliveriesParams{
+		EventID:   event.ID,
+		Owners:    owners,
+		EventType: eventType,
+	})
+}
+
+// transferEventType returns the type of the event of a transfer between the accounts.
+// Transfers from and to the system accounts are deposits and withdrawals,
+// unless they reverse another transfer.
+func transferEventType(fromAccount Account, toAccount Account, isReversal bool) string {
+	switch {
+	case isReversal:
+		return EventTransferReversed
+	case fromAccount.IsSystem:
+		return EventDepositCreated
+	case toAccount.IsSystem:
+		return EventWithdrawalCreated
+	default:
+		return EventTransferCreated
+	}
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferTxOutboxEvent(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.NewFromInt(10),
+	})
+	assert.NoError(t, err)
+
+	// The event is leased together with the other pending events
+	var event OutboxEvent
+	for event.ID == 0 {
+		leased, err := store.LeaseOutboxEvents(context.Background(), LeaseOutboxEventsParams{
+			LeasedUntil: pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+			LimitCount:  100,
+		})
+		assert.NoError(t, err)
+		if !assert.NotEmpty(t, leased) {
+			return
+		}
+
+		for _, leasedEvent := range leased {
+			if leasedEvent.EventType == EventTransferCreated && leasedEvent.AggregateID == result.Transfer.ID {
+				event = leasedEvent
+			}
+		}
+	}
+
+	assert.False(t, event.PublishedAt.Valid)
+
+	var transfer Transfer
+	err = json.Unmarshal(event.Payload, &transfer)
+	assert.NoError(t, err)
+	assert.Equal(t, result.Transfer.ID, transfer.ID)
+	assert.True(t, result.Transfer.Amount.Equal(transfer.Amount))
+
+	failedEvent, err := store.MarkOutboxEventFailed(context.Background(), MarkOutboxEventFailedParams{
+		ID:            event.ID,
+		LastError:     pgtype.Text{String: "receiver unavailable", Valid: true},
+		NextAttemptAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), failedEvent.Attempts)
+	assert.Equal(t, "receiver unavailable", failedEvent.LastError.String)
+
+	publishedEvent, err := store.MarkOutboxEventPublished(context.Background(), event.ID)
+	assert.NoError(t, err)
+	assert.True(t, publishedEvent.PublishedAt.Valid)
+}
+
+func TestTransferTxOutboxEventRollback(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	// A failed transfer leaves no event behind
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        account1.Balance.Add(account1.OverdraftLimit).Add(decimal.NewFromInt(1)),
+	})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	var count int
+	err = connPool.QueryRow(
+		context.Background(),
+		"SELECT count(*) FROM outbox_events WHERE (payload->>'fromAccountId')::bigint = $1",
+		account1.ID).Scan(&count)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+}
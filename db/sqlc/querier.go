@@ -6,27 +6,122 @@ package db
 
 import (
 	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
 )
 
 type Querier interface {
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	BlockSession(ctx context.Context, id uuid.UUID) (Session, error)
+	// Rows locked by another replica are skipped, so each due
+	// schedule is picked by a single scheduler at a time.
+	// The time of the claim is returned for the next run to be
+	// computed with the same clock as the due runs
+	ClaimDueScheduledTransfer(ctx context.Context) (ClaimDueScheduledTransferRow, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAccountFreeze(ctx context.Context, arg CreateAccountFreezeParams) (AccountFreeze, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditEvent, error)
+	CreateBalanceAdjustment(ctx context.Context, arg CreateBalanceAdjustmentParams) (BalanceAdjustment, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateOutboxEvent(ctx context.Context, arg CreateOutboxEventParams) (OutboxEvent, error)
+	CreatePendingAuditEvent(ctx context.Context, arg CreatePendingAuditEventParams) (PendingAuditEvent, error)
+	// Nothing is inserted when start_at is not in the future,
+	// as the schedules are run with the clock of the database
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateScheduledTransferRun(ctx context.Context, arg CreateScheduledTransferRunParams) (ScheduledTransferRun, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
-	DeleteAccount(ctx context.Context, id int64) error
+	// Queues the event for the active endpoints of the given owners subscribed to its type
+	CreateWebhookDeliveries(ctx context.Context, arg CreateWebhookDeliveriesParams) error
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (WebhookEndpoint, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context) error
+	DeleteLimit(ctx context.Context, id int64) error
+	DeletePendingAuditEvents(ctx context.Context, ids []int64) error
 	DeleteUser(ctx context.Context, name string) error
+	DisableWebhookEndpoint(ctx context.Context, id int64) (WebhookEndpoint, error)
+	ExpireHolds(ctx context.Context) ([]Hold, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountHeldAmount(ctx context.Context, accountID int64) (decimal.Decimal, error)
+	// Reversals are refunds, they don't count against the limits of the sender
+	GetAccountOutgoingUsage(ctx context.Context, arg GetAccountOutgoingUsageParams) (GetAccountOutgoingUsageRow, error)
+	GetActiveAccountFreeze(ctx context.Context, accountID int64) (AccountFreeze, error)
+	GetBalanceAdjustment(ctx context.Context, id int64) (BalanceAdjustment, error)
+	GetCurrency(ctx context.Context, code Currency) (CurrencyInfo, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetLastAuditEvent(ctx context.Context) (AuditEvent, error)
+	GetLimit(ctx context.Context, id int64) (Limit, error)
+	GetOutboxEvent(ctx context.Context, id int64) (OutboxEvent, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetSystemAccount(ctx context.Context, currency Currency) (Account, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
 	GetUser(ctx context.Context, name string) (User, error)
-	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	// Same as GetAccountOutgoingUsage, over all the accounts of the owner in the currency
+	GetUserOutgoingUsage(ctx context.Context, arg GetUserOutgoingUsageParams) (GetUserOutgoingUsageRow, error)
+	GetWebhookEndpoint(ctx context.Context, id int64) (WebhookEndpoint, error)
+	// The leased events are hidden from the other relays until leased_until,
+	// they are published again if the relay stops before marking them
+	LeaseOutboxEvents(ctx context.Context, arg LeaseOutboxEventsParams) ([]OutboxEvent, error)
+	// The leased deliveries are hidden from the other dispatchers until leased_until
+	LeaseWebhookDeliveries(ctx context.Context, arg LeaseWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListAccountBalanceAdjustments(ctx context.Context, arg ListAccountBalanceAdjustmentsParams) ([]BalanceAdjustment, error)
+	ListAccountBalances(ctx context.Context, arg ListAccountBalancesParams) ([]ListAccountBalancesRow, error)
+	ListAccountEntries(ctx context.Context, arg ListAccountEntriesParams) ([]Entry, error)
+	ListAccountFreezes(ctx context.Context, arg ListAccountFreezesParams) ([]AccountFreeze, error)
+	ListAccountHolds(ctx context.Context, arg ListAccountHoldsParams) ([]Hold, error)
+	ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error)
+	ListAccountsByOwner(ctx context.Context, arg ListAccountsByOwnerParams) ([]Account, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error)
+	ListAuditEventsAfter(ctx context.Context, arg ListAuditEventsAfterParams) ([]AuditEvent, error)
+	ListCurrencies(ctx context.Context) ([]CurrencyInfo, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	ListLimits(ctx context.Context, arg ListLimitsParams) ([]Limit, error)
+	// Every entry references its transfer, or is referenced by its balance adjustment
+	ListOrphanEntries(ctx context.Context, arg ListOrphanEntriesParams) ([]Entry, error)
+	ListOrphanTransfers(ctx context.Context, arg ListOrphanTransfersParams) ([]Transfer, error)
+	ListPendingAuditEvents(ctx context.Context, limit int32) ([]PendingAuditEvent, error)
+	ListScheduledTransferRuns(ctx context.Context, arg ListScheduledTransferRunsParams) ([]ScheduledTransferRun, error)
+	ListScheduledTransfersByOwner(ctx context.Context, arg ListScheduledTransfersByOwnerParams) ([]ScheduledTransfer, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
+	// Limits of the account and of its owner in the currency of the account
+	ListTransferLimits(ctx context.Context, arg ListTransferLimitsParams) ([]Limit, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
-	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhookEndpointsByOwner(ctx context.Context, arg ListWebhookEndpointsByOwnerParams) ([]WebhookEndpoint, error)
+	// Serializes the chaining of the pending events until the end of the transaction,
+	// so that each event is chained to the last committed one
+	LockAuditChain(ctx context.Context) error
+	LockIdempotencyKey(ctx context.Context, arg LockIdempotencyKeyParams) error
+	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) (OutboxEvent, error)
+	MarkOutboxEventPublished(ctx context.Context, id int64) (OutboxEvent, error)
+	ReleaseAccountFreeze(ctx context.Context, arg ReleaseAccountFreezeParams) (AccountFreeze, error)
+	SetHoldTransfer(ctx context.Context, arg SetHoldTransferParams) (Hold, error)
+	SettleHold(ctx context.Context, arg SettleHoldParams) (Hold, error)
+	SumAccountEntries(ctx context.Context, accountID int64) (decimal.Decimal, error)
+	SumAccountEntriesAfter(ctx context.Context, arg SumAccountEntriesAfterParams) (decimal.Decimal, error)
+	// Reversals are debited from the to account of the transfer they reverse,
+	// so their amounts are in the currency of its to_amount, and their to_amounts
+	// in the currency of its amount
+	SumTransferReversals(ctx context.Context, reversalOf pgtype.Int8) (SumTransferReversalsRow, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error)
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (Account, error)
+	UpdateScheduledTransferSchedule(ctx context.Context, arg UpdateScheduledTransferScheduleParams) (ScheduledTransfer, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpdateWebhookDeliveryAttempt(ctx context.Context, arg UpdateWebhookDeliveryAttemptParams) (WebhookDelivery, error)
+	UpsertAccountLimit(ctx context.Context, arg UpsertAccountLimitParams) (Limit, error)
+	UpsertIdempotencyKey(ctx context.Context, arg UpsertIdempotencyKeyParams) (IdempotencyKey, error)
+	UpsertUserLimit(ctx context.Context, arg UpsertUserLimitParams) (Limit, error)
 }
 
 var _ Querier = (*Queries)(nil)
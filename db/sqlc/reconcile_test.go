@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListAccountBalancesAndRepairBalanceTx(t *testing.T) {
+	store := NewStore(connPool)
+
+	// Random accounts are created with a balance but without entries
+	account := createRandomAccount(t)
+
+	rows, err := store.ListAccountBalances(context.Background(), ListAccountBalancesParams{
+		AfterID:    account.ID - 1,
+		LimitCount: 1,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, account.ID, rows[0].ID)
+	assert.True(t, account.Balance.Equal(rows[0].Balance))
+	assert.True(t, rows[0].EntriesTotal.IsZero())
+
+	_, err = store.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: account.ID,
+		Amount:    decimal.NewFromInt(7),
+	})
+	assert.NoError(t, err)
+
+	result, err := store.RepairBalanceTx(context.Background(), account.ID)
+	assert.NoError(t, err)
+	assert.True(t, result.Repaired)
+	assert.True(t, account.Balance.Equal(result.PreviousBalance))
+	assert.True(t, decimal.NewFromInt(7).Equal(result.Account.Balance))
+
+	// Nothing to do the second time
+	result, err = store.RepairBalanceTx(context.Background(), account.ID)
+	assert.NoError(t, err)
+	assert.False(t, result.Repaired)
+	assert.True(t, decimal.NewFromInt(7).Equal(result.Account.Balance))
+}
+
+func TestListOrphanEntriesAndTransfers(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	first, err := store.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: account1.ID,
+		Amount:    decimal.NewFromInt(-1),
+	})
+	assert.NoError(t, err)
+
+	// Entries and transfer of a transfer transaction are not orphans
+	transferResult, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.NewFromInt(1),
+	})
+	assert.NoError(t, err)
+
+	// A transfer without entries is an orphan
+	orphanTransfer, err := store.CreateTransfer(context.Background(), CreateTransferParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        decimal.NewFromInt(1),
+		ToAmount:      decimal.NewFromInt(1),
+		ExchangeRate:  decimal.NewFromInt(1),
+		RateTimestamp: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	assert.NoError(t, err)
+
+	entries, err := store.ListOrphanEntries(context.Background(), ListOrphanEntriesParams{
+		AfterID:    first.ID - 1,
+		LimitCount: 1000,
+	})
+	assert.NoError(t, err)
+
+	orphanEntryIDs := map[int64]bool{}
+	for _, entry := range entries {
+		orphanEntryIDs[entry.ID] = true
+	}
+	assert.True(t, orphanEntryIDs[first.ID])
+	assert.False(t, orphanEntryIDs[transferResult.FromEntry.ID])
+	assert.False(t, orphanEntryIDs[transferResult.ToEntry.ID])
+	assert.Equal(t, transferResult.Transfer.ID, transferResult.FromEntry.TransferID.Int64)
+	assert.Equal(t, transferResult.Transfer.ID, transferResult.ToEntry.TransferID.Int64)
+
+	transfers, err := store.ListOrphanTransfers(context.Background(), ListOrphanTransfersParams{
+		AfterID:    transferResult.Transfer.ID - 1,
+		LimitCount: 1000,
+	})
+	assert.NoError(t, err)
+
+	orphanTransferIDs := map[int64]bool{}
+	for _, transfer := range transfers {
+		orphanTransferIDs[transfer.ID] = true
+	}
+	assert.False(t, orphanTransferIDs[transferResult.Transfer.ID])
+	assert.True(t, orphanTransferIDs[orphanTransfer.ID])
+}
+
+func TestListOrphanEntriesAdjustments(t *testing.T) {
+	store := NewStore(connPool)
+
+	admin := createRandomAdmin(t)
+	account := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	systemAccount, err := store.GetSystemAccount(context.Background(), CurrencyUSD)
+	assert.NoError(t, err)
+
+	// Balancing entry booked by the system accounts migration, recorded as an opening balance
+	openingEntry, err := store.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: systemAccount.ID,
+		Amount:    decimal.NewFromInt(-3),
+	})
+	assert.NoError(t, err)
+
+	_, err = store.CreateBalanceAdjustment(context.Background(), CreateBalanceAdjustmentParams{
+		AccountID:  systemAccount.ID,
+		EntryID:    openingEntry.ID,
+		Amount:     openingEntry.Amount,
+		ReasonCode: "opening_balance",
+		Note:       utils.RandomString(12),
+		Operator:   "system",
+	})
+	assert.NoError(t, err)
+
+	// Both entries of an adjustment are referenced by it
+	result, err := store.AdjustBalanceTx(context.Background(), AdjustBalanceTxParams{
+		AccountID:  account.ID,
+		Amount:     decimal.NewFromInt(2),
+		ReasonCode: "correction",
+		Note:       utils.RandomString(12),
+		Operator:   admin.Name,
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.Adjustment.CounterEntryID.Valid)
+
+	// An entry without a transfer or an adjustment is an orphan
+	orphanEntry, err := store.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: account.ID,
+		Amount:    decimal.NewFromInt(1),
+	})
+	assert.NoError(t, err)
+
+	entries, err := store.ListOrphanEntries(context.Background(), ListOrphanEntriesParams{
+		AfterID:    openingEntry.ID - 1,
+		LimitCount: 1000,
+	})
+	assert.NoError(t, err)
+
+	orphanEntryIDs := map[int64]bool{}
+	for _, entry := range entries {
+		orphanEntryIDs[entry.ID] = true
+	}
+	assert.False(t, orphanEntryIDs[openingEntry.ID])
+	assert.False(t, orphanEntryIDs[result.Entry.ID])
+	assert.False(t, orphanEntryIDs[result.Adjustment.CounterEntryID.Int64])
+	assert.True(t, orphanEntryIDs[orphanEntry.ID])
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// RepairBalanceTxResult contains the out parameters of the balance repair transaction
+type RepairBalanceTxResult struct {
+	Account         Account         `json:"account"`
+	PreviousBalance decimal.Decimal `json:"previous_balance"`
+	Repaired        bool            `json:"repaired"`
+}
+
+// RepairBalanceTx resets the balance of an account to the sum of its entries,
+// which are the source of truth. It locks the account first, so that the sum
+// is not taken in the middle of a transfer. Nothing is written when the balance
+// already matches.
+func (store *SqlStore) RepairBalanceTx(ctx context.Context, accountID int64) (RepairBalanceTxResult, error) {
+	var result RepairBalanceTxResult
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, accountID)
+		if err != nil {
+			return err
+		}
+
+		result.Account = account
+		result.PreviousBalance = account.Balance
+
+		total, err := q.SumAccountEntries(ctx, accountID)
+		if err != nil {
+			return err
+		}
+
+		drift := total.Sub(account.Balance)
+		if drift.IsZero() {
+			return nil
+		}
+
+		result.Account, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{
+			ID:     accountID,
+			Amount: drift,
+		})
+		result.Repaired = err == nil
+		return err
+	})
+
+	return result, txErr
+}
@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// ErrReversalOfReversal is returned when reversing a transfer which is itself a reversal
+	ErrReversalOfReversal = errors.New("a reversal cannot be reversed")
+
+	// ErrInvalidReversalAmount is returned when a reversal is made for a negative amount
+	ErrInvalidReversalAmount = errors.New("reversal amount must be positive")
+)
+
+// ErrReversalExceedsRemaining is returned when a reversal is larger than the amount of the transfer left to reverse
+var ErrReversalExceedsRemaining = errors.New("reversal amount exceeds the amount left to reverse")
+
+// ReversalExceedsRemainingError describes a reversal rejected because the transfer
+// was already reversed, fully or partially. Remaining is in the currency of the to account of the transfer.
+type ReversalExceedsRemainingError struct {
+	TransferID int64
+	Remaining  decimal.Decimal
+	Requested  decimal.Decimal
+}
+
+func (e *ReversalExceedsRemainingError) Error() string {
+	return fmt.Sprintf(
+		"transfer [%d] has %s left to reverse, requested: %s",
+		e.TransferID,
+		e.Remaining,
+		e.Requested)
+}
+
+func (e *ReversalExceedsRemainingError) Unwrap() error {
+	return ErrReversalExceedsRemaining
+}
+
+// ReverseTransferTxParams contains the input parameters of the reverse transfer transaction.
+// Amount is in the currency of the to account of the transfer, a zero Amount reverses
+// everything left to reverse. The reversal is checked against the transfer limits of the
+// to account, unless SkipLimits is set, e.g. for the reversals made by an admin.
+type ReverseTransferTxParams struct {
+	TransferID int64           `json:"transfer_id"`
+	Amount     decimal.Decimal `json:"amount"`
+	SkipLimits bool            `json:"skip_limits"`
+}
+
+// ReverseTransferTxResult contains the out parameters of the reverse transfer transaction.
+// Remaining is the amount of the original transfer left to reverse after this reversal.
+type ReverseTransferTxResult struct {
+	TransferTxResult
+	Remaining decimal.Decimal `json:"remaining"`
+}
+
+// ReverseTransferTx creates a compensating transfer moving amount back from the to account
+// of a transfer to its from account, linked to the original transfer by its reversal_of.
+// The original transfer is locked, so concurrent reversals of the same transfer are serialized
+// and together never exceed its amount. The amount is converted back at the inverse of the original
+// exchange rate, rounded to the minor unit of the from currency, and the reversal of everything left
+// refunds exactly what is left of the original amount, so a full reversal refunds the original amount.
+func (store *SqlStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+
+	if arg.Amount.IsNegative() {
+		return result, ErrInvalidReversalAmount
+	}
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			return err
+		}
+
+		if original.ReversalOf.Valid {
+			return ErrReversalOfReversal
+		}
+
+		reversals, err := q.SumTransferReversals(ctx, pgtype.Int8{Int64: original.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+
+		remaining := original.ToAmount.Sub(reversals.Total)
+		amount := arg.Amount
+		if amount.IsZero() {
+			amount = remaining
+		}
+
+		if !remaining.IsPositive() || amount.GreaterThan(remaining) {
+			return &ReversalExceedsRemainingError{
+				TransferID: original.ID,
+				Remaining:  remaining,
+				Requested:  amount,
+			}
+		}
+
+		refund, err := reversalRefund(ctx, q, original, reversals.Refunded, amount, remaining)
+		if err != nil {
+			return err
+		}
+
+		result.TransferTxResult, err = store.transfer(ctx, q, TransferTxParams{
+			FromAccountId: original.ToAccountID,
+			ToAccountId:   original.FromAccountID,
+			Amount:        amount,
+			ExchangeRate:  decimal.NewFromInt(1).Div(original.ExchangeRate),
+			RateTimestamp: original.RateTimestamp.Time,
+			ReversalOf:    original.ID,
+			ToAmount:      refund,
+			SkipLimits:    arg.SkipLimits,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Remaining = remaining.Sub(amount)
+		return nil
+	})
+
+	return result, txErr
+}
+
+// reversalRefund returns the amount refunded to the from account of original when amount is reversed,
+// in the currency of original.Amount. refunded is what the previous reversals refunded already.
+func reversalRefund(
+	ctx context.Context,
+	q *Queries,
+	original Transfer,
+	refunded decimal.Decimal,
+	amount decimal.Decimal,
+	remaining decimal.Decimal) (decimal.Decimal, error) {
+
+	leftToRefund := original.Amount.Sub(refunded)
+	if amount.Equal(remaining) {
+		return leftToRefund, nil
+	}
+
+	fromAccount, err := q.GetAccount(ctx, original.FromAccountID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	fromCurrency, err := q.GetCurrency(ctx, fromAccount.Currency)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	refund := amount.DivRound(original.ExchangeRate, int32(fromCurrency.Exponent))
+	return decimal.Min(refund, leftToRefund), nil
+}
@@ -0,0 +1,252 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func createRandomTransferTx(t *testing.T, amount decimal.Decimal) (TransferTxResult, Account, Account) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        amount,
+	})
+	assert.NoError(t, err)
+
+	return result, account1, account2
+}
+
+func TestReverseTransferTxPartial(t *testing.T) {
+	store := NewStore(connPool)
+	transfer, account1, account2 := createRandomTransferTx(t, decimal.NewFromInt(10))
+
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     decimal.NewFromInt(4),
+	})
+	assert.NoError(t, err)
+
+	reversal := result.Transfer
+	assert.Equal(t, account2.ID, reversal.FromAccountID)
+	assert.Equal(t, account1.ID, reversal.ToAccountID)
+	assert.True(t, decimal.NewFromInt(4).Equal(reversal.Amount))
+	assert.True(t, reversal.ReversalOf.Valid)
+	assert.Equal(t, transfer.Transfer.ID, reversal.ReversalOf.Int64)
+	assert.True(t, decimal.NewFromInt(6).Equal(result.Remaining))
+
+	// A zero amount reverses everything left
+	result, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+	})
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(6).Equal(result.Transfer.Amount))
+	assert.True(t, result.Remaining.IsZero())
+
+	// Both accounts are back to their balance before the transfer
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	assert.NoError(t, err)
+	assert.True(t, account1.Balance.Equal(updatedAccount1.Balance))
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	assert.NoError(t, err)
+	assert.True(t, account2.Balance.Equal(updatedAccount2.Balance))
+
+	// Nothing is left to reverse
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     decimal.NewFromInt(1),
+	})
+
+	var exceedsErr *ReversalExceedsRemainingError
+	assert.ErrorAs(t, err, &exceedsErr)
+	assert.True(t, exceedsErr.Remaining.IsZero())
+}
+
+func TestReverseTransferTxCrossCurrency(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyEUR)
+
+	// 10 USD are converted to 3.33 EUR, converting them back at the inverse rate gives 9.99 USD
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.NewFromInt(10),
+		ExchangeRate:  decimal.RequireFromString("0.333333"),
+	})
+	assert.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("3.33").Equal(transfer.Transfer.ToAmount))
+
+	// A partial reversal is converted at the inverse of the original rate
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     decimal.RequireFromString("1.11"),
+	})
+	assert.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("3.33").Equal(result.Transfer.ToAmount))
+	assert.True(t, decimal.NewFromInt(1).Div(transfer.Transfer.ExchangeRate).Equal(result.Transfer.ExchangeRate))
+
+	// The full reversal of what is left refunds exactly what is left of the original amount
+	result, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+	})
+	assert.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("6.67").Equal(result.Transfer.ToAmount))
+	assert.True(t, result.Remaining.IsZero())
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	assert.NoError(t, err)
+	assert.True(t, account1.Balance.Equal(updatedAccount1.Balance))
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	assert.NoError(t, err)
+	assert.True(t, account2.Balance.Equal(updatedAccount2.Balance))
+}
+
+func TestReverseTransferTxCrossCurrencyFull(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyEUR)
+
+	transfer, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.NewFromInt(10),
+		ExchangeRate:  decimal.RequireFromString("0.333333"),
+	})
+	assert.NoError(t, err)
+
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+	})
+	assert.NoError(t, err)
+	assert.True(t, transfer.Transfer.Amount.Equal(result.Transfer.ToAmount))
+	assert.True(t, transfer.Transfer.ToAmount.Equal(result.Transfer.Amount))
+}
+
+func TestReverseTransferTxExceedsRemaining(t *testing.T) {
+	store := NewStore(connPool)
+	transfer, _, _ := createRandomTransferTx(t, decimal.NewFromInt(10))
+
+	_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     decimal.NewFromInt(11),
+	})
+	assert.ErrorIs(t, err, ErrReversalExceedsRemaining)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     decimal.NewFromInt(-1),
+	})
+	assert.ErrorIs(t, err, ErrInvalidReversalAmount)
+}
+
+func TestReverseTransferTxOfReversal(t *testing.T) {
+	store := NewStore(connPool)
+	transfer, _, _ := createRandomTransferTx(t, decimal.NewFromInt(10))
+
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     decimal.NewFromInt(5),
+	})
+	assert.NoError(t, err)
+
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: result.Transfer.ID,
+	})
+	assert.ErrorIs(t, err, ErrReversalOfReversal)
+}
+
+func TestReverseTransferTxNotFound(t *testing.T) {
+	store := NewStore(connPool)
+
+	_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: -1,
+	})
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestReverseTransferTxConcurrent(t *testing.T) {
+	store := NewStore(connPool)
+	transfer, _, account2 := createRandomTransferTx(t, decimal.NewFromInt(10))
+
+	// Reverse 3 each time concurrently, only 3 reversals fit in the transfer
+	n := 5
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransferID: transfer.Transfer.ID,
+				Amount:     decimal.NewFromInt(3),
+			})
+
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrReversalExceedsRemaining):
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	assert.Equal(t, 3, succeeded)
+
+	reversals, err := testQueries.SumTransferReversals(context.Background(), pgtype.Int8{Int64: transfer.Transfer.ID, Valid: true})
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(9).Equal(reversals.Total))
+	assert.True(t, decimal.NewFromInt(9).Equal(reversals.Refunded))
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	assert.NoError(t, err)
+	assert.True(t, account2.Balance.Add(decimal.NewFromInt(1)).Equal(updatedAccount2.Balance))
+}
+
+func TestReverseTransferTxLimits(t *testing.T) {
+	store := NewStore(connPool)
+	transfer, _, account2 := createRandomTransferTx(t, decimal.NewFromInt(10))
+
+	_, err := testQueries.UpsertAccountLimit(context.Background(), UpsertAccountLimitParams{
+		AccountID:         account2.ID,
+		Currency:          account2.Currency,
+		MaxSingleTransfer: decimal.NewNullDecimal(decimal.NewFromInt(5)),
+	})
+	assert.NoError(t, err)
+
+	// The reversal is sent by the to account of the transfer, within its limits
+	_, err = store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     decimal.NewFromInt(6),
+	})
+
+	var limitErr *LimitExceededError
+	if assert.ErrorAs(t, err, &limitErr) {
+		assert.Equal(t, LimitMaxSingleTransfer, limitErr.Limit)
+	}
+
+	result, err := store.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     decimal.NewFromInt(6),
+		SkipLimits: true,
+	})
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(4).Equal(result.Remaining))
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrInvalidScheduleTransition is returned when a scheduled transfer cannot move to the requested status
+var ErrInvalidScheduleTransition = errors.New("invalid scheduled transfer status transition")
+
+// ScheduleTransitionError describes a status change rejected because of the current status of the scheduled transfer
+type ScheduleTransitionError struct {
+	ScheduledTransferID int64
+	From                ScheduledTransferStatus
+	To                  ScheduledTransferStatus
+}
+
+func (e *ScheduleTransitionError) Error() string {
+	return fmt.Sprintf(
+		"scheduled transfer [%d] cannot move from %s to %s",
+		e.ScheduledTransferID,
+		e.From,
+		e.To)
+}
+
+func (e *ScheduleTransitionError) Unwrap() error {
+	return ErrInvalidScheduleTransition
+}
+
+// scheduleTransitions lists the statuses a scheduled transfer can move to from each status.
+// Completed and cancelled schedules are final.
+var scheduleTransitions = map[ScheduledTransferStatus][]ScheduledTransferStatus{
+	ScheduledTransferStatusActive: {ScheduledTransferStatusPaused, ScheduledTransferStatusCancelled},
+	ScheduledTransferStatusPaused: {ScheduledTransferStatusActive, ScheduledTransferStatusCancelled},
+}
+
+// SetScheduledTransferStatusTxParams contains the input parameters of the set scheduled transfer status transaction
+type SetScheduledTransferStatusTxParams struct {
+	ID     int64                   `json:"id"`
+	Status ScheduledTransferStatus `json:"status"`
+}
+
+// RunScheduledTransferTxResult contains the out parameters of the run scheduled transfer transaction.
+// Transfer is empty when the run failed.
+type RunScheduledTransferTxResult struct {
+	ScheduledTransfer ScheduledTransfer    `json:"scheduled_transfer"`
+	Run               ScheduledTransferRun `json:"run"`
+	Transfer          TransferTxResult     `json:"transfer"`
+}
+
+// SetScheduledTransferStatusTx pauses, resumes or cancels a scheduled transfer.
+// The schedule is locked, so it cannot change while the scheduler runs it.
+// A resumed schedule keeps its next run, occurrences missed while paused are skipped on that run.
+func (store *SqlStore) SetScheduledTransferStatusTx(
+	ctx context.Context,
+	arg SetScheduledTransferStatusTxParams) (ScheduledTransfer, error) {
+
+	var result ScheduledTransfer
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		schedule, err := q.GetScheduledTransferForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		if !canMoveSchedule(schedule.Status, arg.Status) {
+			return &ScheduleTransitionError{
+				ScheduledTransferID: schedule.ID,
+				From:                schedule.Status,
+				To:                  arg.Status,
+			}
+		}
+
+		result, err = q.UpdateScheduledTransferSchedule(ctx, UpdateScheduledTransferScheduleParams{
+			ID:        schedule.ID,
+			Status:    arg.Status,
+			NextRunAt: schedule.NextRunAt,
+		})
+		return err
+	})
+
+	return result, txErr
+}
+
+// RunDueScheduledTransferTx executes the next due scheduled transfer, if any,
+// and returns pgx.ErrNoRows when none is due.
+// The schedule is claimed with SKIP LOCKED and the run is recorded and the schedule moved to
+// its next occurrence in the same transaction as the transfer, so each occurrence is executed
+// exactly once even with several schedulers. A failed transfer is recorded on the run and
+// is not retried, the schedule still moves on, unless one of its accounts is closed,
+// in which case the schedule is cancelled.
+func (store *SqlStore) RunDueScheduledTransferTx(ctx context.Context) (RunScheduledTransferTxResult, error) {
+	var result RunScheduledTransferTxResult
+
+	txErr := store.execTx(ctx, func(q *Queries) error {
+		claimed, err := q.ClaimDueScheduledTransfer(ctx)
+		if err != nil {
+			return err
+		}
+
+		schedule := claimed.ScheduledTransfer
+
+		// The transfer runs in a savepoint so that its failure can be recorded
+		var runErr error
+		transferErr := execSavepoint(ctx, q, func(q *Queries) error {
+			result.Transfer, runErr = store.transfer(ctx, q, TransferTxParams{
+				FromAccountId: schedule.FromAccountID,
+				ToAccountId:   schedule.ToAccountID,
+				Amount:        schedule.Amount,
+			})
+			return runErr
+		})
+		if transferErr != nil && (runErr == nil || isRetryableTxError(transferErr)) {
+			// The savepoint itself failed or the whole transaction has to be retried
+			return transferErr
+		}
+
+		runArg := CreateScheduledTransferRunParams{
+			ScheduledTransferID: schedule.ID,
+			ScheduledFor:        schedule.NextRunAt,
+		}
+		if runErr != nil {
+			result.Transfer = TransferTxResult{}
+			runArg.Error = pgtype.Text{String: runErr.Error(), Valid: true}
+		} else {
+			runArg.TransferID = pgtype.Int8{Int64: result.Transfer.Transfer.ID, Valid: true}
+		}
+
+		result.Run, err = q.CreateScheduledTransferRun(ctx, runArg)
+		if err != nil {
+			return err
+		}
+
+		status := ScheduledTransferStatusActive
+		// The next run is after the time of the claim, on the clock of the database
+		// which tells when runs are due, and not on the clock of the application
+		nextRunAt, ok := nextScheduledRun(schedule, claimed.ClaimedAt.Time)
+		if !ok {
+			status = ScheduledTransferStatusCompleted
+			nextRunAt = schedule.NextRunAt.Time
+		}
+
+		// A closed account stays closed, every later run would fail the same way
+		if errors.Is(runErr, ErrAccountClosed) {
+			status = ScheduledTransferStatusCancelled
+			nextRunAt = schedule.NextRunAt.Time
+		}
+
+		result.ScheduledTransfer, err = q.UpdateScheduledTransferSchedule(ctx, UpdateScheduledTransferScheduleParams{
+			ID:        schedule.ID,
+			Status:    status,
+			NextRunAt: pgtype.Timestamptz{Time: nextRunAt, Valid: true},
+		})
+		return err
+	})
+
+	return result, txErr
+}
+
+func canMoveSchedule(from ScheduledTransferStatus, to ScheduledTransferStatus) bool {
+	for _, status := range scheduleTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nextScheduledRun returns the first occurrence of the schedule after the given time,
+// skipping the occurrences in between. It returns false when no occurrence is left.
+func nextScheduledRun(schedule ScheduledTransfer, after time.Time) (time.Time, bool) {
+	if schedule.Frequency == ScheduleFrequencyOnce {
+		return time.Time{}, false
+	}
+
+	next := schedule.NextRunAt.Time
+	for !next.After(after) {
+		next = addScheduleInterval(schedule, next)
+	}
+
+	if schedule.EndAt.Valid && next.After(schedule.EndAt.Time) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+// addScheduleInterval returns the occurrence of the schedule following t.
+// Monthly occurrences stay on the day of month of the start, or on the
+// last day of shorter months.
+func addScheduleInterval(schedule ScheduledTransfer, t time.Time) time.Time {
+	count := max(int(schedule.IntervalCount), 1)
+
+	switch schedule.Frequency {
+	case ScheduleFrequencyDaily:
+		return t.AddDate(0, 0, count)
+	case ScheduleFrequencyWeekly:
+		return t.AddDate(0, 0, 7*count)
+	default:
+		t = t.UTC()
+		firstOfMonth := time.Date(t.Year(), t.Month()+time.Month(count), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+		lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+		day := min(schedule.StartAt.Time.UTC().Day(), lastDay)
+		return firstOfMonth.AddDate(0, 0, day-1)
+	}
+}
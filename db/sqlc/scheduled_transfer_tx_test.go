@@ -0,0 +1,266 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func createDueScheduledTransfer(
+	t *testing.T,
+	fromAccount Account,
+	toAccount Account,
+	amount decimal.Decimal,
+	frequency ScheduleFrequency) ScheduledTransfer {
+
+	schedule, err := testQueries.CreateScheduledTransfer(context.Background(), CreateScheduledTransferParams{
+		Owner:         fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        amount,
+		Frequency:     frequency,
+		IntervalCount: 1,
+		StartAt:       pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ScheduledTransferStatusActive, schedule.Status)
+	assert.Equal(t, schedule.StartAt, schedule.NextRunAt)
+
+	// Schedules can only start in the future, the first run is moved back to make it due
+	schedule, err = testQueries.UpdateScheduledTransferSchedule(context.Background(), UpdateScheduledTransferScheduleParams{
+		ID:        schedule.ID,
+		Status:    ScheduledTransferStatusActive,
+		NextRunAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+	})
+	assert.NoError(t, err)
+
+	return schedule
+}
+
+// runScheduledTransfer runs the due scheduled transfers until the given one is run
+func runScheduledTransfer(t *testing.T, store Store, scheduleID int64) RunScheduledTransferTxResult {
+	for {
+		result, err := store.RunDueScheduledTransferTx(context.Background())
+		if !assert.NoError(t, err) {
+			return result
+		}
+
+		if result.ScheduledTransfer.ID == scheduleID {
+			return result
+		}
+	}
+}
+
+func TestRunDueScheduledTransferTx(t *testing.T) {
+	store := NewStore(connPool)
+
+	fromAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	toAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	schedule := createDueScheduledTransfer(t, fromAccount, toAccount, decimal.NewFromInt(10), ScheduleFrequencyDaily)
+
+	result := runScheduledTransfer(t, store, schedule.ID)
+	assert.False(t, result.Run.Error.Valid)
+	assert.Equal(t, result.Transfer.Transfer.ID, result.Run.TransferID.Int64)
+	assert.Equal(t, schedule.NextRunAt.Time.Unix(), result.Run.ScheduledFor.Time.Unix())
+	assert.True(t, decimal.NewFromInt(10).Equal(result.Transfer.Transfer.Amount))
+	assert.True(t, fromAccount.Balance.Sub(decimal.NewFromInt(10)).Equal(result.Transfer.FromAccount.Balance))
+
+	// The schedule moves to its next occurrence
+	assert.Equal(t, ScheduledTransferStatusActive, result.ScheduledTransfer.Status)
+	assert.WithinDuration(t, schedule.NextRunAt.Time.AddDate(0, 0, 1), result.ScheduledTransfer.NextRunAt.Time, time.Second)
+}
+
+func TestClaimDueScheduledTransfer(t *testing.T) {
+	fromAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	toAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	createDueScheduledTransfer(t, fromAccount, toAccount, decimal.NewFromInt(1), ScheduleFrequencyDaily)
+
+	// The claim tells the time of the database it was made at
+	claimed, err := testQueries.ClaimDueScheduledTransfer(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, claimed.ClaimedAt.Valid)
+	assert.False(t, claimed.ScheduledTransfer.NextRunAt.Time.After(claimed.ClaimedAt.Time))
+}
+
+func TestRunDueScheduledTransferTxFailure(t *testing.T) {
+	store := NewStore(connPool)
+
+	fromAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	toAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	amount := fromAccount.Balance.Add(fromAccount.OverdraftLimit).Add(decimal.NewFromInt(1))
+	schedule := createDueScheduledTransfer(t, fromAccount, toAccount, amount, ScheduleFrequencyOnce)
+
+	// The failure is recorded and the one-off schedule is completed
+	result := runScheduledTransfer(t, store, schedule.ID)
+	assert.True(t, result.Run.Error.Valid)
+	assert.Contains(t, result.Run.Error.String, ErrInsufficientFunds.Error())
+	assert.False(t, result.Run.TransferID.Valid)
+	assert.Equal(t, ScheduledTransferStatusCompleted, result.ScheduledTransfer.Status)
+
+	updatedAccount, err := store.GetAccount(context.Background(), fromAccount.ID)
+	assert.NoError(t, err)
+	assert.True(t, fromAccount.Balance.Equal(updatedAccount.Balance))
+}
+
+func TestRunDueScheduledTransferTxClosedAccount(t *testing.T) {
+	store := NewStore(connPool)
+
+	fromAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	toAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	schedule := createDueScheduledTransfer(t, fromAccount, toAccount, decimal.NewFromInt(1), ScheduleFrequencyDaily)
+
+	_, err := testQueries.UpdateAccountStatus(context.Background(), UpdateAccountStatusParams{
+		ID:     fromAccount.ID,
+		Status: AccountStatusClosed,
+	})
+	assert.NoError(t, err)
+
+	// The failure is recorded once and the schedule is cancelled instead of moving on
+	result := runScheduledTransfer(t, store, schedule.ID)
+	assert.True(t, result.Run.Error.Valid)
+	assert.Contains(t, result.Run.Error.String, "closed")
+	assert.Equal(t, ScheduledTransferStatusCancelled, result.ScheduledTransfer.Status)
+	assert.Equal(t, schedule.NextRunAt.Time.Unix(), result.ScheduledTransfer.NextRunAt.Time.Unix())
+}
+
+func TestCreateScheduledTransferStartInPast(t *testing.T) {
+	fromAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	toAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	_, err := testQueries.CreateScheduledTransfer(context.Background(), CreateScheduledTransferParams{
+		Owner:         fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        decimal.NewFromInt(1),
+		Frequency:     ScheduleFrequencyOnce,
+		IntervalCount: 1,
+		StartAt:       pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+	})
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestRunDueScheduledTransferTxConcurrent(t *testing.T) {
+	store := NewStore(connPool)
+
+	fromAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	toAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	schedule := createDueScheduledTransfer(t, fromAccount, toAccount, decimal.NewFromInt(1), ScheduleFrequencyOnce)
+
+	// Schedulers racing on the due transfers never run an occurrence twice
+	n := 5
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			var err error
+			for err == nil {
+				_, err = store.RunDueScheduledTransferTx(context.Background())
+			}
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+		assert.True(t, errors.Is(err, pgx.ErrNoRows))
+	}
+
+	runs, err := store.ListScheduledTransferRuns(context.Background(), ListScheduledTransferRunsParams{
+		ScheduledTransferID: schedule.ID,
+		Limit:               10,
+		Offset:              0,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, runs, 1)
+
+	updatedAccount, err := store.GetAccount(context.Background(), fromAccount.ID)
+	assert.NoError(t, err)
+	assert.True(t, fromAccount.Balance.Sub(decimal.NewFromInt(1)).Equal(updatedAccount.Balance))
+}
+
+func TestSetScheduledTransferStatusTx(t *testing.T) {
+	store := NewStore(connPool)
+
+	fromAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	toAccount := createRandomAccountWithCurrency(t, CurrencyUSD)
+	schedule := createDueScheduledTransfer(t, fromAccount, toAccount, decimal.NewFromInt(1), ScheduleFrequencyWeekly)
+
+	for _, status := range []ScheduledTransferStatus{
+		ScheduledTransferStatusPaused,
+		ScheduledTransferStatusActive,
+		ScheduledTransferStatusCancelled,
+	} {
+		updatedSchedule, err := store.SetScheduledTransferStatusTx(context.Background(), SetScheduledTransferStatusTxParams{
+			ID:     schedule.ID,
+			Status: status,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, status, updatedSchedule.Status)
+		assert.Equal(t, schedule.NextRunAt, updatedSchedule.NextRunAt)
+	}
+
+	// A cancelled schedule is final
+	_, err := store.SetScheduledTransferStatusTx(context.Background(), SetScheduledTransferStatusTxParams{
+		ID:     schedule.ID,
+		Status: ScheduledTransferStatusActive,
+	})
+	assert.ErrorIs(t, err, ErrInvalidScheduleTransition)
+}
+
+func TestNextScheduledRun(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+	}
+
+	timestamptz := func(t time.Time) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: t, Valid: true}
+	}
+
+	monthly := ScheduledTransfer{
+		Frequency:     ScheduleFrequencyMonthly,
+		IntervalCount: 1,
+		StartAt:       timestamptz(date(2024, time.January, 31)),
+		NextRunAt:     timestamptz(date(2024, time.January, 31)),
+	}
+
+	// Monthly runs stay on the start day, or the last day of shorter months
+	next, ok := nextScheduledRun(monthly, date(2024, time.January, 31))
+	assert.True(t, ok)
+	assert.Equal(t, date(2024, time.February, 29), next)
+
+	monthly.NextRunAt = timestamptz(next)
+	next, ok = nextScheduledRun(monthly, next)
+	assert.True(t, ok)
+	assert.Equal(t, date(2024, time.March, 31), next)
+
+	// Missed occurrences are skipped
+	biweekly := ScheduledTransfer{
+		Frequency:     ScheduleFrequencyWeekly,
+		IntervalCount: 2,
+		StartAt:       timestamptz(date(2024, time.January, 1)),
+		NextRunAt:     timestamptz(date(2024, time.January, 1)),
+	}
+
+	next, ok = nextScheduledRun(biweekly, date(2024, time.February, 1))
+	assert.True(t, ok)
+	assert.Equal(t, date(2024, time.February, 12), next)
+
+	// No occurrence after the end
+	biweekly.EndAt = timestamptz(date(2024, time.February, 10))
+	_, ok = nextScheduledRun(biweekly, date(2024, time.February, 1))
+	assert.False(t, ok)
+
+	once := ScheduledTransfer{
+		Frequency: ScheduleFrequencyOnce,
+		StartAt:   timestamptz(date(2024, time.January, 1)),
+		NextRunAt: timestamptz(date(2024, time.January, 1)),
+	}
+
+	_, ok = nextScheduledRun(once, date(2024, time.January, 1))
+	assert.False(t, ok)
+}
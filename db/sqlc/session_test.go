@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+)
+
+func createRandomSession(t *testing.T) Session {
+	user := createRandomUser(t)
+
+	arg := CreateSessionParams{
+		ID:           uuid.New(),
+		Username:     user.Name,
+		RefreshToken: utils.RandomString(32),
+		UserAgent:    "test-agent",
+		ClientIp:     "127.0.0.1",
+		IsBlocked:    false,
+		ExpiresAt:    pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	}
+
+	session, err := testQueries.CreateSession(context.Background(), arg)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, session)
+
+	assert.Equal(t, arg.ID, session.ID)
+	assert.Equal(t, arg.Username, session.Username)
+	assert.Equal(t, arg.RefreshToken, session.RefreshToken)
+	assert.Equal(t, arg.UserAgent, session.UserAgent)
+	assert.Equal(t, arg.ClientIp, session.ClientIp)
+	assert.False(t, session.IsBlocked)
+	assert.WithinDuration(t, arg.ExpiresAt.Time, session.ExpiresAt.Time, time.Second)
+	assert.NotEmpty(t, session.CreatedAt)
+
+	return session
+}
+
+func TestCreateSession(t *testing.T) {
+	createRandomSession(t)
+}
+
+func TestGetSession(t *testing.T) {
+	session1 := createRandomSession(t)
+
+	session2, err := testQueries.GetSession(context.Background(), session1.ID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, session2)
+
+	assert.Equal(t, session1.ID, session2.ID)
+	assert.Equal(t, session1.Username, session2.Username)
+	assert.Equal(t, session1.RefreshToken, session2.RefreshToken)
+	assert.Equal(t, session1.IsBlocked, session2.IsBlocked)
+	assert.WithinDuration(t, session1.ExpiresAt.Time, session2.ExpiresAt.Time, time.Second)
+}
+
+func TestBlockSession(t *testing.T) {
+	session1 := createRandomSession(t)
+
+	session2, err := testQueries.BlockSession(context.Background(), session1.ID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, session2)
+
+	assert.Equal(t, session1.ID, session2.ID)
+	assert.True(t, session2.IsBlocked)
+}
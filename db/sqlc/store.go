@@ -3,25 +3,84 @@ package db
 import (
 	"context"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Store provides all functions to execute db queries and transactions
 type Store interface {
 	Querier
+	CreateUserTx(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateAccountTx(ctx context.Context, arg CreateAccountParams) (Account, error)
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	IdempotentTransferTx(ctx context.Context, arg IdempotentTransferTxParams) (IdempotentTransferTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	AccountStatementTx(ctx context.Context, arg AccountStatementTxParams) (AccountStatementTxResult, error)
+	AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error)
+	DepositTx(ctx context.Context, arg CashTxParams) (TransferTxResult, error)
+	WithdrawTx(ctx context.Context, arg CashTxParams) (TransferTxResult, error)
+	RepairBalanceTx(ctx context.Context, accountID int64) (RepairBalanceTxResult, error)
+	CloseAccountTx(ctx context.Context, accountID int64) (Account, error)
+	FreezeAccountTx(ctx context.Context, arg FreezeAccountTxParams) (AccountFreezeTxResult, error)
+	UnfreezeAccountTx(ctx context.Context, arg UnfreezeAccountTxParams) (AccountFreezeTxResult, error)
+	AuthorizeHold(ctx context.Context, arg AuthorizeHoldParams) (Hold, error)
+	CaptureHold(ctx context.Context, arg CaptureHoldParams) (CaptureHoldResult, error)
+	ReleaseHold(ctx context.Context, holdID int64) (Hold, error)
+	SetScheduledTransferStatusTx(ctx context.Context, arg SetScheduledTransferStatusTxParams) (ScheduledTransfer, error)
+	RunDueScheduledTransferTx(ctx context.Context) (RunScheduledTransferTxResult, error)
+	ChainAuditEventsTx(ctx context.Context, limit int32) (int, error)
+	VerifyAuditChain(ctx context.Context) (AuditChainResult, error)
 }
 
 // Store provides all functions to execute sql queries and transactions
 type SqlStore struct {
 	*Queries
-	connPool *pgxpool.Pool
+	connPool     *pgxpool.Pool
+	isoLevel     pgx.TxIsoLevel
+	maxTxRetries int
+
+	// frozenBlocksIncoming makes frozen accounts refuse incoming transfers too
+	frozenBlocksIncoming bool
+}
+
+// StoreOption configures optional behaviour of the store
+type StoreOption func(*SqlStore)
+
+// WithIsoLevel sets the isolation level used by the store transactions.
+// By default the isolation level configured on the database server is used.
+func WithIsoLevel(isoLevel pgx.TxIsoLevel) StoreOption {
+	return func(store *SqlStore) {
+		store.isoLevel = isoLevel
+	}
+}
+
+// WithMaxTxRetries sets how many times a transaction is retried
+// after a serialization failure, a deadlock or a fork of the audit chain
+func WithMaxTxRetries(maxTxRetries int) StoreOption {
+	return func(store *SqlStore) {
+		store.maxTxRetries = maxTxRetries
+	}
+}
+
+// WithFrozenAccountsBlockIncoming sets whether frozen accounts refuse incoming transfers.
+// By default a frozen account can still receive money, it only cannot send any.
+func WithFrozenAccountsBlockIncoming(block bool) StoreOption {
+	return func(store *SqlStore) {
+		store.frozenBlocksIncoming = block
+	}
 }
 
 // NewStore creates a new store
-func NewStore(connPool *pgxpool.Pool) Store {
-	return &SqlStore{
-		Queries:  New(connPool),
-		connPool: connPool,
+func NewStore(connPool *pgxpool.Pool, opts ...StoreOption) Store {
+	store := &SqlStore{
+		Queries:      New(connPool),
+		connPool:     connPool,
+		maxTxRetries: defaultMaxTxRetries,
 	}
+
+	for _, opt := range opts {
+		opt(store)
+	}
+
+	return store
 }
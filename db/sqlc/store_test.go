@@ -3,8 +3,11 @@ package db
 import (
 	"context"
 	"fmt"
+	"math"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
@@ -13,7 +16,7 @@ func TestTranferTx(t *testing.T) {
 	store := NewStore(connPool)
 
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
 	fmt.Println(">> before:", account1.Balance, account2.Balance)
 
 	amountToTransfer := decimal.NewFromInt32(10)
@@ -116,7 +119,7 @@ func TestTranferTxDeadlock(t *testing.T) {
 	store := NewStore(connPool)
 
 	account1 := createRandomAccount(t)
-	account2 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
 	fmt.Println(">> before:", account1.Balance, account2.Balance)
 
 	amountToTransfer := decimal.NewFromInt32(10)
@@ -167,3 +170,146 @@ func TestTranferTxDeadlock(t *testing.T) {
 	assert.Equal(t, account1.Balance, updatedAccount1.Balance)
 	assert.Equal(t, account2.Balance, updatedAccount2.Balance)
 }
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccountWithCurrency(t, account1.Currency)
+
+	// Transfer more than the balance of account1 without any overdraft
+	amountToTransfer := account1.Balance.Add(decimal.NewFromInt(1))
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        amountToTransfer,
+	})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	assert.Empty(t, result.Transfer)
+
+	var insufficientFundsErr *InsufficientFundsError
+	assert.ErrorAs(t, err, &insufficientFundsErr)
+	assert.Equal(t, account1.ID, insufficientFundsErr.AccountID)
+	assert.True(t, account1.Balance.Equal(insufficientFundsErr.Available))
+	assert.True(t, amountToTransfer.Equal(insufficientFundsErr.Requested))
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, account2.Balance, updatedAccount2.Balance)
+
+	// The same transfer succeeds once account1 has an overdraft limit
+	_, err = testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: decimal.NewFromInt(1),
+	})
+	assert.NoError(t, err)
+
+	result, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        amountToTransfer,
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.FromAccount.Balance.Equal(decimal.NewFromInt(-1)))
+	assert.True(t, result.ToAccount.Balance.Equal(account2.Balance.Add(amountToTransfer)))
+}
+
+func TestTransferTxAccountNotFound(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccount(t)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   math.MaxInt64,
+		Amount:        decimal.NewFromInt(1),
+	})
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, account1.Balance, updatedAccount1.Balance)
+}
+
+func TestTransferTxCrossCurrency(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyINR)
+
+	amountToTransfer := decimal.RequireFromString("10.5")
+	exchangeRate := decimal.RequireFromString("83.257")
+	rateTimestamp := time.Now().Add(-time.Minute)
+	expectedToAmount := decimal.RequireFromString("874.20")
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        amountToTransfer,
+		ExchangeRate:  exchangeRate,
+		RateTimestamp: rateTimestamp,
+	})
+	assert.NoError(t, err)
+
+	assert.True(t, amountToTransfer.Equal(result.Transfer.Amount))
+	assert.True(t, expectedToAmount.Equal(result.Transfer.ToAmount))
+	assert.True(t, exchangeRate.Equal(result.Transfer.ExchangeRate))
+	assert.WithinDuration(t, rateTimestamp, result.Transfer.RateTimestamp.Time, time.Second)
+
+	// Each account is debited or credited in its own currency
+	assert.True(t, amountToTransfer.Neg().Equal(result.FromEntry.Amount))
+	assert.True(t, expectedToAmount.Equal(result.ToEntry.Amount))
+	assert.True(t, account1.Balance.Sub(amountToTransfer).Equal(result.FromAccount.Balance))
+	assert.True(t, account2.Balance.Add(expectedToAmount).Equal(result.ToAccount.Balance))
+}
+
+func TestTransferTxExchangeRateRequired(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyEUR)
+
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.NewFromInt(1),
+	})
+	assert.ErrorIs(t, err, ErrExchangeRateRequired)
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, account1.Balance, updatedAccount1.Balance)
+}
+
+func TestTransferTxInvalidAmountPrecision(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	// USD minor unit has 2 decimal places
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.RequireFromString("1.001"),
+	})
+	assert.ErrorIs(t, err, ErrInvalidAmountPrecision)
+
+	var precisionErr *AmountPrecisionError
+	assert.ErrorAs(t, err, &precisionErr)
+	assert.Equal(t, CurrencyUSD, precisionErr.Currency)
+	assert.Equal(t, int16(2), precisionErr.Exponent)
+
+	// Trailing zeros are fine
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.RequireFromString("1.100"),
+	})
+	assert.NoError(t, err)
+}
@@ -8,6 +8,7 @@ package db
 import (
 	"context"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
@@ -15,21 +16,37 @@ const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  to_amount,
+  exchange_rate,
+  rate_timestamp,
+  reversal_of
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5, $6, $7
 )
-RETURNING id, from_account_id, to_account_id, amount, created_at
+RETURNING id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, rate_timestamp, reversal_of
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64           `json:"fromAccountId"`
-	ToAccountID   int64           `json:"toAccountId"`
-	Amount        decimal.Decimal `json:"amount"`
+	FromAccountID int64              `json:"fromAccountId"`
+	ToAccountID   int64              `json:"toAccountId"`
+	Amount        decimal.Decimal    `json:"amount"`
+	ToAmount      decimal.Decimal    `json:"toAmount"`
+	ExchangeRate  decimal.Decimal    `json:"exchangeRate"`
+	RateTimestamp pgtype.Timestamptz `json:"rateTimestamp"`
+	ReversalOf    pgtype.Int8        `json:"reversalOf"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRow(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ToAmount,
+		arg.ExchangeRate,
+		arg.RateTimestamp,
+		arg.ReversalOf,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -37,12 +54,16 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.RateTimestamp,
+		&i.ReversalOf,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, rate_timestamp, reversal_of FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
@@ -55,12 +76,106 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.RateTimestamp,
+		&i.ReversalOf,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, rate_timestamp, reversal_of FROM transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRow(ctx, getTransferForUpdate, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.ToAmount,
+		&i.ExchangeRate,
+		&i.RateTimestamp,
+		&i.ReversalOf,
 	)
 	return i, err
 }
 
+const listAccountTransfers = `-- name: ListAccountTransfers :many
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, rate_timestamp, reversal_of FROM transfers
+WHERE (
+    ($1::bool AND from_account_id = $2)
+    OR ($3::bool AND to_account_id = $2)
+  )
+  AND ($4::bigint IS NULL
+    OR from_account_id = $4 OR to_account_id = $4)
+  AND ($5::decimal IS NULL
+    OR CASE WHEN from_account_id = $2 THEN amount ELSE to_amount END >= $5)
+  AND ($6::decimal IS NULL
+    OR CASE WHEN from_account_id = $2 THEN amount ELSE to_amount END <= $6)
+ORDER BY id
+LIMIT $8
+OFFSET $7
+`
+
+type ListAccountTransfersParams struct {
+	IncludeOutgoing bool                `json:"includeOutgoing"`
+	AccountID       int64               `json:"accountId"`
+	IncludeIncoming bool                `json:"includeIncoming"`
+	Counterparty    pgtype.Int8         `json:"counterparty"`
+	MinAmount       decimal.NullDecimal `json:"minAmount"`
+	MaxAmount       decimal.NullDecimal `json:"maxAmount"`
+	OffsetCount     int32               `json:"offsetCount"`
+	LimitCount      int32               `json:"limitCount"`
+}
+
+func (q *Queries) ListAccountTransfers(ctx context.Context, arg ListAccountTransfersParams) ([]Transfer, error) {
+	rows, err := q.db.Query(ctx, listAccountTransfers,
+		arg.IncludeOutgoing,
+		arg.AccountID,
+		arg.IncludeIncoming,
+		arg.Counterparty,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.OffsetCount,
+		arg.LimitCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Transfer{}
+	for rows.Next() {
+		var i Transfer
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.RateTimestamp,
+			&i.ReversalOf,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, to_amount, exchange_rate, rate_timestamp, reversal_of FROM transfers
 ORDER BY id
 LIMIT $1
 OFFSET $2
@@ -86,6 +201,10 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.ToAmount,
+			&i.ExchangeRate,
+			&i.RateTimestamp,
+			&i.ReversalOf,
 		); err != nil {
 			return nil, err
 		}
@@ -96,3 +215,26 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 	}
 	return items, nil
 }
+
+const sumTransferReversals = `-- name: SumTransferReversals :one
+SELECT
+  COALESCE(SUM(amount), 0)::decimal AS total,
+  COALESCE(SUM(to_amount), 0)::decimal AS refunded
+FROM transfers
+WHERE reversal_of = $1
+`
+
+type SumTransferReversalsRow struct {
+	Total    decimal.Decimal `json:"total"`
+	Refunded decimal.Decimal `json:"refunded"`
+}
+
+// Reversals are debited from the to account of the transfer they reverse,
+// so their amounts are in the currency of its to_amount, and their to_amounts
+// in the currency of its amount
+func (q *Queries) SumTransferReversals(ctx context.Context, reversalOf pgtype.Int8) (SumTransferReversalsRow, error) {
+	row := q.db.QueryRow(ctx, sumTransferReversals, reversalOf)
+	var i SumTransferReversalsRow
+	err := row.Scan(&i.Total, &i.Refunded)
+	return i, err
+}
@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,6 +20,9 @@ func createRandomTransfer(t *testing.T) Transfer {
 		FromAccountID: fromAccount.ID,
 		ToAccountID:   toAccount.ID,
 		Amount:        utils.RandomDecimal(10, 1000),
+		ToAmount:      utils.RandomDecimal(10, 1000),
+		ExchangeRate:  utils.RandomDecimal(1, 10),
+		RateTimestamp: pgtype.Timestamptz{Time: time.Now(), Valid: true},
 	}
 
 	transfer, err := testQueries.CreateTransfer(context.Background(), arg)
@@ -26,6 +32,9 @@ func createRandomTransfer(t *testing.T) Transfer {
 	assert.Equal(t, arg.FromAccountID, transfer.FromAccountID)
 	assert.Equal(t, arg.ToAccountID, transfer.ToAccountID)
 	assert.Equal(t, arg.Amount, transfer.Amount)
+	assert.Equal(t, arg.ToAmount, transfer.ToAmount)
+	assert.Equal(t, arg.ExchangeRate, transfer.ExchangeRate)
+	assert.WithinDuration(t, arg.RateTimestamp.Time, transfer.RateTimestamp.Time, time.Second)
 	assert.NotEmpty(t, transfer.CreatedAt)
 
 	return transfer
@@ -67,3 +76,189 @@ func TestListTransfers(t *testing.T) {
 		assert.NotEmpty(t, transfer)
 	}
 }
+
+func TestListAccountTransfers(t *testing.T) {
+	account := createRandomAccount(t)
+	counterparty1 := createRandomAccountWithCurrency(t, account.Currency)
+	counterparty2 := createRandomAccountWithCurrency(t, account.Currency)
+
+	createTransfer := func(fromAccountID, toAccountID int64, amount int64) Transfer {
+		transfer, err := testQueries.CreateTransfer(context.Background(), CreateTransferParams{
+			FromAccountID: fromAccountID,
+			ToAccountID:   toAccountID,
+			Amount:        decimal.NewFromInt(amount),
+			ToAmount:      decimal.NewFromInt(amount),
+			ExchangeRate:  decimal.NewFromInt(1),
+			RateTimestamp: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		})
+		assert.NoError(t, err)
+		return transfer
+	}
+
+	out1 := createTransfer(account.ID, counterparty1.ID, 10)
+	out2 := createTransfer(account.ID, counterparty2.ID, 20)
+	in1 := createTransfer(counterparty1.ID, account.ID, 30)
+	createTransfer(counterparty1.ID, counterparty2.ID, 40)
+
+	testCases := []struct {
+		name     string
+		arg      ListAccountTransfersParams
+		expected []Transfer
+	}{
+		{
+			name: "Both",
+			arg: ListAccountTransfersParams{
+				IncludeOutgoing: true,
+				IncludeIncoming: true,
+			},
+			expected: []Transfer{out1, out2, in1},
+		},
+		{
+			name: "Outgoing",
+			arg: ListAccountTransfersParams{
+				IncludeOutgoing: true,
+			},
+			expected: []Transfer{out1, out2},
+		},
+		{
+			name: "Incoming",
+			arg: ListAccountTransfersParams{
+				IncludeIncoming: true,
+			},
+			expected: []Transfer{in1},
+		},
+		{
+			name: "Counterparty",
+			arg: ListAccountTransfersParams{
+				IncludeOutgoing: true,
+				IncludeIncoming: true,
+				Counterparty:    pgtype.Int8{Int64: counterparty1.ID, Valid: true},
+			},
+			expected: []Transfer{out1, in1},
+		},
+		{
+			name: "AmountRange",
+			arg: ListAccountTransfersParams{
+				IncludeOutgoing: true,
+				IncludeIncoming: true,
+				MinAmount:       decimal.NewNullDecimal(decimal.NewFromInt(15)),
+				MaxAmount:       decimal.NewNullDecimal(decimal.NewFromInt(30)),
+			},
+			expected: []Transfer{out2, in1},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			arg := tc.arg
+			arg.AccountID = account.ID
+			arg.LimitCount = 10
+
+			transfers, err := testQueries.ListAccountTransfers(context.Background(), arg)
+			assert.NoError(t, err)
+			assert.Len(t, transfers, len(tc.expected))
+
+			for j := range tc.expected {
+				assert.Equal(t, tc.expected[j].ID, transfers[j].ID)
+			}
+		})
+	}
+}
+
+func TestListAccountTransfersCrossCurrency(t *testing.T) {
+	account := createRandomAccountWithCurrency(t, CurrencyEUR)
+	counterparty := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	createTransfer := func(fromAccountID, toAccountID int64, amount, toAmount string) Transfer {
+		transfer, err := testQueries.CreateTransfer(context.Background(), CreateTransferParams{
+			FromAccountID: fromAccountID,
+			ToAccountID:   toAccountID,
+			Amount:        decimal.RequireFromString(amount),
+			ToAmount:      decimal.RequireFromString(toAmount),
+			ExchangeRate:  decimal.RequireFromString(toAmount).Div(decimal.RequireFromString(amount)),
+			RateTimestamp: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		})
+		assert.NoError(t, err)
+		return transfer
+	}
+
+	// 100 EUR sent as 110 USD, and 200 USD received as 180 EUR
+	out := createTransfer(account.ID, counterparty.ID, "100", "110")
+	in := createTransfer(counterparty.ID, account.ID, "200", "180")
+
+	testCases := []struct {
+		name      string
+		minAmount string
+		maxAmount string
+		expected  []Transfer
+	}{
+		{
+			name:      "AmountInAccountCurrency",
+			minAmount: "100",
+			maxAmount: "180",
+			expected:  []Transfer{out, in},
+		},
+		{
+			name:      "OutgoingAmountInCounterpartyCurrency",
+			minAmount: "110",
+			maxAmount: "110",
+			expected:  []Transfer{},
+		},
+		{
+			name:      "IncomingAmountInCounterpartyCurrency",
+			minAmount: "200",
+			maxAmount: "200",
+			expected:  []Transfer{},
+		},
+		{
+			name:      "IncomingOnly",
+			minAmount: "150",
+			maxAmount: "190",
+			expected:  []Transfer{in},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			transfers, err := testQueries.ListAccountTransfers(context.Background(), ListAccountTransfersParams{
+				IncludeOutgoing: true,
+				IncludeIncoming: true,
+				AccountID:       account.ID,
+				MinAmount:       decimal.NewNullDecimal(decimal.RequireFromString(tc.minAmount)),
+				MaxAmount:       decimal.NewNullDecimal(decimal.RequireFromString(tc.maxAmount)),
+				LimitCount:      10,
+			})
+			assert.NoError(t, err)
+			assert.Len(t, transfers, len(tc.expected))
+
+			for j := range tc.expected {
+				assert.Equal(t, tc.expected[j].ID, transfers[j].ID)
+			}
+		})
+	}
+}
+
+func TestCreateTransferNonPositiveAmount(t *testing.T) {
+	fromAccount := createRandomAccount(t)
+	toAccount := createRandomAccountWithCurrency(t, fromAccount.Currency)
+
+	for _, amount := range []decimal.Decimal{decimal.Zero, decimal.NewFromInt(-10)} {
+		_, err := testQueries.CreateTransfer(context.Background(), CreateTransferParams{
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   toAccount.ID,
+			Amount:        amount,
+			ToAmount:      amount,
+			ExchangeRate:  decimal.NewFromInt(1),
+			RateTimestamp: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		})
+
+		var pgErr *pgconn.PgError
+		if assert.ErrorAs(t, err, &pgErr) {
+			assert.Equal(t, "23514", pgErr.Code)
+		}
+	}
+}
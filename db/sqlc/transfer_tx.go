@@ -2,15 +2,96 @@ package db
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/shopspring/decimal"
 )
 
-// TransferTxParams contains the input parameters of the transfer transaction
+var (
+	// ErrExchangeRateRequired is returned when a transfer between accounts
+	// of different currencies is made without exchange rate
+	ErrExchangeRateRequired = errors.New("exchange rate is required between different currencies")
+
+	// ErrToAmountTooSmall is returned when the converted amount is rounded down to zero
+	ErrToAmountTooSmall = errors.New("converted amount is too small")
+)
+
+// ErrInsufficientFunds is returned when the from account cannot cover the transfer amount
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// InsufficientFundsError describes a transfer rejected because the from account
+// balance, including its overdraft limit and minus its active holds, is lower than the transfer amount
+type InsufficientFundsError struct {
+	AccountID int64
+	Available decimal.Decimal
+	Requested decimal.Decimal
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf(
+		"account [%d] has insufficient funds, available: %s, requested: %s",
+		e.AccountID,
+		e.Available,
+		e.Requested)
+}
+
+func (e *InsufficientFundsError) Unwrap() error {
+	return ErrInsufficientFunds
+}
+
+// ErrInvalidAmountPrecision is returned when an amount has more decimal places than its currency minor unit
+var ErrInvalidAmountPrecision = errors.New("invalid amount precision")
+
+// AmountPrecisionError describes an amount which cannot be expressed in the minor unit of its currency
+type AmountPrecisionError struct {
+	Amount   decimal.Decimal
+	Currency Currency
+	Exponent int16
+}
+
+func (e *AmountPrecisionError) Error() string {
+	return fmt.Sprintf(
+		"amount %s has more than %d decimal places allowed by %s",
+		e.Amount,
+		e.Exponent,
+		e.Currency)
+}
+
+func (e *AmountPrecisionError) Unwrap() error {
+	return ErrInvalidAmountPrecision
+}
+
+// checkAmountPrecision makes sure amount can be expressed in the minor unit of currency
+func checkAmountPrecision(amount decimal.Decimal, currency CurrencyInfo) error {
+	if !amount.Equal(amount.Truncate(int32(currency.Exponent))) {
+		return &AmountPrecisionError{
+			Amount:   amount,
+			Currency: currency.Code,
+			Exponent: currency.Exponent,
+		}
+	}
+
+	return nil
+}
+
+// TransferTxParams contains the input parameters of the transfer transaction.
+// Amount is in the currency of the from account, and is converted to the currency
+// of the to account with ExchangeRate. ExchangeRate can be left zero when both
+// accounts have the same currency. ReversalOf, ToAmount and SkipLimits are only set by
+// ReverseTransferTx, ToAmount is then credited instead of the converted amount.
 type TransferTxParams struct {
 	FromAccountId int64           `json:"from_account_id"`
 	ToAccountId   int64           `json:"to_account_id"`
 	Amount        decimal.Decimal `json:"amount"`
+	ExchangeRate  decimal.Decimal `json:"exchange_rate"`
+	RateTimestamp time.Time       `json:"rate_timestamp"`
+	ReversalOf    int64           `json:"reversal_of,omitempty"`
+	ToAmount      decimal.Decimal `json:"to_amount,omitempty"`
+	SkipLimits    bool            `json:"skip_limits,omitempty"`
 }
 
 // TransferTxResult contains the out parameters of the transfer transaction
@@ -23,69 +104,248 @@ type TransferTxResult struct {
 }
 
 // TransferTx tranfer amount from one account to another account.
-// It creates transfer record, from/to entries and update balances of from/to accounts
+// It locks both accounts, checks their status and makes sure the from account can cover the amount within
+// its overdraft limit and active holds and within its transfer limits and the ones of its owner
+// (unless it is a system account or the limits are skipped), creates transfer record, from/to entries,
+// update balances of from/to accounts and records the transfer event in the outbox and in the audit log.
+// The from account is debited in its own currency and the to account is credited the converted amount,
+// rounded to the minor unit of its currency.
 func (store *SqlStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 
-	amountToWithdraw := arg.Amount.Mul(decimal.NewFromFloat(-1))
-	amountToDeposit := arg.Amount
-
 	txErr := store.execTx(ctx, func(q *Queries) error {
 		var err error
+		result, err = store.transfer(ctx, q, arg)
+		return err
+	})
 
-		// Create Transfer Record
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountId,
-			ToAccountID:   arg.ToAccountId,
-			Amount:        arg.Amount,
-		})
-		if err != nil {
-			return err
+	return result, txErr
+}
+
+// transfer runs the queries of a transfer on q, which is expected
+// to be bound to a transaction
+func (store *SqlStore) transfer(ctx context.Context, q *Queries, arg TransferTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	fromAccount, toAccount, err := lockAccounts(ctx, q, arg.FromAccountId, arg.ToAccountId)
+	if err != nil {
+		return result, err
+	}
+
+	err = store.checkCanSend(ctx, q, fromAccount)
+	if err != nil {
+		return result, err
+	}
+
+	err = store.checkCanReceive(ctx, q, toAccount)
+	if err != nil {
+		return result, err
+	}
+
+	exchangeRate := arg.ExchangeRate
+	if exchangeRate.IsZero() {
+		if fromAccount.Currency != toAccount.Currency {
+			return result, ErrExchangeRateRequired
 		}
 
-		// Create FromEntry record
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountId,
-			Amount:    amountToWithdraw,
-		})
+		exchangeRate = decimal.NewFromInt(1)
+	}
+
+	rateTimestamp := arg.RateTimestamp
+	if rateTimestamp.IsZero() {
+		rateTimestamp = time.Now()
+	}
+
+	fromCurrency, err := q.GetCurrency(ctx, fromAccount.Currency)
+	if err != nil {
+		return result, err
+	}
+
+	err = checkAmountPrecision(arg.Amount, fromCurrency)
+	if err != nil {
+		return result, err
+	}
+
+	toCurrency := fromCurrency
+	if toAccount.Currency != fromAccount.Currency {
+		toCurrency, err = q.GetCurrency(ctx, toAccount.Currency)
 		if err != nil {
-			return err
+			return result, err
 		}
+	}
 
-		// Create ToEntry record
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountId,
-			Amount:    amountToDeposit,
-		})
+	amountToWithdraw := arg.Amount.Neg()
+	amountToDeposit := arg.ToAmount
+	if amountToDeposit.IsZero() {
+		amountToDeposit = arg.Amount.Mul(exchangeRate).Round(int32(toCurrency.Exponent))
+	}
+
+	err = checkAmountPrecision(amountToDeposit, toCurrency)
+	if err != nil {
+		return result, err
+	}
+
+	if !amountToDeposit.IsPositive() {
+		return result, ErrToAmountTooSmall
+	}
+
+	// System accounts are the counterpart of the money entering
+	// the bank, they are expected to go below zero
+	if !fromAccount.IsSystem {
+		err = checkAvailableFunds(ctx, q, fromAccount, arg.Amount)
 		if err != nil {
-			return err
+			return result, err
 		}
 
-		// To avoid deadlock, always perform transfer in a specific order
-		// for eg: these 2 txs will endup in deadlock. t1: a1 -> a2 and t2: a2 -> a1.
-		// To avoid deadlock, always update balance of a1 before a2.
-		if arg.FromAccountId < arg.ToAccountId {
-			result.FromAccount, result.ToAccount, err = addAmount(
-				ctx,
-				q,
-				arg.FromAccountId,
-				amountToWithdraw,
-				arg.ToAccountId,
-				amountToDeposit)
-		} else {
-			result.ToAccount, result.FromAccount, err = addAmount(
-				ctx,
-				q,
-				arg.ToAccountId,
-				amountToDeposit,
-				arg.FromAccountId,
-				amountToWithdraw)
+		if !arg.SkipLimits {
+			err = checkTransferLimits(ctx, q, fromAccount, arg.Amount)
+			if err != nil {
+				return result, err
+			}
 		}
+	}
+
+	// Create Transfer Record
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID: arg.FromAccountId,
+		ToAccountID:   arg.ToAccountId,
+		Amount:        arg.Amount,
+		ToAmount:      amountToDeposit,
+		ExchangeRate:  exchangeRate,
+		RateTimestamp: pgtype.Timestamptz{Time: rateTimestamp, Valid: true},
+		ReversalOf:    pgtype.Int8{Int64: arg.ReversalOf, Valid: arg.ReversalOf != 0},
+	})
+	if err != nil {
+		return result, err
+	}
 
-		return nil
+	// Create FromEntry record
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.FromAccountId,
+		Amount:     amountToWithdraw,
+		TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
 	})
+	if err != nil {
+		return result, err
+	}
 
-	return result, txErr
+	// Create ToEntry record
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.ToAccountId,
+		Amount:     amountToDeposit,
+		TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// To avoid deadlock, always perform transfer in a specific order
+	// for eg: these 2 txs will endup in deadlock. t1: a1 -> a2 and t2: a2 -> a1.
+	// To avoid deadlock, always update balance of a1 before a2.
+	if arg.FromAccountId < arg.ToAccountId {
+		result.FromAccount, result.ToAccount, err = addAmount(
+			ctx,
+			q,
+			arg.FromAccountId,
+			amountToWithdraw,
+			arg.ToAccountId,
+			amountToDeposit)
+	} else {
+		result.ToAccount, result.FromAccount, err = addAmount(
+			ctx,
+			q,
+			arg.ToAccountId,
+			amountToDeposit,
+			arg.FromAccountId,
+			amountToWithdraw)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	err = recordEvent(
+		ctx,
+		q,
+		transferEventType(fromAccount, toAccount, arg.ReversalOf != 0),
+		result.Transfer.ID,
+		result.Transfer,
+		fromAccount.Owner,
+		toAccount.Owner)
+	if err != nil {
+		return result, err
+	}
+
+	err = recordAudit(
+		ctx,
+		q,
+		AuditActionCreate,
+		AuditResourceTransfer,
+		strconv.FormatInt(result.Transfer.ID, 10),
+		nil,
+		result.Transfer)
+	return result, err
+}
+
+// AvailableBalance returns the balance of account, including its overdraft limit,
+// minus heldAmount, the amount reserved by its active holds
+func AvailableBalance(account Account, heldAmount decimal.Decimal) decimal.Decimal {
+	return account.Balance.Add(account.OverdraftLimit).Sub(heldAmount)
+}
+
+// availableBalance returns the available balance of account with its current holds
+func availableBalance(ctx context.Context, q *Queries, account Account) (decimal.Decimal, error) {
+	heldAmount, err := q.GetAccountHeldAmount(ctx, account.ID)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return AvailableBalance(account, heldAmount), nil
+}
+
+// checkAvailableFunds makes sure the available balance of account can cover amount
+func checkAvailableFunds(ctx context.Context, q *Queries, account Account, amount decimal.Decimal) error {
+	available, err := availableBalance(ctx, q, account)
+	if err != nil {
+		return err
+	}
+
+	if available.LessThan(amount) {
+		return &InsufficientFundsError{
+			AccountID: account.ID,
+			Available: available,
+			Requested: amount,
+		}
+	}
+
+	return nil
+}
+
+// lockAccounts locks both accounts for the rest of the transaction.
+// Accounts are always locked in the same order to avoid deadlocks between
+// concurrent transfers going in opposite directions.
+func lockAccounts(
+	ctx context.Context,
+	q *Queries,
+	fromAccountID int64,
+	toAccountID int64) (fromAccount Account, toAccount Account, err error) {
+
+	if fromAccountID < toAccountID {
+		fromAccount, err = q.GetAccountForUpdate(ctx, fromAccountID)
+		if err != nil {
+			return
+		}
+
+		toAccount, err = q.GetAccountForUpdate(ctx, toAccountID)
+		return
+	}
+
+	toAccount, err = q.GetAccountForUpdate(ctx, toAccountID)
+	if err != nil {
+		return
+	}
+
+	fromAccount, err = q.GetAccountForUpdate(ctx, fromAccountID)
+	return
 }
 
 func addAmount(
@@ -17,7 +17,7 @@ INSERT INTO users (
 ) VALUES (
   $1, $2, $3
 )
-RETURNING name, email, hash_password, created_at, updated_at
+RETURNING name, email, hash_password, created_at, updated_at, role
 `
 
 type CreateUserParams struct {
@@ -35,6 +35,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.HashPassword,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
 	)
 	return i, err
 }
@@ -50,7 +51,7 @@ func (q *Queries) DeleteUser(ctx context.Context, name string) error {
 }
 
 const getUser = `-- name: GetUser :one
-SELECT name, email, hash_password, created_at, updated_at FROM users
+SELECT name, email, hash_password, created_at, updated_at, role FROM users
 WHERE name = $1 LIMIT 1
 `
 
@@ -63,12 +64,13 @@ func (q *Queries) GetUser(ctx context.Context, name string) (User, error) {
 		&i.HashPassword,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
 	)
 	return i, err
 }
 
 const listUsers = `-- name: ListUsers :many
-SELECT name, email, hash_password, created_at, updated_at FROM users
+SELECT name, email, hash_password, created_at, updated_at, role FROM users
 ORDER BY name
 LIMIT $1
 OFFSET $2
@@ -94,6 +96,7 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.HashPassword,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -110,7 +113,7 @@ UPDATE users
   set
   hash_password = $2
 WHERE name = $1
-RETURNING name, email, hash_password, created_at, updated_at
+RETURNING name, email, hash_password, created_at, updated_at, role
 `
 
 type UpdateUserParams struct {
@@ -127,6 +130,34 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.HashPassword,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+  set
+  role = $2
+WHERE name = $1
+RETURNING name, email, hash_password, created_at, updated_at, role
+`
+
+type UpdateUserRoleParams struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserRole, arg.Name, arg.Role)
+	var i User
+	err := row.Scan(
+		&i.Name,
+		&i.Email,
+		&i.HashPassword,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Role,
 	)
 	return i, err
 }
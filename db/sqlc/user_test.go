@@ -27,6 +27,7 @@ func createRandomUser(t *testing.T) User {
 	assert.Equal(t, arg.Name, user.Name)
 	assert.Equal(t, arg.Email, user.Email)
 	assert.Equal(t, arg.HashPassword, user.HashPassword)
+	assert.Equal(t, utils.DepositorRole, user.Role)
 	assert.NotEmpty(t, user.CreatedAt)
 	assert.NotEmpty(t, user.UpdatedAt)
 
@@ -74,6 +75,25 @@ func TestUpdateUser(t *testing.T) {
 	assert.WithinDuration(t, user1.UpdatedAt.Time, user2.UpdatedAt.Time, time.Second)
 }
 
+func TestUpdateUserRole(t *testing.T) {
+	user1 := createRandomUser(t)
+
+	user2, err := testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Name: user1.Name,
+		Role: utils.AdminRole,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, user1.Name, user2.Name)
+	assert.Equal(t, utils.AdminRole, user2.Role)
+
+	// Only known roles are accepted
+	_, err = testQueries.UpdateUserRole(context.Background(), UpdateUserRoleParams{
+		Name: user1.Name,
+		Role: utils.RandomString(6),
+	})
+	assert.Error(t, err)
+}
+
 func TestDeleteUser(t *testing.T) {
 	user1 := createRandomUser(t)
 
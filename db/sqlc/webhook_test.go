@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func createRandomWebhookEndpoint(t *testing.T, owner string, eventTypes ...string) WebhookEndpoint {
+	arg := CreateWebhookEndpointParams{
+		Owner:      owner,
+		Url:        "https://example.com/hooks",
+		Secret:     "whsec_secret",
+		EventTypes: eventTypes,
+	}
+
+	endpoint, err := testQueries.CreateWebhookEndpoint(context.Background(), arg)
+	assert.NoError(t, err)
+	assert.Equal(t, arg.Owner, endpoint.Owner)
+	assert.Equal(t, arg.EventTypes, endpoint.EventTypes)
+	assert.True(t, endpoint.IsActive)
+
+	return endpoint
+}
+
+// listWebhookDeliveryEventTypes returns the event types delivered to an endpoint, latest first
+func listWebhookDeliveryEventTypes(t *testing.T, endpointID int64) []string {
+	deliveries, err := testQueries.ListWebhookDeliveries(context.Background(), ListWebhookDeliveriesParams{
+		EndpointID: endpointID,
+		Limit:      10,
+		Offset:     0,
+	})
+	assert.NoError(t, err)
+
+	eventTypes := make([]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		assert.Equal(t, WebhookDeliveryStatusPending, delivery.Status)
+
+		event, err := testQueries.GetOutboxEvent(context.Background(), delivery.EventID)
+		assert.NoError(t, err)
+		eventTypes = append(eventTypes, event.EventType)
+	}
+
+	return eventTypes
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	store := NewStore(connPool)
+
+	account1 := createRandomAccountWithCurrency(t, CurrencyUSD)
+	account2 := createRandomAccountWithCurrency(t, CurrencyUSD)
+
+	transferEndpoint := createRandomWebhookEndpoint(t, account2.Owner, EventTransferCreated)
+	cashEndpoint := createRandomWebhookEndpoint(t, account1.Owner, EventDepositCreated, EventAccountClosed)
+
+	// Only the subscribed endpoints of the parties get the event
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account1.ID,
+		ToAccountId:   account2.ID,
+		Amount:        decimal.NewFromInt(10),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{EventTransferCreated}, listWebhookDeliveryEventTypes(t, transferEndpoint.ID))
+	assert.Empty(t, listWebhookDeliveryEventTypes(t, cashEndpoint.ID))
+
+	_, err = store.DepositTx(context.Background(), CashTxParams{
+		AccountID: account1.ID,
+		Amount:    decimal.NewFromInt(10),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{EventDepositCreated}, listWebhookDeliveryEventTypes(t, cashEndpoint.ID))
+
+	account, err := store.GetAccount(context.Background(), account1.ID)
+	assert.NoError(t, err)
+
+	_, err = store.WithdrawTx(context.Background(), CashTxParams{
+		AccountID: account1.ID,
+		Amount:    account.Balance,
+	})
+	assert.NoError(t, err)
+
+	_, err = store.CloseAccountTx(context.Background(), account1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{EventAccountClosed, EventDepositCreated}, listWebhookDeliveryEventTypes(t, cashEndpoint.ID))
+
+	// A disabled endpoint gets no new deliveries
+	disabledEndpoint, err := store.DisableWebhookEndpoint(context.Background(), transferEndpoint.ID)
+	assert.NoError(t, err)
+	assert.False(t, disabledEndpoint.IsActive)
+
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountId: account2.ID,
+		ToAccountId:   createRandomAccountWithCurrency(t, CurrencyUSD).ID,
+		Amount:        decimal.NewFromInt(1),
+	})
+	assert.NoError(t, err)
+	assert.Len(t, listWebhookDeliveryEventTypes(t, transferEndpoint.ID), 1)
+}
@@ -0,0 +1,43 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// FilePublisher appends the events to a file, one JSON object per line
+type FilePublisher struct {
+	mutex sync.Mutex
+	file  *os.File
+}
+
+// NewFilePublisher opens the file the events are appended to, creating it if needed
+func NewFilePublisher(path string) (*FilePublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FilePublisher{file: file}, nil
+}
+
+// Publish writes the event as a line of the file
+func (publisher *FilePublisher) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+
+	_, err = publisher.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the file
+func (publisher *FilePublisher) Close() error {
+	return publisher.file.Close()
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultHTTPTimeout = 10 * time.Second
+
+	eventIDHeader   = "X-Event-ID"
+	eventTypeHeader = "X-Event-Type"
+)
+
+// HTTPPublisher posts each event as JSON to a webhook URL.
+// Any response other than 2xx is a failure, and the event is published again later.
+type HTTPPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPPublisher creates a publisher posting the events to url.
+// A default client with a timeout is used when client is nil.
+func NewHTTPPublisher(url string, client *http.Client) (*HTTPPublisher, error) {
+	if url == "" {
+		return nil, errors.New("event webhook url is required")
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	return &HTTPPublisher{
+		url:    url,
+		client: client,
+	}, nil
+}
+
+// Publish posts the event to the webhook URL
+func (publisher *HTTPPublisher) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, publisher.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(eventIDHeader, strconv.FormatInt(event.ID, 10))
+	request.Header.Set(eventTypeHeader, event.Type)
+
+	response, err := publisher.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("event webhook responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
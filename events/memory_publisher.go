@@ -0,0 +1,34 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryPublisher keeps the published events in memory, mostly for tests and local runs
+type MemoryPublisher struct {
+	mutex  sync.Mutex
+	events []Event
+}
+
+// NewMemoryPublisher creates an empty memory publisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish appends the event to the published events
+func (publisher *MemoryPublisher) Publish(ctx context.Context, event Event) error {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+
+	publisher.events = append(publisher.events, event)
+	return nil
+}
+
+// Events returns the events published so far, in publication order
+func (publisher *MemoryPublisher) Events() []Event {
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+
+	return append([]Event(nil), publisher.events...)
+}
@@ -0,0 +1,23 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Event is a ledger event published to the downstream services
+type Event struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID int64           `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// EventPublisher delivers events to the downstream services.
+// Events are delivered at least once, so an event can be published again
+// after a failure, and consumers are expected to deduplicate them on ID.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
@@ -0,0 +1,149 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func randomEvent(id int64) Event {
+	return Event{
+		ID:          id,
+		Type:        "transfer.created",
+		AggregateID: id * 10,
+		Payload:     json.RawMessage(`{"amount":"10"}`),
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+func TestMemoryPublisher(t *testing.T) {
+	publisher := NewMemoryPublisher()
+	assert.Empty(t, publisher.Events())
+
+	for id := int64(1); id <= 3; id++ {
+		err := publisher.Publish(context.Background(), randomEvent(id))
+		assert.NoError(t, err)
+	}
+
+	published := publisher.Events()
+	assert.Len(t, published, 3)
+	assert.Equal(t, randomEvent(1).ID, published[0].ID)
+	assert.Equal(t, randomEvent(3).ID, published[2].ID)
+}
+
+func TestFilePublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	publisher, err := NewFilePublisher(path)
+	assert.NoError(t, err)
+
+	events := []Event{randomEvent(1), randomEvent(2)}
+	for _, event := range events {
+		err = publisher.Publish(context.Background(), event)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, publisher.Close())
+
+	// Events are appended to the existing file
+	publisher, err = NewFilePublisher(path)
+	assert.NoError(t, err)
+
+	events = append(events, randomEvent(3))
+	err = publisher.Publish(context.Background(), events[2])
+	assert.NoError(t, err)
+	assert.NoError(t, publisher.Close())
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var lines int
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		err = json.Unmarshal(scanner.Bytes(), &event)
+		assert.NoError(t, err)
+		assert.Equal(t, events[lines].ID, event.ID)
+		assert.Equal(t, events[lines].Type, event.Type)
+		assert.JSONEq(t, string(events[lines].Payload), string(event.Payload))
+		lines++
+	}
+	assert.Equal(t, len(events), lines)
+}
+
+func TestHTTPPublisher(t *testing.T) {
+	event := randomEvent(1)
+
+	testCases := []struct {
+		name          string
+		status        int
+		validateError func(err error)
+	}{
+		{
+			name:   "OK",
+			status: http.StatusNoContent,
+			validateError: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:   "ErrorStatus",
+			status: http.StatusServiceUnavailable,
+			validateError: func(err error) {
+				assert.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var received Event
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "1", r.Header.Get(eventIDHeader))
+				assert.Equal(t, event.Type, r.Header.Get(eventTypeHeader))
+
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.NoError(t, json.Unmarshal(body, &received))
+
+				w.WriteHeader(tc.status)
+			}))
+			defer receiver.Close()
+
+			publisher, err := NewHTTPPublisher(receiver.URL, receiver.Client())
+			assert.NoError(t, err)
+
+			err = publisher.Publish(context.Background(), event)
+			tc.validateError(err)
+			assert.Equal(t, event.ID, received.ID)
+		})
+	}
+}
+
+func TestHTTPPublisherUnreachable(t *testing.T) {
+	_, err := NewHTTPPublisher("", nil)
+	assert.Error(t, err)
+
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	url := receiver.URL
+	receiver.Close()
+
+	publisher, err := NewHTTPPublisher(url, nil)
+	assert.NoError(t, err)
+
+	err = publisher.Publish(context.Background(), randomEvent(1))
+	assert.Error(t, err)
+}
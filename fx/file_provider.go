@@ -0,0 +1,48 @@
+package fx
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// rateFile is the layout of the file read by NewFileRateProvider
+type rateFile struct {
+	// Timestamp applies to the rates which don't have their own timestamp
+	Timestamp time.Time `json:"timestamp"`
+	Rates     []Rate    `json:"rates"`
+}
+
+// NewFileRateProvider creates a rate provider serving the rates of a JSON file, eg:
+//
+//	{"timestamp": "2023-12-10T09:00:00Z", "rates": [{"from": "USD", "to": "EUR", "rate": "0.92"}]}
+//
+// The file is read once, the rates are not reloaded when it changes.
+// It is meant for tests and development, the live rates are served by HTTPRateProvider.
+func NewFileRateProvider(path string) (FXRateProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read rates file: %w", err)
+	}
+
+	var file rateFile
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse rates file: %w", err)
+	}
+
+	return file.provider()
+}
+
+// provider returns a static provider of the rates,
+// with the timestamp of the file applied to the rates without their own
+func (file rateFile) provider() (FXRateProvider, error) {
+	for i := range file.Rates {
+		if file.Rates[i].Timestamp.IsZero() {
+			file.Rates[i].Timestamp = file.Timestamp
+		}
+	}
+
+	return NewStaticRateProvider(file.Rates)
+}
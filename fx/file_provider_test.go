@@ -0,0 +1,43 @@
+package fx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileRateProvider(t *testing.T) {
+	provider, err := NewFileRateProvider("testdata/rates.json")
+	assert.NoError(t, err)
+
+	rate, err := provider.GetRate(context.Background(), "USD", "EUR")
+	assert.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("0.92").Equal(rate.Rate))
+	assert.Equal(t, time.Date(2023, 12, 10, 9, 0, 0, 0, time.UTC), rate.Timestamp.UTC())
+
+	// Rates can override the timestamp of the file
+	rate, err = provider.GetRate(context.Background(), "EUR", "INR")
+	assert.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("90.5").Equal(rate.Rate))
+	assert.Equal(t, time.Date(2023, 12, 10, 10, 0, 0, 0, time.UTC), rate.Timestamp.UTC())
+
+	_, err = provider.GetRate(context.Background(), "USD", "GBP")
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestFileRateProviderInvalidFile(t *testing.T) {
+	_, err := NewFileRateProvider(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "rates.json")
+	err = os.WriteFile(path, []byte("not json"), 0o600)
+	assert.NoError(t, err)
+
+	_, err = NewFileRateProvider(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,199 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultHTTPTimeout = 10 * time.Second
+
+	// fetchRetryInterval is the time after which failed rates are fetched again,
+	// unless the refresh interval is shorter
+	fetchRetryInterval = 10 * time.Second
+)
+
+// ErrRatesUnavailable is returned when the rates cannot be fetched and none were fetched before
+var ErrRatesUnavailable = errors.New("exchange rates unavailable")
+
+// HTTPRateProvider serves the live rates published at a URL, in the layout of
+// the rates file. The rates are fetched again once they are older than the
+// refresh interval, and the previous rates are kept when fetching fails.
+// Only one fetch runs at a time, outside of the lock, and a failed fetch
+// is not retried before the retry interval.
+type HTTPRateProvider struct {
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+
+	mutex     sync.Mutex
+	rates     FXRateProvider
+	fetchedAt time.Time
+	fetchErr  error
+	failedAt  time.Time
+	// fetching is closed when the running fetch completes, and is nil otherwise
+	fetching chan struct{}
+}
+
+// NewHTTPRateProvider creates a rate provider fetching the rates from url.
+// A default client with a timeout is used when client is nil.
+func NewHTTPRateProvider(url string, client *http.Client, refreshInterval time.Duration) (*HTTPRateProvider, error) {
+	if url == "" {
+		return nil, errors.New("exchange rates url is required")
+	}
+
+	if refreshInterval <= 0 {
+		return nil, fmt.Errorf("invalid exchange rates refresh interval: %s", refreshInterval)
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: defaultHTTPTimeout}
+	}
+
+	return &HTTPRateProvider{
+		url:             url,
+		client:          client,
+		refreshInterval: refreshInterval,
+	}, nil
+}
+
+// GetRate returns the rate of the currency pair from the last fetched rates
+func (provider *HTTPRateProvider) GetRate(ctx context.Context, from string, to string) (Rate, error) {
+	if from == to {
+		return Rate{
+			From:      from,
+			To:        to,
+			Rate:      decimal.NewFromInt(1),
+			Timestamp: time.Now(),
+		}, nil
+	}
+
+	rates, err := provider.currentRates(ctx)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	return rates.GetRate(ctx, from, to)
+}
+
+// currentRates returns the fetched rates, fetching them again when they are too old.
+// While rates are being fetched, the previous rates are returned if any,
+// otherwise the fetch is waited for.
+func (provider *HTTPRateProvider) currentRates(ctx context.Context) (FXRateProvider, error) {
+	provider.mutex.Lock()
+
+	for {
+		if provider.rates != nil && time.Since(provider.fetchedAt) < provider.refreshInterval {
+			rates := provider.rates
+			provider.mutex.Unlock()
+			return rates, nil
+		}
+
+		if provider.fetchErr != nil && time.Since(provider.failedAt) < provider.retryInterval() {
+			defer provider.mutex.Unlock()
+			return provider.lastRates()
+		}
+
+		if provider.fetching == nil {
+			break
+		}
+
+		if provider.rates != nil {
+			rates := provider.rates
+			provider.mutex.Unlock()
+			return rates, nil
+		}
+
+		fetching := provider.fetching
+		provider.mutex.Unlock()
+
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		provider.mutex.Lock()
+	}
+
+	fetching := make(chan struct{})
+	provider.fetching = fetching
+	provider.mutex.Unlock()
+
+	// The fetch is shared by the waiting requests,
+	// so it is not canceled with the request starting it
+	rates, err := provider.fetch(context.WithoutCancel(ctx))
+
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	provider.fetching = nil
+	close(fetching)
+
+	if err != nil {
+		provider.fetchErr = err
+		provider.failedAt = time.Now()
+		return provider.lastRates()
+	}
+
+	provider.rates = rates
+	provider.fetchedAt = time.Now()
+	provider.fetchErr = nil
+	return rates, nil
+}
+
+// lastRates returns the previous rates after a failed fetch.
+// The timestamps of the previous rates tell whether they can still be used.
+// It must be called with the mutex locked.
+func (provider *HTTPRateProvider) lastRates() (FXRateProvider, error) {
+	if provider.rates != nil {
+		return provider.rates, nil
+	}
+
+	return nil, fmt.Errorf("%w: %w", ErrRatesUnavailable, provider.fetchErr)
+}
+
+func (provider *HTTPRateProvider) retryInterval() time.Duration {
+	return min(provider.refreshInterval, fetchRetryInterval)
+}
+
+func (provider *HTTPRateProvider) fetch(ctx context.Context) (FXRateProvider, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Accept", "application/json")
+
+	response, err := provider.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchange rates url responded with status %d", response.StatusCode)
+	}
+
+	var file rateFile
+	err = json.NewDecoder(response.Body).Decode(&file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse exchange rates: %w", err)
+	}
+
+	// Live rates must tell when they were published, for their age to be checked
+	for _, rate := range file.Rates {
+		if rate.Timestamp.IsZero() && file.Timestamp.IsZero() {
+			return nil, fmt.Errorf("exchange rate from %s to %s has no timestamp", rate.From, rate.To)
+		}
+	}
+
+	return file.provider()
+}
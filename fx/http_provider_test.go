@@ -0,0 +1,195 @@
+package fx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTPRateProvider(t *testing.T) {
+	_, err := NewHTTPRateProvider("", nil, time.Minute)
+	assert.Error(t, err)
+
+	_, err = NewHTTPRateProvider("https://rates.example.com", nil, 0)
+	assert.Error(t, err)
+
+	provider, err := NewHTTPRateProvider("https://rates.example.com", nil, time.Minute)
+	assert.NoError(t, err)
+	assert.NotNil(t, provider)
+}
+
+func TestHTTPRateProvider(t *testing.T) {
+	requests := 0
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if fail {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"timestamp": "2023-12-10T09:00:00Z", "rates": [{"from": "USD", "to": "EUR", "rate": "0.92"}]}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewHTTPRateProvider(server.URL, nil, time.Hour)
+	assert.NoError(t, err)
+
+	// The same currency doesn't need any rate
+	rate, err := provider.GetRate(context.Background(), "USD", "USD")
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(1).Equal(rate.Rate))
+	assert.Equal(t, 0, requests)
+
+	rate, err = provider.GetRate(context.Background(), "USD", "EUR")
+	assert.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("0.92").Equal(rate.Rate))
+	assert.Equal(t, time.Date(2023, 12, 10, 9, 0, 0, 0, time.UTC), rate.Timestamp.UTC())
+
+	// The rates are fetched once per refresh interval
+	_, err = provider.GetRate(context.Background(), "EUR", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	_, err = provider.GetRate(context.Background(), "USD", "INR")
+	assert.ErrorIs(t, err, ErrRateNotFound)
+
+	// The previous rates are kept when fetching fails
+	fail = true
+	provider.fetchedAt = time.Now().Add(-2 * time.Hour)
+
+	rate, err = provider.GetRate(context.Background(), "USD", "EUR")
+	assert.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("0.92").Equal(rate.Rate))
+	assert.Equal(t, 2, requests)
+}
+
+func TestHTTPRateProviderUnavailable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		response string
+	}{
+		{
+			name:   "ErrorStatus",
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:     "InvalidResponse",
+			status:   http.StatusOK,
+			response: "not json",
+		},
+		{
+			name:     "MissingTimestamp",
+			status:   http.StatusOK,
+			response: `{"rates": [{"from": "USD", "to": "EUR", "rate": "0.92"}]}`,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer server.Close()
+
+			provider, err := NewHTTPRateProvider(server.URL, nil, time.Hour)
+			assert.NoError(t, err)
+
+			_, err = provider.GetRate(context.Background(), "USD", "EUR")
+			assert.ErrorIs(t, err, ErrRatesUnavailable)
+		})
+	}
+}
+
+func TestHTTPRateProviderRetryInterval(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	provider, err := NewHTTPRateProvider(server.URL, nil, time.Hour)
+	assert.NoError(t, err)
+
+	_, err = provider.GetRate(context.Background(), "USD", "EUR")
+	assert.ErrorIs(t, err, ErrRatesUnavailable)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// A failed fetch is not retried before the retry interval
+	_, err = provider.GetRate(context.Background(), "USD", "EUR")
+	assert.ErrorIs(t, err, ErrRatesUnavailable)
+	assert.Equal(t, int32(1), requests.Load())
+
+	provider.failedAt = time.Now().Add(-fetchRetryInterval)
+
+	_, err = provider.GetRate(context.Background(), "USD", "EUR")
+	assert.ErrorIs(t, err, ErrRatesUnavailable)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestHTTPRateProviderConcurrentFetch(t *testing.T) {
+	var requests atomic.Int32
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		received <- struct{}{}
+		<-release
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"timestamp": "2023-12-10T09:00:00Z", "rates": [{"from": "USD", "to": "EUR", "rate": "0.92"}]}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewHTTPRateProvider(server.URL, nil, time.Hour)
+	assert.NoError(t, err)
+
+	// Without previous rates, the requests wait for a single fetch
+	n := 5
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := provider.GetRate(context.Background(), "USD", "EUR")
+			errs <- err
+		}()
+	}
+
+	<-received
+	close(release)
+
+	for i := 0; i < n; i++ {
+		assert.NoError(t, <-errs)
+	}
+	assert.Equal(t, int32(1), requests.Load())
+
+	// With previous rates, they are returned while the rates are fetched again
+	release = make(chan struct{})
+	provider.fetchedAt = time.Now().Add(-2 * time.Hour)
+
+	go func() {
+		_, err := provider.GetRate(context.Background(), "USD", "EUR")
+		errs <- err
+	}()
+
+	<-received
+
+	rate, err := provider.GetRate(context.Background(), "USD", "EUR")
+	assert.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("0.92").Equal(rate.Rate))
+
+	close(release)
+	assert.NoError(t, <-errs)
+	assert.Equal(t, int32(2), requests.Load())
+}
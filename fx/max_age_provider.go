@@ -0,0 +1,40 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MaxAgeRateProvider rejects the rates of another provider published longer than maxAge ago,
+// so that money is never converted at a rate the market has moved away from
+type MaxAgeRateProvider struct {
+	provider FXRateProvider
+	maxAge   time.Duration
+}
+
+// NewMaxAgeRateProvider creates a provider serving the rates of provider not older than maxAge
+func NewMaxAgeRateProvider(provider FXRateProvider, maxAge time.Duration) (*MaxAgeRateProvider, error) {
+	if maxAge <= 0 {
+		return nil, fmt.Errorf("invalid maximum exchange rate age: %s", maxAge)
+	}
+
+	return &MaxAgeRateProvider{
+		provider: provider,
+		maxAge:   maxAge,
+	}, nil
+}
+
+// GetRate returns the rate of the currency pair, or a StaleRateError when it is too old
+func (provider *MaxAgeRateProvider) GetRate(ctx context.Context, from string, to string) (Rate, error) {
+	rate, err := provider.provider.GetRate(ctx, from, to)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	if time.Since(rate.Timestamp) > provider.maxAge {
+		return Rate{}, &StaleRateError{From: from, To: to, Timestamp: rate.Timestamp}
+	}
+
+	return rate, nil
+}
@@ -0,0 +1,39 @@
+package fx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxAgeRateProvider(t *testing.T) {
+	staticProvider, err := NewStaticRateProvider([]Rate{
+		{From: "USD", To: "EUR", Rate: decimal.RequireFromString("0.92"), Timestamp: time.Now().Add(-time.Minute)},
+		{From: "EUR", To: "INR", Rate: decimal.RequireFromString("90.5"), Timestamp: time.Now().Add(-2 * time.Hour)},
+	})
+	assert.NoError(t, err)
+
+	_, err = NewMaxAgeRateProvider(staticProvider, 0)
+	assert.Error(t, err)
+
+	provider, err := NewMaxAgeRateProvider(staticProvider, time.Hour)
+	assert.NoError(t, err)
+
+	rate, err := provider.GetRate(context.Background(), "USD", "EUR")
+	assert.NoError(t, err)
+	assert.True(t, decimal.RequireFromString("0.92").Equal(rate.Rate))
+
+	_, err = provider.GetRate(context.Background(), "EUR", "INR")
+	assert.ErrorIs(t, err, ErrStaleRate)
+
+	var staleErr *StaleRateError
+	assert.ErrorAs(t, err, &staleErr)
+	assert.Equal(t, "EUR", staleErr.From)
+	assert.Equal(t, "INR", staleErr.To)
+
+	_, err = provider.GetRate(context.Background(), "USD", "INR")
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
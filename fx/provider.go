@@ -0,0 +1,60 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrRateNotFound is returned when no rate is known for a currency pair
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// ErrStaleRate is returned when the known rate of a currency pair is too old to be used
+var ErrStaleRate = errors.New("exchange rate is stale")
+
+// Rate is the amount of the To currency bought by one unit of the From currency
+type Rate struct {
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Rate      decimal.Decimal `json:"rate"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// FXRateProvider is an interface for getting exchange rates
+type FXRateProvider interface {
+	// GetRate returns the current rate to convert from one currency to another
+	GetRate(ctx context.Context, from string, to string) (Rate, error)
+}
+
+// RateNotFoundError describes a currency pair without any known rate
+type RateNotFoundError struct {
+	From string
+	To   string
+}
+
+func (e *RateNotFoundError) Error() string {
+	return fmt.Sprintf("exchange rate not found from %s to %s", e.From, e.To)
+}
+
+func (e *RateNotFoundError) Unwrap() error {
+	return ErrRateNotFound
+}
+
+// StaleRateError describes a rate published longer than the maximum rate age ago
+type StaleRateError struct {
+	From      string
+	To        string
+	Timestamp time.Time
+}
+
+func (e *StaleRateError) Error() string {
+	return fmt.Sprintf("exchange rate from %s to %s is stale, published at %s",
+		e.From, e.To, e.Timestamp.UTC().Format(time.RFC3339))
+}
+
+func (e *StaleRateError) Unwrap() error {
+	return ErrStaleRate
+}
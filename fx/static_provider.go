@@ -0,0 +1,73 @@
+package fx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// inverseRatePrecision is the number of decimal places kept when inverting a rate
+const inverseRatePrecision = 12
+
+// StaticRateProvider serves exchange rates from a fixed table
+type StaticRateProvider struct {
+	rates map[string]Rate
+}
+
+// NewStaticRateProvider creates a new StaticRateProvider from the given rates.
+// Rates without timestamp are considered as published now.
+func NewStaticRateProvider(rates []Rate) (FXRateProvider, error) {
+	provider := &StaticRateProvider{
+		rates: make(map[string]Rate, len(rates)),
+	}
+
+	now := time.Now()
+	for _, rate := range rates {
+		if !rate.Rate.IsPositive() {
+			return nil, fmt.Errorf("invalid rate from %s to %s: must be positive", rate.From, rate.To)
+		}
+
+		if rate.Timestamp.IsZero() {
+			rate.Timestamp = now
+		}
+
+		provider.rates[pairKey(rate.From, rate.To)] = rate
+	}
+
+	return provider, nil
+}
+
+// GetRate returns the rate of the currency pair.
+// Converting a currency to itself always uses a rate of 1, and the inverse
+// of the opposite pair is used when the pair itself is not in the table.
+func (provider *StaticRateProvider) GetRate(ctx context.Context, from string, to string) (Rate, error) {
+	if from == to {
+		return Rate{
+			From:      from,
+			To:        to,
+			Rate:      decimal.NewFromInt(1),
+			Timestamp: time.Now(),
+		}, nil
+	}
+
+	if rate, ok := provider.rates[pairKey(from, to)]; ok {
+		return rate, nil
+	}
+
+	if rate, ok := provider.rates[pairKey(to, from)]; ok {
+		return Rate{
+			From:      from,
+			To:        to,
+			Rate:      decimal.NewFromInt(1).DivRound(rate.Rate, inverseRatePrecision),
+			Timestamp: rate.Timestamp,
+		}, nil
+	}
+
+	return Rate{}, &RateNotFoundError{From: from, To: to}
+}
+
+func pairKey(from string, to string) string {
+	return from + "/" + to
+}
@@ -0,0 +1,83 @@
+package fx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticRateProvider(t *testing.T) {
+	timestamp := time.Now().Add(-time.Hour)
+
+	provider, err := NewStaticRateProvider([]Rate{
+		{From: "USD", To: "EUR", Rate: decimal.RequireFromString("0.8"), Timestamp: timestamp},
+		{From: "EUR", To: "INR", Rate: decimal.RequireFromString("90")},
+	})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		from         string
+		to           string
+		expectedRate decimal.Decimal
+		expectedErr  error
+	}{
+		{
+			name:         "DirectPair",
+			from:         "USD",
+			to:           "EUR",
+			expectedRate: decimal.RequireFromString("0.8"),
+		},
+		{
+			name:         "InversePair",
+			from:         "EUR",
+			to:           "USD",
+			expectedRate: decimal.RequireFromString("1.25"),
+		},
+		{
+			name:         "SameCurrency",
+			from:         "INR",
+			to:           "INR",
+			expectedRate: decimal.NewFromInt(1),
+		},
+		{
+			name:        "UnknownPair",
+			from:        "USD",
+			to:          "INR",
+			expectedErr: ErrRateNotFound,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := provider.GetRate(context.Background(), tc.from, tc.to)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.from, rate.From)
+			assert.Equal(t, tc.to, rate.To)
+			assert.True(t, tc.expectedRate.Equal(rate.Rate), "expected %s, got %s", tc.expectedRate, rate.Rate)
+			assert.False(t, rate.Timestamp.IsZero())
+		})
+	}
+
+	rate, err := provider.GetRate(context.Background(), "USD", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, timestamp, rate.Timestamp)
+}
+
+func TestStaticRateProviderInvalidRate(t *testing.T) {
+	provider, err := NewStaticRateProvider([]Rate{
+		{From: "USD", To: "EUR", Rate: decimal.Zero},
+	})
+	assert.Error(t, err)
+	assert.Nil(t, provider)
+}
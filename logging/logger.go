@@ -0,0 +1,125 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// Formats of the logs
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// RequestIDKey is the key of the request id in the log records
+const RequestIDKey = "request_id"
+
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeyParts are the parts of the keys whose values are never logged
+var sensitiveKeyParts = []string{"password", "token", "secret", "authorization"}
+
+// NewLogger creates a logger writing records of level and above to w, in format.
+// The values of the sensitive attributes are redacted, and the request id
+// found in the context of a record is added to it.
+func NewLogger(w io.Writer, format string, level string) (*slog.Logger, error) {
+	var logLevel slog.Level
+	if level != "" {
+		err := logLevel.UnmarshalText([]byte(level))
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level: %w", err)
+		}
+	}
+
+	opts := &slog.HandlerOptions{
+		Level:       logLevel,
+		ReplaceAttr: redactAttr,
+	}
+
+	var handler slog.Handler
+	switch format {
+	case FormatJSON, "":
+		handler = slog.NewJSONHandler(w, opts)
+	case FormatText:
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format: %q", format)
+	}
+
+	return slog.New(contextHandler{handler}), nil
+}
+
+// IsSensitive tells whether the values of key must be redacted,
+// e.g. passwords, tokens, secrets and authorization headers
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if IsSensitive(attr.Key) {
+		return slog.String(attr.Key, redactedValue)
+	}
+
+	return attr
+}
+
+// RedactURL returns the path and the query of u, with the values of the sensitive parameters redacted
+func RedactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Path
+	}
+
+	query := u.Query()
+	for key := range query {
+		if IsSensitive(key) {
+			query[key] = []string{redactedValue}
+		}
+	}
+
+	return u.Path + "?" + query.Encode()
+}
+
+type requestIDContextKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the id of the request being served
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFrom returns the request id carried by ctx, or an empty string
+func RequestIDFrom(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// contextHandler adds the request id of the context to the records
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestIDFrom(ctx); requestID != "" {
+		record.AddAttrs(slog.String(RequestIDKey, requestID))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	logger, err := NewLogger(&buffer, FormatJSON, "warn")
+	assert.NoError(t, err)
+
+	logger.Info("ignored")
+	assert.Zero(t, buffer.Len())
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	logger.WarnContext(ctx, "login failed", slog.String("user", "user1"), slog.String("password", "secret"))
+
+	var record map[string]any
+	err = json.Unmarshal(buffer.Bytes(), &record)
+	assert.NoError(t, err)
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "login failed", record["msg"])
+	assert.Equal(t, "user1", record["user"])
+	assert.Equal(t, redactedValue, record["password"])
+	assert.Equal(t, "req-123", record[RequestIDKey])
+}
+
+func TestNewLoggerWithAttrs(t *testing.T) {
+	var buffer bytes.Buffer
+	logger, err := NewLogger(&buffer, FormatText, "")
+	assert.NoError(t, err)
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	logger.With(slog.String("refresh_token", "v2.local.token")).InfoContext(ctx, "token renewed")
+
+	assert.Contains(t, buffer.String(), "refresh_token="+redactedValue)
+	assert.Contains(t, buffer.String(), "request_id=req-123")
+	assert.NotContains(t, buffer.String(), "v2.local.token")
+}
+
+func TestNewLoggerInvalidConfig(t *testing.T) {
+	_, err := NewLogger(&bytes.Buffer{}, "xml", "info")
+	assert.Error(t, err)
+
+	_, err = NewLogger(&bytes.Buffer{}, FormatJSON, "verbose")
+	assert.Error(t, err)
+}
+
+func TestIsSensitive(t *testing.T) {
+	for _, key := range []string{"password", "hash_password", "access_token", "RefreshToken", "secret", "Authorization"} {
+		assert.True(t, IsSensitive(key), key)
+	}
+
+	for _, key := range []string{"user", "request_id", "status", "path"} {
+		assert.False(t, IsSensitive(key), key)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	u, err := url.Parse("/api/accounts/1/transfers?page_id=1&token=abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/accounts/1/transfers?page_id=1&token=%5BREDACTED%5D", RedactURL(u))
+
+	u, err = url.Parse("/api/accounts")
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/accounts", RedactURL(u))
+}
+
+func TestRequestIDFrom(t *testing.T) {
+	assert.Empty(t, RequestIDFrom(context.Background()))
+	assert.Equal(t, "req-123", RequestIDFrom(WithRequestID(context.Background(), "req-123")))
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxQueryLength is the length above which the queries without name are truncated in the logs
+const maxQueryLength = 200
+
+// QueryTracer is a pgx tracer logging the queries at debug level, and the failing ones
+// at warn level, with the request id of their context. The arguments of the queries
+// are never logged, as they contain password hashes and tokens.
+type QueryTracer struct {
+	logger *slog.Logger
+}
+
+// NewQueryTracer creates a tracer logging the queries to logger
+func NewQueryTracer(logger *slog.Logger) *QueryTracer {
+	return &QueryTracer{
+		logger: logger,
+	}
+}
+
+type queryStartContextKey struct{}
+
+type queryStart struct {
+	query     string
+	startedAt time.Time
+}
+
+// TraceQueryStart keeps the query and its start time in the context returned to pgx
+func (tracer *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartContextKey{}, queryStart{
+		query:     queryName(data.SQL),
+		startedAt: time.Now(),
+	})
+}
+
+// TraceQueryEnd logs the query with its duration
+func (tracer *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartContextKey{}).(queryStart)
+	if !ok {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("query", start.query),
+		slog.Duration("duration", time.Since(start.startedAt)),
+	}
+
+	if data.Err != nil {
+		attrs = append(attrs, slog.String("error", data.Err.Error()))
+		tracer.logger.LogAttrs(ctx, slog.LevelWarn, "query failed", attrs...)
+		return
+	}
+
+	attrs = append(attrs, slog.Int64("rows", data.CommandTag.RowsAffected()))
+	tracer.logger.LogAttrs(ctx, slog.LevelDebug, "query", attrs...)
+}
+
+// queryName returns the name of the queries generated by sqlc,
+// and the other queries on one line
+func queryName(sql string) string {
+	if name, ok := strings.CutPrefix(sql, "-- name: "); ok {
+		name, _, _ = strings.Cut(name, " ")
+		return name
+	}
+
+	sql = strings.Join(strings.Fields(sql), " ")
+	if len(sql) > maxQueryLength {
+		return sql[:maxQueryLength] + "..."
+	}
+
+	return sql
+}
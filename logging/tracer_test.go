@@ -0,0 +1,69 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryTracer(t *testing.T) {
+	var buffer bytes.Buffer
+	logger, err := NewLogger(&buffer, FormatJSON, "debug")
+	assert.NoError(t, err)
+
+	tracer := NewQueryTracer(logger)
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	ctx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{
+		SQL:  "-- name: CreateUser :one\nINSERT INTO users (name, hash_password) VALUES ($1, $2)",
+		Args: []any{"user1", "$2a$10$hash"},
+	})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{
+		CommandTag: pgconn.NewCommandTag("INSERT 0 1"),
+	})
+
+	var record map[string]any
+	err = json.Unmarshal(buffer.Bytes(), &record)
+	assert.NoError(t, err)
+	assert.Equal(t, "DEBUG", record["level"])
+	assert.Equal(t, "CreateUser", record["query"])
+	assert.Equal(t, float64(1), record["rows"])
+	assert.Equal(t, "req-123", record[RequestIDKey])
+	assert.Contains(t, record, "duration")
+
+	// The arguments are never logged
+	assert.NotContains(t, buffer.String(), "$2a$10$hash")
+}
+
+func TestQueryTracerError(t *testing.T) {
+	var buffer bytes.Buffer
+	logger, err := NewLogger(&buffer, FormatJSON, "info")
+	assert.NoError(t, err)
+
+	tracer := NewQueryTracer(logger)
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "commit"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("conn closed")})
+
+	var record map[string]any
+	err = json.Unmarshal(buffer.Bytes(), &record)
+	assert.NoError(t, err)
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "commit", record["query"])
+	assert.Equal(t, "conn closed", record["error"])
+}
+
+func TestQueryName(t *testing.T) {
+	assert.Equal(t, "GetAccount", queryName("-- name: GetAccount :one\nSELECT * FROM accounts WHERE id = $1"))
+	assert.Equal(t, "SELECT 1 FROM accounts", queryName("SELECT 1\n  FROM accounts"))
+
+	long := "SELECT " + strings.Repeat("a", maxQueryLength)
+	assert.Equal(t, long[:maxQueryLength]+"...", queryName(long))
+}
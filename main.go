@@ -2,31 +2,167 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
 
 	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/api"
 	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/events"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/fx"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/logging"
 	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/webhook"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/worker"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func main() {
+	err := run()
+	if err != nil {
+		slog.Error("Failed to run the server", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+// run starts the workers and the server, and returns when the server stops.
+// Its resources are released before main exits.
+func run() error {
 	config, err := utils.LoadConfig("./")
 	if err != nil {
-		log.Fatal("fatal error while reading config file", err)
+		return fmt.Errorf("fatal error while reading config file: %w", err)
+	}
+
+	logger, err := logging.NewLogger(os.Stdout, config.LogFormat, config.LogLevel)
+	if err != nil {
+		return fmt.Errorf("failed to create the logger: %w", err)
 	}
 
-	connPool, err := pgxpool.New(context.Background(), config.DbUrl)
+	// main logs the error of run with the default logger
+	slog.SetDefault(logger)
+
+	poolConfig, err := pgxpool.ParseConfig(config.DbUrl)
+	if err != nil {
+		return fmt.Errorf("failed to parse the db url: %w", err)
+	}
+	poolConfig.ConnConfig.Tracer = logging.NewQueryTracer(logger)
+
+	connPool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to db: %w", err)
+	}
 	defer connPool.Close()
+
+	rateProvider, err := newRateProvider(config)
+	if err != nil {
+		return fmt.Errorf("failed to create the exchange rate provider: %w", err)
+	}
+
+	store := db.NewStore(connPool, db.WithFrozenAccountsBlockIncoming(config.FrozenAccountsBlockIncoming))
+
+	holdExpirer, err := worker.NewHoldExpirer(store, config.HoldExpiryInterval, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create the hold expirer: %w", err)
+	}
+
+	idempotencyKeyCleaner, err := worker.NewIdempotencyKeyCleaner(store, config.IdempotencyCleanupInterval, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create the idempotency key cleaner: %w", err)
+	}
+
+	transferScheduler, err := worker.NewTransferScheduler(store, config.SchedulerInterval, config.SchedulerBatchSize, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create the transfer scheduler: %w", err)
+	}
+
+	eventPublisher, err := newEventPublisher(config)
 	if err != nil {
-		log.Fatal("Failed to connect to db", err)
+		return fmt.Errorf("failed to create the event publisher: %w", err)
+	}
+
+	if closer, ok := eventPublisher.(io.Closer); ok {
+		defer closer.Close()
 	}
 
-	store := db.NewStore(connPool)
-	server := api.NewServer(store)
+	outboxRelay, err := worker.NewOutboxRelay(store, eventPublisher, config.OutboxRelayInterval, config.OutboxBatchSize, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create the outbox relay: %w", err)
+	}
+
+	webhookDispatcher, err := worker.NewWebhookDispatcher(
+		store,
+		webhook.NewSender(nil),
+		config.WebhookDispatchInterval,
+		config.WebhookBatchSize,
+		config.WebhookMaxAttempts,
+		logger)
+	if err != nil {
+		return fmt.Errorf("failed to create the webhook dispatcher: %w", err)
+	}
+
+	auditChainer, err := worker.NewAuditChainer(store, config.AuditChainInterval, config.AuditChainBatchSize, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create the audit chainer: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go auditChainer.Run(ctx)
+	go holdExpirer.Run(ctx)
+	go idempotencyKeyCleaner.Run(ctx)
+	go transferScheduler.Run(ctx)
+	go outboxRelay.Run(ctx)
+	go webhookDispatcher.Run(ctx)
+
+	server, err := api.NewServer(config, store, rateProvider, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create the server: %w", err)
+	}
 
 	err = server.Start(config.ServerAddress)
 	if err != nil {
-		log.Fatal("Failed to start the server", err)
+		return fmt.Errorf("failed to start the server: %w", err)
+	}
+
+	return nil
+}
+
+// newRateProvider serves the live exchange rates of the configured URL, or the rates
+// of the configured file in development, rejecting the rates older than the maximum age.
+// Without any of them, only transfers between accounts of the same currency are possible.
+func newRateProvider(config utils.Config) (fx.FXRateProvider, error) {
+	var provider fx.FXRateProvider
+	var err error
+
+	switch {
+	case config.FXRatesURL != "" && config.FXRatesFile != "":
+		return nil, errors.New("only one of the exchange rates url and file can be configured")
+	case config.FXRatesURL != "":
+		provider, err = fx.NewHTTPRateProvider(config.FXRatesURL, nil, config.FXRatesRefreshInterval)
+	case config.FXRatesFile != "":
+		provider, err = fx.NewFileRateProvider(config.FXRatesFile)
+	default:
+		provider, err = fx.NewStaticRateProvider(nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return fx.NewMaxAgeRateProvider(provider, config.FXMaxRateAge)
+}
+
+// newEventPublisher creates the publisher the outbox events are relayed to
+func newEventPublisher(config utils.Config) (events.EventPublisher, error) {
+	switch config.EventPublisher {
+	case "memory":
+		return events.NewMemoryPublisher(), nil
+	case "file":
+		return events.NewFilePublisher(config.EventFile)
+	case "http":
+		return events.NewHTTPPublisher(config.EventWebhookURL, nil)
+	default:
+		return nil, fmt.Errorf("unknown event publisher: %q", config.EventPublisher)
 	}
 }
@@ -0,0 +1,224 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/shopspring/decimal"
+)
+
+// Modes of a reconciliation run
+const (
+	ModeDryRun = "dry-run"
+	ModeApply  = "apply"
+)
+
+// BalanceMismatch is an account whose balance differs from the sum of its entries
+type BalanceMismatch struct {
+	AccountID    int64           `json:"account_id"`
+	Currency     db.Currency     `json:"currency"`
+	Balance      decimal.Decimal `json:"balance"`
+	EntriesTotal decimal.Decimal `json:"entries_total"`
+	Difference   decimal.Decimal `json:"difference"`
+	Repaired     bool            `json:"repaired"`
+}
+
+// OrphanEntry is an entry which belongs to no transfer nor balance adjustment
+type OrphanEntry struct {
+	ID        int64           `json:"id"`
+	AccountID int64           `json:"account_id"`
+	Amount    decimal.Decimal `json:"amount"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// OrphanTransfer is a transfer missing its from or to entry
+type OrphanTransfer struct {
+	ID            int64           `json:"id"`
+	FromAccountID int64           `json:"from_account_id"`
+	ToAccountID   int64           `json:"to_account_id"`
+	Amount        decimal.Decimal `json:"amount"`
+	ToAmount      decimal.Decimal `json:"to_amount"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// Report is the outcome of a reconciliation run
+type Report struct {
+	Mode              string            `json:"mode"`
+	StartedAt         time.Time         `json:"started_at"`
+	FinishedAt        time.Time         `json:"finished_at"`
+	AccountsChecked   int               `json:"accounts_checked"`
+	BalanceMismatches []BalanceMismatch `json:"balance_mismatches"`
+	OrphanEntries     []OrphanEntry     `json:"orphan_entries"`
+	OrphanTransfers   []OrphanTransfer  `json:"orphan_transfers"`
+}
+
+// HasDiscrepancies tells if the run found anything wrong,
+// even if it was repaired afterwards
+func (report Report) HasDiscrepancies() bool {
+	return len(report.BalanceMismatches) > 0 ||
+		len(report.OrphanEntries) > 0 ||
+		len(report.OrphanTransfers) > 0
+}
+
+// Reconciler checks the ledger for drift between the account balances
+// and their entries, and for entries and transfers which don't add up
+type Reconciler struct {
+	store     db.Store
+	batchSize int32
+}
+
+// NewReconciler creates a reconciler reading the ledger in batches of batchSize rows
+func NewReconciler(store db.Store, batchSize int32) (*Reconciler, error) {
+	if batchSize < 1 {
+		return nil, fmt.Errorf("invalid batch size: %d", batchSize)
+	}
+
+	return &Reconciler{
+		store:     store,
+		batchSize: batchSize,
+	}, nil
+}
+
+// Run scans the whole ledger and reports the discrepancies.
+// When apply is set, mismatching balances are reset to the sum of their entries.
+// Orphan entries and transfers are only reported, they need a human to look at them.
+func (r *Reconciler) Run(ctx context.Context, apply bool) (Report, error) {
+	report := Report{
+		Mode:              ModeDryRun,
+		StartedAt:         time.Now(),
+		BalanceMismatches: []BalanceMismatch{},
+		OrphanEntries:     []OrphanEntry{},
+		OrphanTransfers:   []OrphanTransfer{},
+	}
+
+	if apply {
+		report.Mode = ModeApply
+	}
+
+	err := r.checkBalances(ctx, apply, &report)
+	if err != nil {
+		return report, fmt.Errorf("cannot check balances: %w", err)
+	}
+
+	err = r.findOrphanEntries(ctx, &report)
+	if err != nil {
+		return report, fmt.Errorf("cannot find orphan entries: %w", err)
+	}
+
+	err = r.findOrphanTransfers(ctx, &report)
+	if err != nil {
+		return report, fmt.Errorf("cannot find orphan transfers: %w", err)
+	}
+
+	report.FinishedAt = time.Now()
+
+	return report, nil
+}
+
+func (r *Reconciler) checkBalances(ctx context.Context, apply bool, report *Report) error {
+	var afterID int64
+
+	for {
+		rows, err := r.store.ListAccountBalances(ctx, db.ListAccountBalancesParams{
+			AfterID:    afterID,
+			LimitCount: r.batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			report.AccountsChecked++
+			if row.Balance.Equal(row.EntriesTotal) {
+				continue
+			}
+
+			mismatch := BalanceMismatch{
+				AccountID:    row.ID,
+				Currency:     row.Currency,
+				Balance:      row.Balance,
+				EntriesTotal: row.EntriesTotal,
+				Difference:   row.Balance.Sub(row.EntriesTotal),
+			}
+
+			if apply {
+				result, err := r.store.RepairBalanceTx(ctx, row.ID)
+				if err != nil {
+					return fmt.Errorf("cannot repair account [%d]: %w", row.ID, err)
+				}
+
+				mismatch.Repaired = result.Repaired
+			}
+
+			report.BalanceMismatches = append(report.BalanceMismatches, mismatch)
+		}
+
+		if len(rows) < int(r.batchSize) {
+			return nil
+		}
+
+		afterID = rows[len(rows)-1].ID
+	}
+}
+
+func (r *Reconciler) findOrphanEntries(ctx context.Context, report *Report) error {
+	var afterID int64
+
+	for {
+		entries, err := r.store.ListOrphanEntries(ctx, db.ListOrphanEntriesParams{
+			AfterID:    afterID,
+			LimitCount: r.batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			report.OrphanEntries = append(report.OrphanEntries, OrphanEntry{
+				ID:        entry.ID,
+				AccountID: entry.AccountID,
+				Amount:    entry.Amount,
+				CreatedAt: entry.CreatedAt.Time,
+			})
+		}
+
+		if len(entries) < int(r.batchSize) {
+			return nil
+		}
+
+		afterID = entries[len(entries)-1].ID
+	}
+}
+
+func (r *Reconciler) findOrphanTransfers(ctx context.Context, report *Report) error {
+	var afterID int64
+
+	for {
+		transfers, err := r.store.ListOrphanTransfers(ctx, db.ListOrphanTransfersParams{
+			AfterID:    afterID,
+			LimitCount: r.batchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, transfer := range transfers {
+			report.OrphanTransfers = append(report.OrphanTransfers, OrphanTransfer{
+				ID:            transfer.ID,
+				FromAccountID: transfer.FromAccountID,
+				ToAccountID:   transfer.ToAccountID,
+				Amount:        transfer.Amount,
+				ToAmount:      transfer.ToAmount,
+				CreatedAt:     transfer.CreatedAt.Time,
+			})
+		}
+
+		if len(transfers) < int(r.batchSize) {
+			return nil
+		}
+
+		afterID = transfers[len(transfers)-1].ID
+	}
+}
@@ -0,0 +1,178 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func accountBalance(id int64, balance string, entriesTotal string) db.ListAccountBalancesRow {
+	return db.ListAccountBalancesRow{
+		ID:           id,
+		Currency:     db.CurrencyUSD,
+		Balance:      decimal.RequireFromString(balance),
+		EntriesTotal: decimal.RequireFromString(entriesTotal),
+	}
+}
+
+func TestReconcilerRun(t *testing.T) {
+	testCases := []struct {
+		name          string
+		apply         bool
+		buildStubFunc func(store *mockdb.MockStore)
+		validateRun   func(report Report, err error)
+	}{
+		{
+			name: "Clean",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountBalances(gomock.Any(), gomock.Eq(db.ListAccountBalancesParams{AfterID: 0, LimitCount: 2})).
+					Times(1).
+					Return([]db.ListAccountBalancesRow{accountBalance(1, "10", "10.00")}, nil)
+				store.EXPECT().ListOrphanEntries(gomock.Any(), gomock.Any()).Times(1).Return([]db.Entry{}, nil)
+				store.EXPECT().ListOrphanTransfers(gomock.Any(), gomock.Any()).Times(1).Return([]db.Transfer{}, nil)
+				store.EXPECT().RepairBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateRun: func(report Report, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, ModeDryRun, report.Mode)
+				assert.Equal(t, 1, report.AccountsChecked)
+				assert.Empty(t, report.BalanceMismatches)
+				assert.False(t, report.HasDiscrepancies())
+			},
+		},
+		{
+			name: "DryRunReportsAcrossBatches",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountBalances(gomock.Any(), gomock.Eq(db.ListAccountBalancesParams{AfterID: 0, LimitCount: 2})).
+					Times(1).
+					Return([]db.ListAccountBalancesRow{
+						accountBalance(1, "10", "10"),
+						accountBalance(2, "50", "20"),
+					}, nil)
+				store.EXPECT().
+					ListAccountBalances(gomock.Any(), gomock.Eq(db.ListAccountBalancesParams{AfterID: 2, LimitCount: 2})).
+					Times(1).
+					Return([]db.ListAccountBalancesRow{accountBalance(3, "-5", "0")}, nil)
+
+				store.EXPECT().
+					ListOrphanEntries(gomock.Any(), gomock.Eq(db.ListOrphanEntriesParams{AfterID: 0, LimitCount: 2})).
+					Times(1).
+					Return([]db.Entry{{ID: 7, AccountID: 1, Amount: decimal.NewFromInt(3)}}, nil)
+				store.EXPECT().
+					ListOrphanTransfers(gomock.Any(), gomock.Eq(db.ListOrphanTransfersParams{AfterID: 0, LimitCount: 2})).
+					Times(1).
+					Return([]db.Transfer{{ID: 8, FromAccountID: 1, ToAccountID: 2}, {ID: 9, FromAccountID: 2, ToAccountID: 3}}, nil)
+				store.EXPECT().
+					ListOrphanTransfers(gomock.Any(), gomock.Eq(db.ListOrphanTransfersParams{AfterID: 9, LimitCount: 2})).
+					Times(1).
+					Return([]db.Transfer{}, nil)
+
+				store.EXPECT().RepairBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateRun: func(report Report, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 3, report.AccountsChecked)
+				assert.True(t, report.HasDiscrepancies())
+
+				assert.Len(t, report.BalanceMismatches, 2)
+				assert.Equal(t, int64(2), report.BalanceMismatches[0].AccountID)
+				assert.True(t, decimal.NewFromInt(30).Equal(report.BalanceMismatches[0].Difference))
+				assert.False(t, report.BalanceMismatches[0].Repaired)
+				assert.Equal(t, int64(3), report.BalanceMismatches[1].AccountID)
+				assert.True(t, decimal.NewFromInt(-5).Equal(report.BalanceMismatches[1].Difference))
+
+				assert.Len(t, report.OrphanEntries, 1)
+				assert.Equal(t, int64(7), report.OrphanEntries[0].ID)
+				assert.Len(t, report.OrphanTransfers, 2)
+			},
+		},
+		{
+			name:  "ApplyRepairsBalances",
+			apply: true,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountBalances(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListAccountBalancesRow{accountBalance(2, "50", "20")}, nil)
+				store.EXPECT().
+					RepairBalanceTx(gomock.Any(), gomock.Eq(int64(2))).
+					Times(1).
+					Return(db.RepairBalanceTxResult{Repaired: true}, nil)
+				store.EXPECT().ListOrphanEntries(gomock.Any(), gomock.Any()).Times(1).Return([]db.Entry{}, nil)
+				store.EXPECT().ListOrphanTransfers(gomock.Any(), gomock.Any()).Times(1).Return([]db.Transfer{}, nil)
+			},
+			validateRun: func(report Report, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, ModeApply, report.Mode)
+				assert.Len(t, report.BalanceMismatches, 1)
+				assert.True(t, report.BalanceMismatches[0].Repaired)
+
+				// Repaired mismatches are still reported as discrepancies
+				assert.True(t, report.HasDiscrepancies())
+			},
+		},
+		{
+			name:  "RepairError",
+			apply: true,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountBalances(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListAccountBalancesRow{accountBalance(2, "50", "20")}, nil)
+				store.EXPECT().
+					RepairBalanceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RepairBalanceTxResult{}, pgx.ErrTxClosed)
+				store.EXPECT().ListOrphanEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateRun: func(report Report, err error) {
+				assert.ErrorIs(t, err, pgx.ErrTxClosed)
+			},
+		},
+		{
+			name: "StoreError",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListAccountBalances(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, pgx.ErrTxClosed)
+				store.EXPECT().ListOrphanEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateRun: func(report Report, err error) {
+				assert.ErrorIs(t, err, pgx.ErrTxClosed)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			reconciler, err := NewReconciler(store, 2)
+			assert.NoError(t, err)
+
+			report, err := reconciler.Run(context.Background(), tc.apply)
+			tc.validateRun(report, err)
+		})
+	}
+}
+
+func TestNewReconcilerInvalidBatchSize(t *testing.T) {
+	reconciler, err := NewReconciler(nil, 0)
+	assert.Error(t, err)
+	assert.Nil(t, reconciler)
+}
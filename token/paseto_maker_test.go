@@ -0,0 +1,99 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasetoMaker(t *testing.T) {
+	maker, err := NewPasetoMaker(utils.RandomString(32))
+	assert.NoError(t, err)
+
+	username := utils.RandomString(6)
+	role := utils.DepositorRole
+	duration := time.Minute
+
+	issuedAt := time.Now()
+	expiredAt := issuedAt.Add(duration)
+
+	token, payload, err := maker.CreateToken(username, role, TokenTypeAccess, duration)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token, TokenTypeAccess)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, payload)
+
+	assert.NotZero(t, payload.ID)
+	assert.Equal(t, username, payload.Username)
+	assert.Equal(t, role, payload.Role)
+	assert.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+	assert.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+}
+
+func TestExpiredPasetoToken(t *testing.T) {
+	maker, err := NewPasetoMaker(utils.RandomString(32))
+	assert.NoError(t, err)
+
+	token, payload, err := maker.CreateToken(utils.RandomString(6), utils.DepositorRole, TokenTypeAccess, -time.Minute)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, payload)
+
+	payload, err = maker.VerifyToken(token, TokenTypeAccess)
+	assert.Error(t, err)
+	assert.EqualError(t, err, ErrExpiredToken.Error())
+	assert.Nil(t, payload)
+}
+
+func TestTamperedPasetoToken(t *testing.T) {
+	maker, err := NewPasetoMaker(utils.RandomString(32))
+	assert.NoError(t, err)
+
+	token, _, err := maker.CreateToken(utils.RandomString(6), utils.DepositorRole, TokenTypeAccess, time.Minute)
+	assert.NoError(t, err)
+
+	// Flip a bit of a character in the middle of the token
+	i := len(token) / 2
+	tampered := token[:i] + string(token[i]^1) + token[i+1:]
+
+	payload, err := maker.VerifyToken(tampered, TokenTypeAccess)
+	assert.Error(t, err)
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+	assert.Nil(t, payload)
+
+	// Token encrypted with another key must be rejected
+	otherMaker, err := NewPasetoMaker(utils.RandomString(32))
+	assert.NoError(t, err)
+
+	payload, err = otherMaker.VerifyToken(token, TokenTypeAccess)
+	assert.Error(t, err)
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+	assert.Nil(t, payload)
+}
+
+func TestInvalidPasetoKeySize(t *testing.T) {
+	maker, err := NewPasetoMaker(utils.RandomString(31))
+	assert.Error(t, err)
+	assert.Nil(t, maker)
+}
+
+func TestWrongTypePasetoToken(t *testing.T) {
+	maker, err := NewPasetoMaker(utils.RandomString(32))
+	assert.NoError(t, err)
+
+	token, _, err := maker.CreateToken(utils.RandomString(6), utils.DepositorRole, TokenTypeRefresh, time.Minute)
+	assert.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token, TokenTypeAccess)
+	assert.EqualError(t, err, ErrInvalidToken.Error())
+	assert.Nil(t, payload)
+
+	payload, err = maker.VerifyToken(token, TokenTypeRefresh)
+	assert.NoError(t, err)
+	assert.Equal(t, TokenTypeRefresh, payload.Type)
+}
@@ -0,0 +1,103 @@
+package token
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Different types of error returned by the VerifyToken function
+var (
+	ErrInvalidToken = errors.New("token is invalid")
+	ErrExpiredToken = errors.New("token has expired")
+)
+
+// TokenType tells what a token can be used for
+type TokenType string
+
+// Types of token. Access tokens authorize the requests,
+// refresh tokens can only be used to get new access tokens.
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+// Payload contains the payload data of the token
+type Payload struct {
+	ID        uuid.UUID `json:"id"`
+	Type      TokenType `json:"type"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+// NewPayload creates a new token payload with a specific username, role, type and duration
+func NewPayload(username string, role string, tokenType TokenType, duration time.Duration) (*Payload, error) {
+	tokenID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &Payload{
+		ID:        tokenID,
+		Type:      tokenType,
+		Username:  username,
+		Role:      role,
+		IssuedAt:  time.Now(),
+		ExpiredAt: time.Now().Add(duration),
+	}
+
+	return payload, nil
+}
+
+// Valid checks if the token payload is valid or not
+func (payload *Payload) Valid() error {
+	if time.Now().After(payload.ExpiredAt) {
+		return ErrExpiredToken
+	}
+
+	return nil
+}
+
+// checkType makes sure the token has the expected type, so that
+// a refresh token cannot be used as an access token and conversely
+func (payload *Payload) checkType(tokenType TokenType) error {
+	if payload.Type != tokenType {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+// GetExpirationTime implements jwt.Claims
+func (payload *Payload) GetExpirationTime() (*jwt.NumericDate, error) {
+	return jwt.NewNumericDate(payload.ExpiredAt), nil
+}
+
+// GetIssuedAt implements jwt.Claims
+func (payload *Payload) GetIssuedAt() (*jwt.NumericDate, error) {
+	return jwt.NewNumericDate(payload.IssuedAt), nil
+}
+
+// GetNotBefore implements jwt.Claims
+func (payload *Payload) GetNotBefore() (*jwt.NumericDate, error) {
+	return nil, nil
+}
+
+// GetIssuer implements jwt.Claims
+func (payload *Payload) GetIssuer() (string, error) {
+	return "", nil
+}
+
+// GetSubject implements jwt.Claims
+func (payload *Payload) GetSubject() (string, error) {
+	return payload.Username, nil
+}
+
+// GetAudience implements jwt.Claims
+func (payload *Payload) GetAudience() (jwt.ClaimStrings, error) {
+	return nil, nil
+}
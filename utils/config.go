@@ -1,19 +1,47 @@
 package utils
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 // Config stores all configuration of the application
 // The values are read using viper from .env file or environment variables
 type Config struct {
-	DbHost        string `mapstructure:"DATABASE_HOST"`
-	DbName        string `mapstructure:"DATABASE_NAME"`
-	DbUser        string `mapstructure:"DATABASE_USER"`
-	DbPassword    string `mapstructure:"DATABASE_PASSWORD"`
-	DbPort        int32  `mapstructure:"DATABASE_PORT"`
-	DbUrl         string `mapstructure:"DATABASE_URL"`
-	ServerAddress string `mapstructure:"SERVER_ADDRESS"`
+	DbHost                      string        `mapstructure:"DATABASE_HOST"`
+	DbName                      string        `mapstructure:"DATABASE_NAME"`
+	DbUser                      string        `mapstructure:"DATABASE_USER"`
+	DbPassword                  string        `mapstructure:"DATABASE_PASSWORD"`
+	DbPort                      int32         `mapstructure:"DATABASE_PORT"`
+	DbUrl                       string        `mapstructure:"DATABASE_URL"`
+	ServerAddress               string        `mapstructure:"SERVER_ADDRESS"`
+	TokenSymmetricKey           string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	AccessTokenDuration         time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration        time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	IdempotencyKeyTTL           time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	IdempotencyCleanupInterval  time.Duration `mapstructure:"IDEMPOTENCY_CLEANUP_INTERVAL"`
+	FXRatesURL                  string        `mapstructure:"FX_RATES_URL"`
+	FXRatesRefreshInterval      time.Duration `mapstructure:"FX_RATES_REFRESH_INTERVAL"`
+	FXRatesFile                 string        `mapstructure:"FX_RATES_FILE"`
+	FXMaxRateAge                time.Duration `mapstructure:"FX_MAX_RATE_AGE"`
+	CurrencyCacheTTL            time.Duration `mapstructure:"CURRENCY_CACHE_TTL"`
+	FrozenAccountsBlockIncoming bool          `mapstructure:"FROZEN_ACCOUNTS_BLOCK_INCOMING"`
+	HoldExpiryInterval          time.Duration `mapstructure:"HOLD_EXPIRY_INTERVAL"`
+	SchedulerInterval           time.Duration `mapstructure:"SCHEDULER_INTERVAL"`
+	SchedulerBatchSize          int           `mapstructure:"SCHEDULER_BATCH_SIZE"`
+	EventPublisher              string        `mapstructure:"EVENT_PUBLISHER"`
+	EventFile                   string        `mapstructure:"EVENT_FILE"`
+	EventWebhookURL             string        `mapstructure:"EVENT_WEBHOOK_URL"`
+	OutboxRelayInterval         time.Duration `mapstructure:"OUTBOX_RELAY_INTERVAL"`
+	OutboxBatchSize             int32         `mapstructure:"OUTBOX_BATCH_SIZE"`
+	WebhookDispatchInterval     time.Duration `mapstructure:"WEBHOOK_DISPATCH_INTERVAL"`
+	WebhookBatchSize            int32         `mapstructure:"WEBHOOK_BATCH_SIZE"`
+	WebhookMaxAttempts          int32         `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	AuditChainInterval          time.Duration `mapstructure:"AUDIT_CHAIN_INTERVAL"`
+	AuditChainBatchSize         int32         `mapstructure:"AUDIT_CHAIN_BATCH_SIZE"`
+	LogFormat                   string        `mapstructure:"LOG_FORMAT"`
+	LogLevel                    string        `mapstructure:"LOG_LEVEL"`
 }
 
 // LoadConfig loads configuration from .env file and environment variables
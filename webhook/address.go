@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrInsecureURL is returned when the url of an endpoint is not an https url
+	ErrInsecureURL = errors.New("webhook url must be an https url")
+
+	// ErrForbiddenAddress is returned when the host of an endpoint is, or resolves to,
+	// an address of the internal network
+	ErrForbiddenAddress = errors.New("webhook address is not allowed")
+)
+
+const dialTimeout = 5 * time.Second
+
+// forbiddenPrefixes are the ranges which are not covered by the net.IP helpers,
+// the shared address space of carrier-grade NAT and the IPv4/IPv6 translation prefix
+var forbiddenPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// ValidateURL checks that rawURL is an https url whose host is not a loopback,
+// private, link-local or metadata address. Host names are checked again against
+// the addresses they resolve to when the deliveries are sent.
+func ValidateURL(rawURL string) error {
+	u, err := parseURL(rawURL)
+	if err != nil {
+		return err
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenAddress
+	}
+
+	if ip := net.ParseIP(host); ip != nil && !isAllowedIP(ip) {
+		return ErrForbiddenAddress
+	}
+
+	return nil
+}
+
+// parseURL parses rawURL, which must be an https url
+func parseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, ErrInsecureURL
+	}
+
+	return u, nil
+}
+
+// isAllowedIP tells if deliveries can be sent to ip, which must be a public unicast address.
+// It rejects the cloud metadata address 169.254.169.254, which is link-local.
+func isAllowedIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, prefix := range forbiddenPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkDialAddress is the control function of the dialer of the deliveries. It is called
+// with the resolved address of every connection, so a host name cannot be rebound to an
+// internal address between the check and the connection.
+func checkDialAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isAllowedIP(ip) {
+		return ErrForbiddenAddress
+	}
+
+	return nil
+}
+
+// newClient returns the default client of the sender. It only connects to public
+// addresses, ignores the proxy of the environment and doesn't follow redirects.
+func newClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: dialTimeout,
+		Control: checkDialAddress,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network string, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       defaultTimeout,
+		CheckRedirect: noRedirect,
+	}
+}
+
+// noRedirect makes the client return the redirect responses, which are
+// failed deliveries, instead of following them to another host
+func noRedirect(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateURL(t *testing.T) {
+	for _, rawURL := range []string{
+		"https://example.com/hooks",
+		"https://example.com:8443/hooks?token=1",
+		"https://93.184.216.34/hooks",
+	} {
+		assert.NoError(t, ValidateURL(rawURL), rawURL)
+	}
+
+	for _, rawURL := range []string{"http://example.com/hooks", "ftp://example.com", "https:///hooks", "example.com"} {
+		assert.ErrorIs(t, ValidateURL(rawURL), ErrInsecureURL, rawURL)
+	}
+
+	for _, rawURL := range []string{
+		"https://localhost/hooks",
+		"https://api.localhost/hooks",
+		"https://127.0.0.1/hooks",
+		"https://10.0.0.1/hooks",
+		"https://192.168.1.1/hooks",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]/hooks",
+		"https://[fd00:ec2::254]/hooks",
+		"https://0.0.0.0/hooks",
+	} {
+		assert.ErrorIs(t, ValidateURL(rawURL), ErrForbiddenAddress, rawURL)
+	}
+}
+
+func TestCheckDialAddress(t *testing.T) {
+	assert.NoError(t, checkDialAddress("tcp", "93.184.216.34:443", nil))
+	assert.NoError(t, checkDialAddress("tcp6", "[2606:2800:220:1:248:1893:25c8:1946]:443", nil))
+
+	for _, address := range []string{
+		"127.0.0.1:443",
+		"172.16.0.1:443",
+		"169.254.169.254:80",
+		"100.64.0.1:443",
+		"[::ffff:127.0.0.1]:443",
+		"[fe80::1]:443",
+		"[64:ff9b::a9fe:a9fe]:443",
+	} {
+		assert.ErrorIs(t, checkDialAddress("tcp", address, nil), ErrForbiddenAddress, address)
+	}
+}
+
+func TestIsAllowedIP(t *testing.T) {
+	assert.True(t, isAllowedIP(net.ParseIP("8.8.8.8")))
+	assert.False(t, isAllowedIP(net.ParseIP("224.0.0.1")))
+	assert.False(t, isAllowedIP(net.IP{1, 2}))
+}
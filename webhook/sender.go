@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/events"
+)
+
+// Headers of the deliveries
+const (
+	DeliveryIDHeader = "X-Webhook-Delivery"
+	EventTypeHeader  = "X-Webhook-Event"
+	TimestampHeader  = "X-Webhook-Timestamp"
+	SignatureHeader  = "X-Webhook-Signature"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// ErrErrorStatus is returned when an endpoint doesn't respond with a 2xx status
+var ErrErrorStatus = errors.New("webhook endpoint responded with an error status")
+
+// Delivery is an event to send to an endpoint
+type Delivery struct {
+	ID     int64
+	URL    string
+	Secret string
+	Event  events.Event
+}
+
+// Sender posts the signed deliveries to the endpoints
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender creates a sender using client, or when client is nil a default client with
+// a timeout, which only connects to public addresses. Redirects are never followed.
+func NewSender(client *http.Client) *Sender {
+	if client == nil {
+		client = newClient()
+	} else {
+		withoutRedirects := *client
+		withoutRedirects.CheckRedirect = noRedirect
+		client = &withoutRedirects
+	}
+
+	return &Sender{client: client}
+}
+
+// Send posts the event of the delivery as JSON, signed with the endpoint secret.
+// It returns the response status code, 0 when no response was received, and an
+// error unless the endpoint responded with a 2xx status.
+func (sender *Sender) Send(ctx context.Context, delivery Delivery) (int, error) {
+	// The addresses are checked by the dialer of the default client
+	_, err := parseURL(delivery.URL)
+	if err != nil {
+		return 0, err
+	}
+
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return 0, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(DeliveryIDHeader, strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set(EventTypeHeader, delivery.Event.Type)
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, body))
+
+	response, err := sender.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	// Drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return response.StatusCode, ErrErrorStatus
+	}
+
+	return response.StatusCode, nil
+}
@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/events"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestReceiver starts a stand-in endpoint which checks the signature
+// of the deliveries with secret and responds with status
+func newTestReceiver(t *testing.T, secret string, status int, received *[]events.Event) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), time.Unix(timestamp, 0), time.Minute)
+
+		if !VerifySignature(secret, timestamp, body, r.Header.Get(SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var event events.Event
+		assert.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, event.Type, r.Header.Get(EventTypeHeader))
+		*received = append(*received, event)
+
+		w.WriteHeader(status)
+	}))
+}
+
+func TestSenderSend(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	event := events.Event{
+		ID:          7,
+		Type:        "deposit.created",
+		AggregateID: 70,
+		Payload:     json.RawMessage(`{"id":70}`),
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+
+	testCases := []struct {
+		name           string
+		deliverySecret string
+		status         int
+		validateSend   func(statusCode int, err error, received []events.Event)
+	}{
+		{
+			name:           "OK",
+			deliverySecret: secret,
+			status:         http.StatusOK,
+			validateSend: func(statusCode int, err error, received []events.Event) {
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, statusCode)
+				if assert.Len(t, received, 1) {
+					assert.Equal(t, event.ID, received[0].ID)
+					assert.Equal(t, event.AggregateID, received[0].AggregateID)
+				}
+			},
+		},
+		{
+			name:           "WrongSecret",
+			deliverySecret: "whsec_wrong",
+			status:         http.StatusOK,
+			validateSend: func(statusCode int, err error, received []events.Event) {
+				assert.Error(t, err)
+				assert.Equal(t, http.StatusUnauthorized, statusCode)
+				assert.Empty(t, received)
+			},
+		},
+		{
+			name:           "ErrorStatus",
+			deliverySecret: secret,
+			status:         http.StatusInternalServerError,
+			validateSend: func(statusCode int, err error, received []events.Event) {
+				assert.Error(t, err)
+				assert.Equal(t, http.StatusInternalServerError, statusCode)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			var received []events.Event
+			receiver := newTestReceiver(t, secret, tc.status, &received)
+			defer receiver.Close()
+
+			sender := NewSender(receiver.Client())
+			statusCode, err := sender.Send(context.Background(), Delivery{
+				ID:     1,
+				URL:    receiver.URL,
+				Secret: tc.deliverySecret,
+				Event:  event,
+			})
+			tc.validateSend(statusCode, err, received)
+		})
+	}
+}
+
+func TestSenderSendInsecureURL(t *testing.T) {
+	var received []events.Event
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, events.Event{})
+	}))
+	defer receiver.Close()
+
+	statusCode, err := NewSender(receiver.Client()).Send(context.Background(), Delivery{ID: 1, URL: receiver.URL, Secret: "whsec_secret"})
+	assert.ErrorIs(t, err, ErrInsecureURL)
+	assert.Zero(t, statusCode)
+	assert.Empty(t, received)
+}
+
+func TestSenderSendForbiddenAddress(t *testing.T) {
+	// The default client never connects to the receiver, which listens on a loopback address
+	receiver := httptest.NewTLSServer(http.NotFoundHandler())
+	defer receiver.Close()
+
+	statusCode, err := NewSender(nil).Send(context.Background(), Delivery{ID: 1, URL: receiver.URL, Secret: "whsec_secret"})
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+	assert.Zero(t, statusCode)
+
+	statusCode, err = NewSender(nil).Send(context.Background(), Delivery{ID: 1, URL: "https://169.254.169.254/latest/meta-data", Secret: "whsec_secret"})
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+	assert.Zero(t, statusCode)
+}
+
+func TestSenderSendRedirect(t *testing.T) {
+	redirected := false
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/internal" {
+			redirected = true
+			return
+		}
+
+		http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+	}))
+	defer receiver.Close()
+
+	statusCode, err := NewSender(receiver.Client()).Send(context.Background(), Delivery{ID: 1, URL: receiver.URL, Secret: "whsec_secret"})
+	assert.ErrorIs(t, err, ErrErrorStatus)
+	assert.Equal(t, http.StatusTemporaryRedirect, statusCode)
+	assert.False(t, redirected)
+}
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+const (
+	secretPrefix    = "whsec_"
+	secretSize      = 32
+	signaturePrefix = "sha256="
+)
+
+// GenerateSecret returns a new random secret for an endpoint
+func GenerateSecret() (string, error) {
+	key := make([]byte, secretSize)
+	_, err := rand.Read(key)
+	if err != nil {
+		return "", err
+	}
+
+	return secretPrefix + hex.EncodeToString(key), nil
+}
+
+// Sign returns the signature of a delivery sent at timestamp, in unix seconds.
+// It is the hex HMAC-SHA256 with the endpoint secret of "<timestamp>.<body>",
+// so that a delivery cannot be replayed with another timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature tells if signature is the signature of body sent at timestamp,
+// it is what receivers are expected to check
+func VerifySignature(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateSecret(t *testing.T) {
+	secret1, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret1, secretPrefix))
+	assert.Len(t, secret1, len(secretPrefix)+2*secretSize)
+
+	secret2, err := GenerateSecret()
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret1, secret2)
+}
+
+func TestSignature(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	body := []byte(`{"id":1,"type":"transfer.created"}`)
+	timestamp := int64(1703232000)
+
+	signature := Sign(secret, timestamp, body)
+	assert.True(t, strings.HasPrefix(signature, signaturePrefix))
+	assert.Equal(t, signature, Sign(secret, timestamp, body))
+	assert.True(t, VerifySignature(secret, timestamp, body, signature))
+
+	otherSecret, err := GenerateSecret()
+	assert.NoError(t, err)
+
+	assert.False(t, VerifySignature(otherSecret, timestamp, body, signature))
+	assert.False(t, VerifySignature(secret, timestamp+1, body, signature))
+	assert.False(t, VerifySignature(secret, timestamp, []byte(`{"id":2}`), signature))
+	assert.False(t, VerifySignature(secret, timestamp, body, ""))
+}
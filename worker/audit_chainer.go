@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+)
+
+// AuditChainer periodically appends the pending audit events to the audit log.
+// The changes only record their events as pending, so that they don't wait for
+// each other on the chain, and several chainers can run against the same database.
+type AuditChainer struct {
+	store     db.Store
+	interval  time.Duration
+	batchSize int32
+	logger    *slog.Logger
+}
+
+// NewAuditChainer creates an audit chainer running every interval
+// and appending the pending events batchSize at a time
+func NewAuditChainer(store db.Store, interval time.Duration, batchSize int32, logger *slog.Logger) (*AuditChainer, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid audit chain interval: %s", interval)
+	}
+
+	if batchSize < 1 {
+		return nil, fmt.Errorf("invalid audit chain batch size: %d", batchSize)
+	}
+
+	return &AuditChainer{
+		store:     store,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+	}, nil
+}
+
+// Run appends the pending events every interval until ctx is done
+func (chainer *AuditChainer) Run(ctx context.Context) {
+	ticker := time.NewTicker(chainer.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx := runContext(ctx)
+			err := chainer.chain(runCtx)
+			if err != nil {
+				chainer.logger.ErrorContext(runCtx, "Failed to chain audit events", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// chain appends batches of pending events until none is left
+func (chainer *AuditChainer) chain(ctx context.Context) error {
+	for {
+		chained, err := chainer.store.ChainAuditEventsTx(ctx, chainer.batchSize)
+		if err != nil {
+			return err
+		}
+
+		if chained < int(chainer.batchSize) {
+			return nil
+		}
+	}
+}
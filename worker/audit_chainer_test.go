@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewAuditChainer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	_, err := NewAuditChainer(store, 0, 10, testLogger)
+	assert.Error(t, err)
+
+	_, err = NewAuditChainer(store, time.Second, 0, testLogger)
+	assert.Error(t, err)
+
+	chainer, err := NewAuditChainer(store, time.Second, 10, testLogger)
+	assert.NoError(t, err)
+	assert.NotNil(t, chainer)
+}
+
+func TestAuditChainerChain(t *testing.T) {
+	testCases := []struct {
+		name          string
+		buildStubFunc func(store *mockdb.MockStore)
+		validateError func(err error)
+	}{
+		{
+			name: "UntilBatchNotFull",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().ChainAuditEventsTx(gomock.Any(), gomock.Eq(int32(10))).Times(2).Return(10, nil),
+					store.EXPECT().ChainAuditEventsTx(gomock.Any(), gomock.Eq(int32(10))).Times(1).Return(3, nil),
+				)
+			},
+			validateError: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "Error",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().ChainAuditEventsTx(gomock.Any(), gomock.Any()).Times(1).Return(0, pgx.ErrTxClosed)
+			},
+			validateError: func(err error) {
+				assert.ErrorIs(t, err, pgx.ErrTxClosed)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			chainer, err := NewAuditChainer(store, time.Second, 10, testLogger)
+			assert.NoError(t, err)
+
+			err = chainer.chain(context.Background())
+			tc.validateError(err)
+		})
+	}
+}
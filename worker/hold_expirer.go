@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+)
+
+// HoldExpirer periodically marks the holds past their expiry as expired.
+// Expired holds already stop reserving money, this only settles their status.
+type HoldExpirer struct {
+	store    db.Store
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewHoldExpirer creates a hold expirer running every interval
+func NewHoldExpirer(store db.Store, interval time.Duration, logger *slog.Logger) (*HoldExpirer, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid hold expiry interval: %s", interval)
+	}
+
+	return &HoldExpirer{
+		store:    store,
+		interval: interval,
+		logger:   logger,
+	}, nil
+}
+
+// Run expires the stale holds every interval until ctx is done
+func (expirer *HoldExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(expirer.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx := runContext(ctx)
+			holds, err := expirer.store.ExpireHolds(runCtx)
+			if err != nil {
+				expirer.logger.ErrorContext(runCtx, "Failed to expire holds", slog.Any("error", err))
+				continue
+			}
+
+			if len(holds) > 0 {
+				expirer.logger.InfoContext(runCtx, "Expired holds", slog.Int("count", len(holds)))
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewHoldExpirer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	_, err := NewHoldExpirer(store, 0, testLogger)
+	assert.Error(t, err)
+
+	expirer, err := NewHoldExpirer(store, time.Minute, testLogger)
+	assert.NoError(t, err)
+	assert.NotNil(t, expirer)
+}
+
+func TestHoldExpirerRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A failure doesn't stop the expirer, it retries on the next tick
+	gomock.InOrder(
+		store.EXPECT().
+			ExpireHolds(gomock.Any()).
+			Times(1).
+			Return(nil, pgx.ErrTxClosed),
+		store.EXPECT().
+			ExpireHolds(gomock.Any()).
+			Times(1).
+			DoAndReturn(func(ctx context.Context) ([]db.Hold, error) {
+				cancel()
+				return []db.Hold{{ID: 1, Status: db.HoldStatusExpired}}, nil
+			}),
+		// The ticker can still fire once before the cancellation is seen
+		store.EXPECT().
+			ExpireHolds(gomock.Any()).
+			AnyTimes().
+			Return(nil, context.Canceled),
+	)
+
+	expirer, err := NewHoldExpirer(store, time.Millisecond, testLogger)
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		expirer.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("hold expirer did not stop")
+	}
+}
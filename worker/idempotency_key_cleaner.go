@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+)
+
+// IdempotencyKeyCleaner periodically deletes the idempotency keys past their expiry.
+// Expired keys are already ignored when a request is replayed, this only frees the storage.
+type IdempotencyKeyCleaner struct {
+	store    db.Store
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewIdempotencyKeyCleaner creates an idempotency key cleaner running every interval
+func NewIdempotencyKeyCleaner(store db.Store, interval time.Duration, logger *slog.Logger) (*IdempotencyKeyCleaner, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid idempotency key cleanup interval: %s", interval)
+	}
+
+	return &IdempotencyKeyCleaner{
+		store:    store,
+		interval: interval,
+		logger:   logger,
+	}, nil
+}
+
+// Run deletes the expired idempotency keys every interval until ctx is done
+func (cleaner *IdempotencyKeyCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(cleaner.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx := runContext(ctx)
+			err := cleaner.store.DeleteExpiredIdempotencyKeys(runCtx)
+			if err != nil {
+				cleaner.logger.ErrorContext(runCtx, "Failed to delete expired idempotency keys", slog.Any("error", err))
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewIdempotencyKeyCleaner(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	_, err := NewIdempotencyKeyCleaner(store, 0, testLogger)
+	assert.Error(t, err)
+
+	cleaner, err := NewIdempotencyKeyCleaner(store, time.Hour, testLogger)
+	assert.NoError(t, err)
+	assert.NotNil(t, cleaner)
+}
+
+func TestIdempotencyKeyCleanerRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A failure doesn't stop the cleaner, it retries on the next tick
+	gomock.InOrder(
+		store.EXPECT().
+			DeleteExpiredIdempotencyKeys(gomock.Any()).
+			Times(1).
+			Return(pgx.ErrTxClosed),
+		store.EXPECT().
+			DeleteExpiredIdempotencyKeys(gomock.Any()).
+			Times(1).
+			DoAndReturn(func(ctx context.Context) error {
+				cancel()
+				return nil
+			}),
+		// The ticker can still fire once before the cancellation is seen
+		store.EXPECT().
+			DeleteExpiredIdempotencyKeys(gomock.Any()).
+			AnyTimes().
+			Return(context.Canceled),
+	)
+
+	cleaner, err := NewIdempotencyKeyCleaner(store, time.Millisecond, testLogger)
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		cleaner.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("idempotency key cleaner did not stop")
+	}
+}
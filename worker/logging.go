@@ -0,0 +1,14 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/logging"
+	"github.com/google/uuid"
+)
+
+// runContext returns a copy of ctx carrying a new request id, so that the records
+// of a run of a worker, including the ones of its queries, can be correlated
+func runContext(ctx context.Context) context.Context {
+	return logging.WithRequestID(ctx, uuid.NewString())
+}
@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/logging"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// testLogger discards the records of the workers under test
+var testLogger, _ = logging.NewLogger(io.Discard, logging.FormatJSON, "")
+
+func TestRunContext(t *testing.T) {
+	ctx1 := runContext(context.Background())
+	ctx2 := runContext(context.Background())
+
+	assert.Len(t, logging.RequestIDFrom(ctx1), 36)
+	assert.NotEqual(t, logging.RequestIDFrom(ctx1), logging.RequestIDFrom(ctx2))
+}
+
+func TestWorkerLogsRequestID(t *testing.T) {
+	var buffer bytes.Buffer
+	logger, err := logging.NewLogger(&buffer, logging.FormatJSON, "info")
+	assert.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The queries of a run share the request id of its records
+	var requestID string
+	store.EXPECT().
+		DeleteExpiredIdempotencyKeys(gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context) error {
+			requestID = logging.RequestIDFrom(ctx)
+			cancel()
+			return pgx.ErrTxClosed
+		})
+	store.EXPECT().
+		DeleteExpiredIdempotencyKeys(gomock.Any()).
+		AnyTimes().
+		Return(context.Canceled)
+
+	cleaner, err := NewIdempotencyKeyCleaner(store, time.Millisecond, logger)
+	assert.NoError(t, err)
+
+	cleaner.Run(ctx)
+
+	var record map[string]any
+	err = json.NewDecoder(&buffer).Decode(&record)
+	assert.NoError(t, err)
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, pgx.ErrTxClosed.Error(), record["error"])
+	assert.NotEmpty(t, requestID)
+	assert.Equal(t, requestID, record[logging.RequestIDKey])
+}
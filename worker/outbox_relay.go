@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/events"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// outboxLease is how long a relay has to publish the events it leased
+	// before another relay can publish them again
+	outboxLease = time.Minute
+
+	outboxRetryBaseDelay = 5 * time.Second
+	outboxRetryMaxDelay  = time.Hour
+)
+
+// OutboxRelay periodically publishes the events of the outbox.
+// Events are leased before being published, so several relays can run against
+// the same database, and are published at least once: an event is published again
+// when its publication fails or when the relay stops before marking it published.
+type OutboxRelay struct {
+	store     db.Store
+	publisher events.EventPublisher
+	interval  time.Duration
+	batchSize int32
+	logger    *slog.Logger
+}
+
+// NewOutboxRelay creates a relay running every interval
+// and publishing at most batchSize events each time
+func NewOutboxRelay(
+	store db.Store,
+	publisher events.EventPublisher,
+	interval time.Duration,
+	batchSize int32,
+	logger *slog.Logger) (*OutboxRelay, error) {
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid outbox relay interval: %s", interval)
+	}
+
+	if batchSize < 1 {
+		return nil, fmt.Errorf("invalid outbox relay batch size: %d", batchSize)
+	}
+
+	return &OutboxRelay{
+		store:     store,
+		publisher: publisher,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+	}, nil
+}
+
+// Run publishes the pending events every interval until ctx is done
+func (relay *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relay.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx := runContext(ctx)
+			err := relay.relay(runCtx)
+			if err != nil {
+				relay.logger.ErrorContext(runCtx, "Failed to relay outbox events", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// relay publishes a batch of pending events, in creation order
+func (relay *OutboxRelay) relay(ctx context.Context) error {
+	outboxEvents, err := relay.store.LeaseOutboxEvents(ctx, db.LeaseOutboxEventsParams{
+		LeasedUntil: pgtype.Timestamptz{Time: time.Now().Add(outboxLease), Valid: true},
+		LimitCount:  relay.batchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(outboxEvents, func(i, j int) bool {
+		return outboxEvents[i].ID < outboxEvents[j].ID
+	})
+
+	for _, outboxEvent := range outboxEvents {
+		publishErr := relay.publisher.Publish(ctx, toEvent(outboxEvent))
+		if publishErr == nil {
+			_, err = relay.store.MarkOutboxEventPublished(ctx, outboxEvent.ID)
+		} else {
+			_, err = relay.store.MarkOutboxEventFailed(ctx, db.MarkOutboxEventFailedParams{
+				ID:            outboxEvent.ID,
+				LastError:     pgtype.Text{String: publishErr.Error(), Valid: true},
+				NextAttemptAt: pgtype.Timestamptz{Time: time.Now().Add(retryDelay(outboxEvent.Attempts, outboxRetryBaseDelay, outboxRetryMaxDelay)), Valid: true},
+			})
+		}
+
+		// The event stays leased, it is published again once the lease is over
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func toEvent(outboxEvent db.OutboxEvent) events.Event {
+	return events.Event{
+		ID:          outboxEvent.ID,
+		Type:        outboxEvent.EventType,
+		AggregateID: outboxEvent.AggregateID,
+		Payload:     outboxEvent.Payload,
+		CreatedAt:   outboxEvent.CreatedAt.Time,
+	}
+}
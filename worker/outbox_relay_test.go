@@ -0,0 +1,163 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/events"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+// failingPublisher fails to publish the events of the given ids
+type failingPublisher struct {
+	*events.MemoryPublisher
+	failingIDs map[int64]bool
+}
+
+func (publisher failingPublisher) Publish(ctx context.Context, event events.Event) error {
+	if publisher.failingIDs[event.ID] {
+		return errors.New("receiver unavailable")
+	}
+
+	return publisher.MemoryPublisher.Publish(ctx, event)
+}
+
+func outboxEvent(id int64, attempts int32) db.OutboxEvent {
+	return db.OutboxEvent{
+		ID:          id,
+		EventType:   db.EventTransferCreated,
+		AggregateID: id * 10,
+		Payload:     []byte(`{"id":1}`),
+		Attempts:    attempts,
+	}
+}
+
+func TestNewOutboxRelay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	publisher := events.NewMemoryPublisher()
+
+	_, err := NewOutboxRelay(store, publisher, 0, 10, testLogger)
+	assert.Error(t, err)
+
+	_, err = NewOutboxRelay(store, publisher, time.Second, 0, testLogger)
+	assert.Error(t, err)
+
+	relay, err := NewOutboxRelay(store, publisher, time.Second, 10, testLogger)
+	assert.NoError(t, err)
+	assert.NotNil(t, relay)
+}
+
+func TestOutboxRelayRelay(t *testing.T) {
+	testCases := []struct {
+		name          string
+		failingIDs    map[int64]bool
+		buildStubFunc func(store *mockdb.MockStore)
+		validateRelay func(publisher failingPublisher, err error)
+	}{
+		{
+			name: "PublishedInOrder",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					LeaseOutboxEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.OutboxEvent{outboxEvent(2, 0), outboxEvent(1, 0)}, nil)
+				store.EXPECT().MarkOutboxEventPublished(gomock.Any(), gomock.Eq(int64(1))).Times(1)
+				store.EXPECT().MarkOutboxEventPublished(gomock.Any(), gomock.Eq(int64(2))).Times(1)
+				store.EXPECT().MarkOutboxEventFailed(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateRelay: func(publisher failingPublisher, err error) {
+				assert.NoError(t, err)
+
+				published := publisher.Events()
+				if assert.Len(t, published, 2) {
+					assert.Equal(t, int64(1), published[0].ID)
+					assert.Equal(t, int64(2), published[1].ID)
+					assert.Equal(t, db.EventTransferCreated, published[0].Type)
+					assert.Equal(t, int64(10), published[0].AggregateID)
+				}
+			},
+		},
+		{
+			name:       "FailureIsRetried",
+			failingIDs: map[int64]bool{1: true},
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					LeaseOutboxEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.OutboxEvent{outboxEvent(1, 3), outboxEvent(2, 0)}, nil)
+				store.EXPECT().
+					MarkOutboxEventFailed(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.MarkOutboxEventFailedParams) (db.OutboxEvent, error) {
+						assert.Equal(t, int64(1), arg.ID)
+						assert.Equal(t, "receiver unavailable", arg.LastError.String)
+						assert.WithinDuration(t, time.Now().Add(retryDelay(3, outboxRetryBaseDelay, outboxRetryMaxDelay)), arg.NextAttemptAt.Time, time.Second)
+						return outboxEvent(1, 4), nil
+					})
+				store.EXPECT().MarkOutboxEventPublished(gomock.Any(), gomock.Eq(int64(2))).Times(1)
+			},
+			validateRelay: func(publisher failingPublisher, err error) {
+				assert.NoError(t, err)
+				assert.Len(t, publisher.Events(), 1)
+			},
+		},
+		{
+			name: "LeaseError",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					LeaseOutboxEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, pgx.ErrTxClosed)
+			},
+			validateRelay: func(publisher failingPublisher, err error) {
+				assert.ErrorIs(t, err, pgx.ErrTxClosed)
+				assert.Empty(t, publisher.Events())
+			},
+		},
+		{
+			name: "MarkError",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					LeaseOutboxEvents(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.OutboxEvent{outboxEvent(1, 0), outboxEvent(2, 0)}, nil)
+				store.EXPECT().
+					MarkOutboxEventPublished(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.OutboxEvent{}, pgx.ErrTxClosed)
+			},
+			validateRelay: func(publisher failingPublisher, err error) {
+				assert.ErrorIs(t, err, pgx.ErrTxClosed)
+				assert.Len(t, publisher.Events(), 1)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			publisher := failingPublisher{
+				MemoryPublisher: events.NewMemoryPublisher(),
+				failingIDs:      tc.failingIDs,
+			}
+
+			relay, err := NewOutboxRelay(store, publisher, time.Second, 10, testLogger)
+			assert.NoError(t, err)
+
+			err = relay.relay(context.Background())
+			tc.validateRelay(publisher, err)
+		})
+	}
+}
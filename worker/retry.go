@@ -0,0 +1,14 @@
+package worker
+
+import "time"
+
+// retryDelay doubles the delay before the next attempt
+// after each failed attempt, from baseDelay up to maxDelay
+func retryDelay(attempts int32, baseDelay time.Duration, maxDelay time.Duration) time.Duration {
+	delay := baseDelay
+	for i := int32(0); i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxDelay)
+}
@@ -0,0 +1,16 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Second, retryDelay(0, time.Second, time.Minute))
+	assert.Equal(t, 2*time.Second, retryDelay(1, time.Second, time.Minute))
+	assert.Equal(t, 8*time.Second, retryDelay(3, time.Second, time.Minute))
+	assert.Equal(t, time.Minute, retryDelay(6, time.Second, time.Minute))
+	assert.Equal(t, time.Minute, retryDelay(100, time.Second, time.Minute))
+}
@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/jackc/pgx/v5"
+)
+
+// TransferScheduler periodically executes the scheduled transfers which are due.
+// Several schedulers can run against the same database, each due occurrence
+// is claimed and executed by only one of them.
+type TransferScheduler struct {
+	store     db.Store
+	interval  time.Duration
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewTransferScheduler creates a scheduler running every interval
+// and executing at most batchSize scheduled transfers each time
+func NewTransferScheduler(store db.Store, interval time.Duration, batchSize int, logger *slog.Logger) (*TransferScheduler, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid scheduler interval: %s", interval)
+	}
+
+	if batchSize < 1 {
+		return nil, fmt.Errorf("invalid scheduler batch size: %d", batchSize)
+	}
+
+	return &TransferScheduler{
+		store:     store,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+	}, nil
+}
+
+// Run executes the due scheduled transfers every interval until ctx is done
+func (scheduler *TransferScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(scheduler.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			scheduler.runDue(runContext(ctx))
+		}
+	}
+}
+
+// runDue executes due scheduled transfers one transaction at a time,
+// until none is due or the batch is done
+func (scheduler *TransferScheduler) runDue(ctx context.Context) {
+	for i := 0; i < scheduler.batchSize; i++ {
+		result, err := scheduler.store.RunDueScheduledTransferTx(ctx)
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				scheduler.logger.ErrorContext(ctx, "Failed to run scheduled transfer", slog.Any("error", err))
+			}
+
+			return
+		}
+
+		if result.Run.Error.Valid {
+			scheduler.logger.WarnContext(
+				ctx,
+				"Scheduled transfer failed",
+				slog.Int64("scheduled_transfer_id", result.ScheduledTransfer.ID),
+				slog.String("error", result.Run.Error.String))
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewTransferScheduler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	_, err := NewTransferScheduler(store, 0, 10, testLogger)
+	assert.Error(t, err)
+
+	_, err = NewTransferScheduler(store, time.Minute, 0, testLogger)
+	assert.Error(t, err)
+
+	scheduler, err := NewTransferScheduler(store, time.Minute, 10, testLogger)
+	assert.NoError(t, err)
+	assert.NotNil(t, scheduler)
+}
+
+func TestTransferSchedulerRunDue(t *testing.T) {
+	failedRun := db.RunScheduledTransferTxResult{
+		ScheduledTransfer: db.ScheduledTransfer{ID: 2},
+		Run:               db.ScheduledTransferRun{Error: pgtype.Text{String: "insufficient funds", Valid: true}},
+	}
+
+	testCases := []struct {
+		name          string
+		batchSize     int
+		buildStubFunc func(store *mockdb.MockStore)
+	}{
+		{
+			name:      "UntilNoneDue",
+			batchSize: 10,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().
+						RunDueScheduledTransferTx(gomock.Any()).
+						Times(1).
+						Return(db.RunScheduledTransferTxResult{ScheduledTransfer: db.ScheduledTransfer{ID: 1}}, nil),
+					store.EXPECT().
+						RunDueScheduledTransferTx(gomock.Any()).
+						Times(1).
+						Return(failedRun, nil),
+					store.EXPECT().
+						RunDueScheduledTransferTx(gomock.Any()).
+						Times(1).
+						Return(db.RunScheduledTransferTxResult{}, pgx.ErrNoRows),
+				)
+			},
+		},
+		{
+			name:      "BatchSize",
+			batchSize: 2,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RunDueScheduledTransferTx(gomock.Any()).
+					Times(2).
+					Return(db.RunScheduledTransferTxResult{ScheduledTransfer: db.ScheduledTransfer{ID: 1}}, nil)
+			},
+		},
+		{
+			name:      "StopsOnError",
+			batchSize: 10,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RunDueScheduledTransferTx(gomock.Any()).
+					Times(1).
+					Return(db.RunScheduledTransferTxResult{}, pgx.ErrTxClosed)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			scheduler, err := NewTransferScheduler(store, time.Minute, tc.batchSize, testLogger)
+			assert.NoError(t, err)
+
+			scheduler.runDue(context.Background())
+		})
+	}
+}
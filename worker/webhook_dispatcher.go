@@ -0,0 +1,209 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/webhook"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// webhookLease is how long a dispatcher has to make the deliveries it leased
+	// before another dispatcher can make them again
+	webhookLease = time.Minute
+
+	// webhookBatchTimeout bounds the time spent sending a batch, well under the lease,
+	// and webhookSendTimeout the time spent sending each delivery of the batch
+	webhookBatchTimeout = webhookLease / 2
+	webhookSendTimeout  = 10 * time.Second
+
+	webhookRetryBaseDelay = 10 * time.Second
+	webhookRetryMaxDelay  = 6 * time.Hour
+)
+
+var errWebhookEndpointDisabled = errors.New("webhook endpoint is disabled")
+
+// WebhookDispatcher periodically sends the pending webhook deliveries.
+// A failed delivery is retried with an exponential backoff, and is dead
+// once maxAttempts attempts failed.
+type WebhookDispatcher struct {
+	store       db.Store
+	sender      *webhook.Sender
+	interval    time.Duration
+	batchSize   int32
+	maxAttempts int32
+	logger      *slog.Logger
+}
+
+// NewWebhookDispatcher creates a dispatcher running every interval
+// and sending at most batchSize deliveries each time
+func NewWebhookDispatcher(
+	store db.Store,
+	sender *webhook.Sender,
+	interval time.Duration,
+	batchSize int32,
+	maxAttempts int32,
+	logger *slog.Logger) (*WebhookDispatcher, error) {
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid webhook dispatch interval: %s", interval)
+	}
+
+	if batchSize < 1 {
+		return nil, fmt.Errorf("invalid webhook batch size: %d", batchSize)
+	}
+
+	if maxAttempts < 1 {
+		return nil, fmt.Errorf("invalid webhook max attempts: %d", maxAttempts)
+	}
+
+	return &WebhookDispatcher{
+		store:       store,
+		sender:      sender,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		logger:      logger,
+	}, nil
+}
+
+// Run sends the pending deliveries every interval until ctx is done
+func (dispatcher *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runCtx := runContext(ctx)
+			err := dispatcher.dispatch(runCtx)
+			if err != nil {
+				dispatcher.logger.ErrorContext(runCtx, "Failed to dispatch webhook deliveries", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// dispatch sends a batch of pending deliveries concurrently, so that
+// a slow endpoint doesn't delay the other deliveries past their lease
+func (dispatcher *WebhookDispatcher) dispatch(ctx context.Context) error {
+	deliveries, err := dispatcher.store.LeaseWebhookDeliveries(ctx, db.LeaseWebhookDeliveriesParams{
+		LeasedUntil: pgtype.Timestamptz{Time: time.Now().Add(webhookLease), Valid: true},
+		LimitCount:  dispatcher.batchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	batchCtx, cancel := context.WithTimeout(ctx, webhookBatchTimeout)
+	defer cancel()
+
+	errs := make([]error, len(deliveries))
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// A delivery which failed to be recorded stays leased,
+			// it is made again once the lease is over
+			errs[i] = dispatcher.deliver(ctx, batchCtx, deliveries[i])
+		}(i)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// deliver makes an attempt of the delivery, sent before batchCtx is done, and records its outcome
+func (dispatcher *WebhookDispatcher) deliver(ctx context.Context, batchCtx context.Context, delivery db.WebhookDelivery) error {
+	endpoint, err := dispatcher.store.GetWebhookEndpoint(ctx, delivery.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	var statusCode int
+	var sendErr error
+	if endpoint.IsActive {
+		outboxEvent, err := dispatcher.store.GetOutboxEvent(ctx, delivery.EventID)
+		if err != nil {
+			return err
+		}
+
+		sendCtx, cancel := context.WithTimeout(batchCtx, webhookSendTimeout)
+		statusCode, sendErr = dispatcher.sender.Send(sendCtx, webhook.Delivery{
+			ID:     delivery.ID,
+			URL:    endpoint.Url,
+			Secret: endpoint.Secret,
+			Event:  toEvent(outboxEvent),
+		})
+		cancel()
+
+		if sendErr != nil {
+			dispatcher.logger.WarnContext(
+				ctx,
+				"Failed to send webhook delivery",
+				slog.Int64("delivery_id", delivery.ID),
+				slog.Any("error", sendErr))
+		}
+	} else {
+		sendErr = errWebhookEndpointDisabled
+	}
+
+	now := time.Now()
+	arg := db.UpdateWebhookDeliveryAttemptParams{
+		ID:             delivery.ID,
+		Status:         db.WebhookDeliveryStatusSucceeded,
+		NextAttemptAt:  pgtype.Timestamptz{Time: now, Valid: true},
+		LastStatusCode: pgtype.Int4{Int32: int32(statusCode), Valid: statusCode != 0},
+	}
+
+	switch {
+	case sendErr == nil:
+		arg.DeliveredAt = pgtype.Timestamptz{Time: now, Valid: true}
+	case !endpoint.IsActive || delivery.Attempts+1 >= dispatcher.maxAttempts:
+		arg.Status = db.WebhookDeliveryStatusDead
+		arg.LastError = pgtype.Text{String: deliveryError(sendErr), Valid: true}
+	default:
+		arg.Status = db.WebhookDeliveryStatusPending
+		arg.LastError = pgtype.Text{String: deliveryError(sendErr), Valid: true}
+		arg.NextAttemptAt.Time = now.Add(retryDelay(delivery.Attempts, webhookRetryBaseDelay, webhookRetryMaxDelay))
+	}
+
+	_, err = dispatcher.store.UpdateWebhookDeliveryAttempt(ctx, arg)
+	return err
+}
+
+// deliveryErrors are the errors recorded as they are for a failed delivery
+var deliveryErrors = []error{
+	errWebhookEndpointDisabled,
+	webhook.ErrErrorStatus,
+	webhook.ErrInsecureURL,
+	webhook.ErrForbiddenAddress,
+}
+
+// deliveryError returns the error recorded for a failed delivery, which the owner
+// of the endpoint can read. The errors of the transport are not recorded as they
+// are, as they describe the network of the server.
+func deliveryError(err error) string {
+	for _, deliveryErr := range deliveryErrors {
+		if errors.Is(err, deliveryErr) {
+			return deliveryErr.Error()
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || os.IsTimeout(err) {
+		return "webhook endpoint timed out"
+	}
+
+	return "webhook endpoint is unreachable"
+}
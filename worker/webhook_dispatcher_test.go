@@ -0,0 +1,293 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	mockdb "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/mock"
+	db "github.com/ernitingarg/golang-postgres-sqlc-bank-backend/db/sqlc"
+	"github.com/ernitingarg/golang-postgres-sqlc-bank-backend/webhook"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewWebhookDispatcher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	sender := webhook.NewSender(nil)
+
+	_, err := NewWebhookDispatcher(store, sender, 0, 10, 5, testLogger)
+	assert.Error(t, err)
+
+	_, err = NewWebhookDispatcher(store, sender, time.Second, 0, 5, testLogger)
+	assert.Error(t, err)
+
+	_, err = NewWebhookDispatcher(store, sender, time.Second, 10, 0, testLogger)
+	assert.Error(t, err)
+
+	dispatcher, err := NewWebhookDispatcher(store, sender, time.Second, 10, 5, testLogger)
+	assert.NoError(t, err)
+	assert.NotNil(t, dispatcher)
+}
+
+func TestWebhookDispatcherDispatch(t *testing.T) {
+	secret, err := webhook.GenerateSecret()
+	assert.NoError(t, err)
+
+	// The stand-in receiver accepts the correctly signed deliveries when up
+	receiverUp := true
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(webhook.TimestampHeader), 10, 64)
+		assert.NoError(t, err)
+
+		if !webhook.VerifySignature(secret, timestamp, body, r.Header.Get(webhook.SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if !receiverUp {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	endpoint := db.WebhookEndpoint{
+		ID:         1,
+		Owner:      "owner",
+		Url:        receiver.URL,
+		Secret:     secret,
+		EventTypes: []string{db.EventTransferCreated},
+		IsActive:   true,
+	}
+
+	disabledEndpoint := endpoint
+	disabledEndpoint.IsActive = false
+
+	delivery := db.WebhookDelivery{
+		ID:         10,
+		EndpointID: endpoint.ID,
+		EventID:    100,
+		Status:     db.WebhookDeliveryStatusPending,
+	}
+
+	outboxEvent := db.OutboxEvent{
+		ID:          delivery.EventID,
+		EventType:   db.EventTransferCreated,
+		AggregateID: 1000,
+		Payload:     []byte(`{"id":1000}`),
+	}
+
+	testCases := []struct {
+		name          string
+		receiverUp    bool
+		buildStubFunc func(store *mockdb.MockStore)
+		validateError func(err error)
+	}{
+		{
+			name:       "Succeeded",
+			receiverUp: true,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					LeaseWebhookDeliveries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.WebhookDelivery{delivery}, nil)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().GetOutboxEvent(gomock.Any(), gomock.Eq(outboxEvent.ID)).Times(1).Return(outboxEvent, nil)
+				store.EXPECT().
+					UpdateWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.UpdateWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+						assert.Equal(t, delivery.ID, arg.ID)
+						assert.Equal(t, db.WebhookDeliveryStatusSucceeded, arg.Status)
+						assert.Equal(t, int32(http.StatusOK), arg.LastStatusCode.Int32)
+						assert.True(t, arg.DeliveredAt.Valid)
+						assert.False(t, arg.LastError.Valid)
+						return db.WebhookDelivery{}, nil
+					})
+			},
+			validateError: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:       "RetriedWithBackoff",
+			receiverUp: false,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				failedDelivery := delivery
+				failedDelivery.Attempts = 2
+
+				store.EXPECT().
+					LeaseWebhookDeliveries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.WebhookDelivery{failedDelivery}, nil)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().GetOutboxEvent(gomock.Any(), gomock.Eq(outboxEvent.ID)).Times(1).Return(outboxEvent, nil)
+				store.EXPECT().
+					UpdateWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.UpdateWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+						assert.Equal(t, db.WebhookDeliveryStatusPending, arg.Status)
+						assert.Equal(t, int32(http.StatusServiceUnavailable), arg.LastStatusCode.Int32)
+						assert.Equal(t, webhook.ErrErrorStatus.Error(), arg.LastError.String)
+						assert.False(t, arg.DeliveredAt.Valid)
+
+						expectedNextAttempt := time.Now().Add(retryDelay(2, webhookRetryBaseDelay, webhookRetryMaxDelay))
+						assert.WithinDuration(t, expectedNextAttempt, arg.NextAttemptAt.Time, time.Second)
+						return db.WebhookDelivery{}, nil
+					})
+			},
+			validateError: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:       "DeadAfterMaxAttempts",
+			receiverUp: false,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				failedDelivery := delivery
+				failedDelivery.Attempts = 4
+
+				store.EXPECT().
+					LeaseWebhookDeliveries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.WebhookDelivery{failedDelivery}, nil)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(endpoint, nil)
+				store.EXPECT().GetOutboxEvent(gomock.Any(), gomock.Eq(outboxEvent.ID)).Times(1).Return(outboxEvent, nil)
+				store.EXPECT().
+					UpdateWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.UpdateWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+						assert.Equal(t, db.WebhookDeliveryStatusDead, arg.Status)
+						assert.True(t, arg.LastError.Valid)
+						return db.WebhookDelivery{}, nil
+					})
+			},
+			validateError: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name:       "DisabledEndpoint",
+			receiverUp: true,
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					LeaseWebhookDeliveries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.WebhookDelivery{delivery}, nil)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(1).Return(disabledEndpoint, nil)
+				store.EXPECT().GetOutboxEvent(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().
+					UpdateWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.UpdateWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+						assert.Equal(t, db.WebhookDeliveryStatusDead, arg.Status)
+						assert.Equal(t, errWebhookEndpointDisabled.Error(), arg.LastError.String)
+						assert.False(t, arg.LastStatusCode.Valid)
+						return db.WebhookDelivery{}, nil
+					})
+			},
+			validateError: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "LeaseError",
+			buildStubFunc: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					LeaseWebhookDeliveries(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, pgx.ErrTxClosed)
+				store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Any()).Times(0)
+			},
+			validateError: func(err error) {
+				assert.ErrorIs(t, err, pgx.ErrTxClosed)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubFunc(store)
+
+			receiverUp = tc.receiverUp
+
+			dispatcher, err := NewWebhookDispatcher(store, webhook.NewSender(receiver.Client()), time.Second, 10, 5, testLogger)
+			assert.NoError(t, err)
+
+			err = dispatcher.dispatch(context.Background())
+			tc.validateError(err)
+		})
+	}
+}
+
+func TestWebhookDispatcherDispatchConcurrently(t *testing.T) {
+	// Each delivery takes a while, the batch is only sent in time concurrently
+	const deliveryCount = 5
+	const delay = 200 * time.Millisecond
+
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	endpoint := db.WebhookEndpoint{ID: 1, Url: receiver.URL, Secret: "whsec_secret", IsActive: true}
+
+	deliveries := make([]db.WebhookDelivery, deliveryCount)
+	for i := range deliveries {
+		deliveries[i] = db.WebhookDelivery{ID: int64(i + 1), EndpointID: endpoint.ID, EventID: int64(i + 1)}
+	}
+
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().LeaseWebhookDeliveries(gomock.Any(), gomock.Any()).Times(1).Return(deliveries, nil)
+	store.EXPECT().GetWebhookEndpoint(gomock.Any(), gomock.Eq(endpoint.ID)).Times(deliveryCount).Return(endpoint, nil)
+	store.EXPECT().GetOutboxEvent(gomock.Any(), gomock.Any()).Times(deliveryCount).Return(db.OutboxEvent{Payload: []byte(`{}`)}, nil)
+	store.EXPECT().
+		UpdateWebhookDeliveryAttempt(gomock.Any(), gomock.Any()).
+		Times(deliveryCount).
+		DoAndReturn(func(ctx context.Context, arg db.UpdateWebhookDeliveryAttemptParams) (db.WebhookDelivery, error) {
+			assert.Equal(t, db.WebhookDeliveryStatusSucceeded, arg.Status)
+			return db.WebhookDelivery{}, nil
+		})
+
+	dispatcher, err := NewWebhookDispatcher(store, webhook.NewSender(receiver.Client()), time.Second, deliveryCount, 5, testLogger)
+	assert.NoError(t, err)
+
+	start := time.Now()
+	err = dispatcher.dispatch(context.Background())
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), deliveryCount*delay)
+}
+
+func TestDeliveryError(t *testing.T) {
+	assert.Equal(t, webhook.ErrErrorStatus.Error(), deliveryError(webhook.ErrErrorStatus))
+	assert.Equal(t, webhook.ErrForbiddenAddress.Error(), deliveryError(&url.Error{
+		Op:  "Post",
+		URL: "https://internal.example.com",
+		Err: &net.OpError{Op: "dial", Net: "tcp", Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 443}, Err: webhook.ErrForbiddenAddress},
+	}))
+	assert.Equal(t, "webhook endpoint timed out", deliveryError(context.DeadlineExceeded))
+
+	connErr := errors.New("dial tcp 10.0.0.1:443: connect: connection refused")
+	assert.Equal(t, "webhook endpoint is unreachable", deliveryError(connErr))
+}